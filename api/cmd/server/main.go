@@ -7,13 +7,16 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/brandon-relentnet/nationcam/api/internal/cache"
 	"github.com/brandon-relentnet/nationcam/api/internal/config"
 	"github.com/brandon-relentnet/nationcam/api/internal/handler"
+	"github.com/brandon-relentnet/nationcam/api/internal/jobs"
 	"github.com/brandon-relentnet/nationcam/api/internal/middleware"
+	"github.com/brandon-relentnet/nationcam/api/internal/migrate"
 	"github.com/brandon-relentnet/nationcam/api/internal/restreamer"
 	dbschema "github.com/brandon-relentnet/nationcam/api/sql"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -25,6 +28,15 @@ func main() {
 		Level: slog.LevelInfo,
 	})))
 
+	// "server migrate <up|down|status>" manages the schema without starting the API.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			slog.Error("migrate failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(); err != nil {
 		slog.Error("fatal", "error", err)
 		os.Exit(1)
@@ -57,11 +69,16 @@ func run() error {
 	}
 	slog.Info("postgres connected")
 
-	// ── Run schema migration (idempotent — safe on every startup) ──
-	if _, err := pool.Exec(ctx, dbschema.Schema); err != nil {
+	// ── Apply pending migrations (advisory-locked across replicas) ──
+	migrator, err := migrate.New(pool, dbschema.Migrations, "migrations")
+	if err != nil {
+		return err
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil {
 		return fmt.Errorf("migrate schema: %w", err)
 	}
-	slog.Info("schema migration complete")
+	slog.Info("schema migration complete", "applied", len(applied))
 
 	// ── Connect to Redis ───────────────────────────────────────────
 	redisCache, err := cache.New(ctx, cfg.RedisURL)
@@ -81,8 +98,16 @@ func run() error {
 		slog.Info("restreamer client configured", "url", cfg.RestreamerURL)
 	}
 
+	// ── Background jobs ────────────────────────────────────────────
+	go jobs.PurgeTrash(ctx, pool, cfg.TrashRetention, time.Hour)
+	slog.Info("trash purge scheduled", "retention", cfg.TrashRetention.String())
+	go jobs.PublishScheduled(ctx, pool, redisCache, time.Minute)
+	go jobs.CheckStreams(ctx, pool, redisCache, cfg.StreamCheckInterval)
+	slog.Info("stream health check scheduled", "interval", cfg.StreamCheckInterval.String())
+
 	// ── Build router ───────────────────────────────────────────────
-	router := handler.NewRouter(pool, redisCache, auth, cfg.CORSOrigins, rc, cfg.StreamerAPIKey)
+	clients := middleware.NewClientHasher(cfg.ClientHashSecret, cfg.TrustedProxies)
+	router := handler.NewRouter(pool, redisCache, auth, clients, cfg.ReportThreshold, cfg.CORSOrigins, rc, cfg.StreamerAPIKey)
 
 	// ── HTTP server ────────────────────────────────────────────────
 	srv := &http.Server{
@@ -119,3 +144,72 @@ func run() error {
 	slog.Info("shutting down server")
 	return srv.Shutdown(shutdownCtx)
 }
+
+// runMigrate implements the migrate subcommand:
+//
+//	server migrate up           apply all pending migrations
+//	server migrate down [n]     revert the last n migrations (default 1)
+//	server migrate status       list migrations and when they were applied
+func runMigrate(args []string) error {
+	ctx := context.Background()
+
+	if len(args) == 0 {
+		return fmt.Errorf("usage: server migrate <up|down [n]|status>")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(pool, dbschema.Migrations, "migrations")
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migration(s)\n", len(applied))
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reverted %d migration(s)\n", len(reverted))
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d  %-40s  %s\n", s.Version, s.Name, applied)
+		}
+
+	default:
+		return fmt.Errorf("unknown migrate command %q (want up, down or status)", args[0])
+	}
+	return nil
+}
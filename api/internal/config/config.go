@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config holds all application configuration loaded from environment variables.
@@ -14,6 +17,27 @@ type Config struct {
 	LogtoEndpoint string
 	CORSOrigins   []string
 
+	// TrashRetention is how long soft-deleted rows stay restorable before
+	// the purge job removes them for good.
+	TrashRetention time.Duration
+
+	// StreamCheckInterval is how often the health check probes video
+	// streams to flip them between published and offline.
+	StreamCheckInterval time.Duration
+
+	// ClientHashSecret keys the pseudonyms of anonymous visitors (public
+	// submissions and reports). Optional; without it they reset on restart.
+	ClientHashSecret string
+
+	// TrustedProxies are the reverse proxies in front of the API whose
+	// X-Forwarded-For identifies visitors. Empty trusts none.
+	TrustedProxies []netip.Prefix
+
+	// ReportThreshold is how many distinct viewers must report a published
+	// camera before it is put into maintenance: 0 disables auto-flagging,
+	// otherwise at least 2.
+	ReportThreshold int
+
 	// Restreamer (optional — empty RestreamerURL disables stream management).
 	RestreamerURL  string
 	RestreamerUser string
@@ -36,6 +60,30 @@ func Load() (*Config, error) {
 		}
 	}
 
+	retention, err := envDuration("TRASH_RETENTION", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	streamCheck, err := envDuration("STREAM_CHECK_INTERVAL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	proxies, err := envPrefixes("TRUSTED_PROXIES")
+	if err != nil {
+		return nil, err
+	}
+
+	reportThreshold, err := envInt("REPORT_MAINTENANCE_THRESHOLD", 0)
+	if err != nil {
+		return nil, err
+	}
+	if reportThreshold == 1 {
+		// One report is one address: a single visitor could take a camera down.
+		return nil, fmt.Errorf("REPORT_MAINTENANCE_THRESHOLD must be 0 (off) or at least 2")
+	}
+
 	return &Config{
 		Port:          envOr("PORT", "8080"),
 		DatabaseURL:   dbURL,
@@ -43,6 +91,13 @@ func Load() (*Config, error) {
 		LogtoEndpoint: envOr("LOGTO_ENDPOINT", "http://localhost:3301"),
 		CORSOrigins:   corsList,
 
+		TrashRetention:      retention,
+		StreamCheckInterval: streamCheck,
+
+		ClientHashSecret: os.Getenv("CLIENT_HASH_SECRET"),
+		TrustedProxies:   proxies,
+		ReportThreshold:  reportThreshold,
+
 		RestreamerURL:  os.Getenv("RESTREAMER_URL"),
 		RestreamerUser: os.Getenv("RESTREAMER_USER"),
 		RestreamerPass: os.Getenv("RESTREAMER_PASS"),
@@ -56,3 +111,51 @@ func envOr(key, fallback string) string {
 	}
 	return fallback
 }
+
+// envDuration parses a Go duration (e.g. "720h") from key, or returns fallback if unset.
+func envDuration(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration (e.g. 720h), got %q", key, v)
+	}
+	return d, nil
+}
+
+// envInt parses a non-negative integer from key, or returns fallback if unset.
+func envInt(key string, fallback int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", key, v)
+	}
+	return n, nil
+}
+
+// envPrefixes parses a comma-separated list of CIDR ranges or single
+// addresses (e.g. "172.16.0.0/12,10.0.0.1") from key.
+func envPrefixes(key string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if p, err := netip.ParsePrefix(v); err == nil {
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s must list CIDR ranges or IP addresses, got %q", key, v)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,187 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: audit.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const countAuditEvents = `-- name: CountAuditEvents :one
+SELECT COUNT(*)::int
+FROM audit_events
+WHERE ($1::text IS NULL OR actor = $1::text)
+  AND ($2::text IS NULL OR action = $2::text)
+  AND ($3::text IS NULL OR entity_type = $3::text)
+  AND ($4::text IS NULL OR entity_id = $4::text)
+  AND ($5::timestamptz IS NULL OR created_at >= $5::timestamptz)
+  AND ($6::timestamptz IS NULL OR created_at < $6::timestamptz)
+`
+
+type CountAuditEventsParams struct {
+	Actor      *string    `json:"actor"`
+	Action     *string    `json:"action"`
+	EntityType *string    `json:"entity_type"`
+	EntityID   *string    `json:"entity_id"`
+	Since      *time.Time `json:"since"`
+	Until      *time.Time `json:"until"`
+}
+
+func (q *Queries) CountAuditEvents(ctx context.Context, arg CountAuditEventsParams) (int32, error) {
+	row := q.db.QueryRow(ctx, countAuditEvents,
+		arg.Actor,
+		arg.Action,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Since,
+		arg.Until,
+	)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createAuditEvent = `-- name: CreateAuditEvent :one
+INSERT INTO audit_events (actor, actor_type, action, entity_type, entity_id, before, after, reverts_audit_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING audit_id, actor, actor_type, action, entity_type, entity_id, before, after, reverts_audit_id, created_at
+`
+
+type CreateAuditEventParams struct {
+	Actor          string          `json:"actor"`
+	ActorType      string          `json:"actor_type"`
+	Action         string          `json:"action"`
+	EntityType     string          `json:"entity_type"`
+	EntityID       string          `json:"entity_id"`
+	Before         json.RawMessage `json:"before"`
+	After          json.RawMessage `json:"after"`
+	RevertsAuditID *int64          `json:"reverts_audit_id"`
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error) {
+	row := q.db.QueryRow(ctx, createAuditEvent,
+		arg.Actor,
+		arg.ActorType,
+		arg.Action,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Before,
+		arg.After,
+		arg.RevertsAuditID,
+	)
+	var i AuditEvent
+	err := row.Scan(
+		&i.AuditID,
+		&i.Actor,
+		&i.ActorType,
+		&i.Action,
+		&i.EntityType,
+		&i.EntityID,
+		&i.Before,
+		&i.After,
+		&i.RevertsAuditID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAuditEvent = `-- name: GetAuditEvent :one
+SELECT audit_id, actor, actor_type, action, entity_type, entity_id, before, after, reverts_audit_id, created_at
+FROM audit_events
+WHERE audit_id = $1
+`
+
+func (q *Queries) GetAuditEvent(ctx context.Context, auditID int64) (AuditEvent, error) {
+	row := q.db.QueryRow(ctx, getAuditEvent, auditID)
+	var i AuditEvent
+	err := row.Scan(
+		&i.AuditID,
+		&i.Actor,
+		&i.ActorType,
+		&i.Action,
+		&i.EntityType,
+		&i.EntityID,
+		&i.Before,
+		&i.After,
+		&i.RevertsAuditID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAuditEvents = `-- name: ListAuditEvents :many
+SELECT audit_id, actor, actor_type, action, entity_type, entity_id, before, after, reverts_audit_id, created_at
+FROM audit_events
+WHERE ($1::text IS NULL OR actor = $1::text)
+  AND ($2::text IS NULL OR action = $2::text)
+  AND ($3::text IS NULL OR entity_type = $3::text)
+  AND ($4::text IS NULL OR entity_id = $4::text)
+  AND ($5::timestamptz IS NULL OR created_at >= $5::timestamptz)
+  AND ($6::timestamptz IS NULL OR created_at < $6::timestamptz)
+  AND ($7::bigint IS NULL OR
+       CASE WHEN $8::bool THEN audit_id > $7::bigint
+            ELSE audit_id < $7::bigint END)
+ORDER BY CASE WHEN $8::bool THEN audit_id ELSE -audit_id END
+LIMIT $10 OFFSET $9
+`
+
+type ListAuditEventsParams struct {
+	Actor      *string    `json:"actor"`
+	Action     *string    `json:"action"`
+	EntityType *string    `json:"entity_type"`
+	EntityID   *string    `json:"entity_id"`
+	Since      *time.Time `json:"since"`
+	Until      *time.Time `json:"until"`
+	CursorID   *int64     `json:"cursor_id"`
+	Backward   bool       `json:"backward"`
+	PageOffset int32      `json:"page_offset"`
+	PageLimit  int32      `json:"page_limit"`
+}
+
+// Newest first. A cursor pages to older events, or to newer ones (in
+// reverse) when backward.
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.Query(ctx, listAuditEvents,
+		arg.Actor,
+		arg.Action,
+		arg.EntityType,
+		arg.EntityID,
+		arg.Since,
+		arg.Until,
+		arg.CursorID,
+		arg.Backward,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuditEvent{}
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.AuditID,
+			&i.Actor,
+			&i.ActorType,
+			&i.Action,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Before,
+			&i.After,
+			&i.RevertsAuditID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
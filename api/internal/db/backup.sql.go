@@ -0,0 +1,550 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: backup.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const clearVideoStreamIDs = `-- name: ClearVideoStreamIDs :exec
+UPDATE videos SET stream_id = NULL
+WHERE video_id = ANY($1::int[]) AND stream_id IS NOT NULL
+`
+
+// Restores relink streams in two passes so videos can swap them.
+func (q *Queries) ClearVideoStreamIDs(ctx context.Context, videoIds []int32) error {
+	_, err := q.db.Exec(ctx, clearVideoStreamIDs, videoIds)
+	return err
+}
+
+const deleteAllPromotions = `-- name: DeleteAllPromotions :execrows
+DELETE FROM promotions
+`
+
+func (q *Queries) DeleteAllPromotions(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAllPromotions)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const exportCountries = `-- name: ExportCountries :many
+
+SELECT code, name, region_type
+FROM countries
+ORDER BY code
+`
+
+type ExportCountriesRow struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	RegionType string `json:"region_type"`
+}
+
+// Catalog export/restore. Rows reference each other by slug so a backup can
+// be loaded into a database with different serial IDs.
+func (q *Queries) ExportCountries(ctx context.Context) ([]ExportCountriesRow, error) {
+	rows, err := q.db.Query(ctx, exportCountries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportCountriesRow{}
+	for rows.Next() {
+		var i ExportCountriesRow
+		if err := rows.Scan(&i.Code, &i.Name, &i.RegionType); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exportPromotions = `-- name: ExportPromotions :many
+SELECT p.slot, v.src AS video, p.title, p.starts_at, p.ends_at, p.timezone,
+       p.priority, p.is_default, p.created_by
+FROM promotions p
+JOIN videos v ON v.video_id = p.video_id
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
+WHERE v.deleted_at IS NULL AND s.deleted_at IS NULL
+  AND (sub.sublocation_id IS NULL OR sub.deleted_at IS NULL)
+ORDER BY p.slot, p.is_default DESC, p.starts_at, p.promotion_id
+`
+
+type ExportPromotionsRow struct {
+	Slot      string     `json:"slot"`
+	Video     string     `json:"video"`
+	Title     *string    `json:"title"`
+	StartsAt  *time.Time `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+	Timezone  string     `json:"timezone"`
+	Priority  int32      `json:"priority"`
+	IsDefault bool       `json:"is_default"`
+	CreatedBy *string    `json:"created_by"`
+}
+
+// Promotions reference their video by src, so only those of exported videos
+// are included.
+func (q *Queries) ExportPromotions(ctx context.Context) ([]ExportPromotionsRow, error) {
+	rows, err := q.db.Query(ctx, exportPromotions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportPromotionsRow{}
+	for rows.Next() {
+		var i ExportPromotionsRow
+		if err := rows.Scan(
+			&i.Slot,
+			&i.Video,
+			&i.Title,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Timezone,
+			&i.Priority,
+			&i.IsDefault,
+			&i.CreatedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exportStates = `-- name: ExportStates :many
+SELECT slug, name, description, country_code, region_type, sort_order
+FROM states
+WHERE deleted_at IS NULL
+ORDER BY slug
+`
+
+type ExportStatesRow struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CountryCode string `json:"country_code"`
+	RegionType  string `json:"region_type"`
+	SortOrder   *int32 `json:"sort_order"`
+}
+
+func (q *Queries) ExportStates(ctx context.Context) ([]ExportStatesRow, error) {
+	rows, err := q.db.Query(ctx, exportStates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportStatesRow{}
+	for rows.Next() {
+		var i ExportStatesRow
+		if err := rows.Scan(
+			&i.Slug,
+			&i.Name,
+			&i.Description,
+			&i.CountryCode,
+			&i.RegionType,
+			&i.SortOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exportSublocations = `-- name: ExportSublocations :many
+SELECT s.slug AS state, sub.slug, sub.name, sub.description,
+       p.slug AS parent, sub.sort_order
+FROM sublocations sub
+JOIN states s ON s.state_id = sub.state_id
+LEFT JOIN sublocations p ON p.sublocation_id = sub.parent_id AND p.deleted_at IS NULL
+WHERE sub.deleted_at IS NULL AND s.deleted_at IS NULL
+ORDER BY s.slug, sub.slug
+`
+
+type ExportSublocationsRow struct {
+	State       string  `json:"state"`
+	Slug        string  `json:"slug"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Parent      *string `json:"parent"`
+	SortOrder   *int32  `json:"sort_order"`
+}
+
+func (q *Queries) ExportSublocations(ctx context.Context) ([]ExportSublocationsRow, error) {
+	rows, err := q.db.Query(ctx, exportSublocations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportSublocationsRow{}
+	for rows.Next() {
+		var i ExportSublocationsRow
+		if err := rows.Scan(
+			&i.State,
+			&i.Slug,
+			&i.Name,
+			&i.Description,
+			&i.Parent,
+			&i.SortOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exportTags = `-- name: ExportTags :many
+SELECT slug, name
+FROM tags
+ORDER BY slug
+`
+
+type ExportTagsRow struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) ExportTags(ctx context.Context) ([]ExportTagsRow, error) {
+	rows, err := q.db.Query(ctx, exportTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportTagsRow{}
+	for rows.Next() {
+		var i ExportTagsRow
+		if err := rows.Scan(&i.Slug, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exportVideos = `-- name: ExportVideos :many
+SELECT v.title, v.src, v.type, v.status,
+       s.slug AS state,
+       sub.slug AS sublocation,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.camera_model, v.firmware, v.owner_contact, v.install_date, v.notes,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.stream_id,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug)
+                 FROM video_tags vt JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
+WHERE v.deleted_at IS NULL AND s.deleted_at IS NULL
+  AND (sub.sublocation_id IS NULL OR sub.deleted_at IS NULL)
+ORDER BY s.slug, v.title, v.video_id
+`
+
+type ExportVideosRow struct {
+	Title        string      `json:"title"`
+	Src          string      `json:"src"`
+	Type         string      `json:"type"`
+	Status       string      `json:"status"`
+	State        string      `json:"state"`
+	Sublocation  *string     `json:"sublocation"`
+	Latitude     *float64    `json:"latitude"`
+	Longitude    *float64    `json:"longitude"`
+	Heading      *float64    `json:"heading"`
+	FieldOfView  *float64    `json:"field_of_view"`
+	Elevation    *float64    `json:"elevation"`
+	Timezone     *string     `json:"timezone"`
+	CameraModel  *string     `json:"camera_model"`
+	Firmware     *string     `json:"firmware"`
+	OwnerContact *string     `json:"owner_contact"`
+	InstallDate  pgtype.Date `json:"install_date"`
+	Notes        *string     `json:"notes"`
+	PublishAt    *time.Time  `json:"publish_at"`
+	UnpublishAt  *time.Time  `json:"unpublish_at"`
+	Featured     bool        `json:"featured"`
+	SortOrder    *int32      `json:"sort_order"`
+	CreatedBy    string      `json:"created_by"`
+	StreamID     *string     `json:"stream_id"`
+	Tags         []string    `json:"tags"`
+}
+
+func (q *Queries) ExportVideos(ctx context.Context) ([]ExportVideosRow, error) {
+	rows, err := q.db.Query(ctx, exportVideos)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ExportVideosRow{}
+	for rows.Next() {
+		var i ExportVideosRow
+		if err := rows.Scan(
+			&i.Title,
+			&i.Src,
+			&i.Type,
+			&i.Status,
+			&i.State,
+			&i.Sublocation,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
+			&i.CameraModel,
+			&i.Firmware,
+			&i.OwnerContact,
+			&i.InstallDate,
+			&i.Notes,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Featured,
+			&i.SortOrder,
+			&i.CreatedBy,
+			&i.StreamID,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLiveVideoIDBySrc = `-- name: GetLiveVideoIDBySrc :one
+SELECT video_id
+FROM videos
+WHERE src = $1 AND deleted_at IS NULL
+ORDER BY video_id
+LIMIT 1
+`
+
+func (q *Queries) GetLiveVideoIDBySrc(ctx context.Context, src string) (int32, error) {
+	row := q.db.QueryRow(ctx, getLiveVideoIDBySrc, src)
+	var video_id int32
+	err := row.Scan(&video_id)
+	return video_id, err
+}
+
+const getRestoredPromotionID = `-- name: GetRestoredPromotionID :one
+SELECT promotion_id
+FROM promotions
+WHERE slot = $1
+  AND CASE WHEN $2::bool THEN is_default
+           ELSE NOT is_default AND video_id = $3 AND starts_at = $4
+      END
+ORDER BY promotion_id
+LIMIT 1
+`
+
+type GetRestoredPromotionIDParams struct {
+	Slot      string     `json:"slot"`
+	IsDefault bool       `json:"is_default"`
+	VideoID   int32      `json:"video_id"`
+	StartsAt  *time.Time `json:"starts_at"`
+}
+
+// The promotion a restored one stands for: the slot's default, or a
+// scheduled promotion of the same video starting at the same time.
+func (q *Queries) GetRestoredPromotionID(ctx context.Context, arg GetRestoredPromotionIDParams) (int32, error) {
+	row := q.db.QueryRow(ctx, getRestoredPromotionID,
+		arg.Slot,
+		arg.IsDefault,
+		arg.VideoID,
+		arg.StartsAt,
+	)
+	var promotion_id int32
+	err := row.Scan(&promotion_id)
+	return promotion_id, err
+}
+
+const pruneStates = `-- name: PruneStates :execrows
+DELETE FROM states
+WHERE NOT (state_id = ANY($1::int[]))
+`
+
+func (q *Queries) PruneStates(ctx context.Context, keep []int32) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneStates, keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const pruneSublocations = `-- name: PruneSublocations :execrows
+DELETE FROM sublocations
+WHERE NOT (sublocation_id = ANY($1::int[]))
+`
+
+func (q *Queries) PruneSublocations(ctx context.Context, keep []int32) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneSublocations, keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const pruneTags = `-- name: PruneTags :execrows
+DELETE FROM tags
+WHERE NOT (slug = ANY($1::text[]))
+`
+
+func (q *Queries) PruneTags(ctx context.Context, keep []string) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneTags, keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const pruneVideos = `-- name: PruneVideos :execrows
+
+DELETE FROM videos
+WHERE NOT (video_id = ANY($1::int[]))
+`
+
+// Replace restores prune whatever the backup didn't upsert, trash included.
+// Rows it did upsert keep their IDs, so references to them survive.
+func (q *Queries) PruneVideos(ctx context.Context, keep []int32) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneVideos, keep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setVideoStreamID = `-- name: SetVideoStreamID :exec
+UPDATE videos SET stream_id = $2 WHERE video_id = $1
+`
+
+type SetVideoStreamIDParams struct {
+	VideoID  int32   `json:"video_id"`
+	StreamID *string `json:"stream_id"`
+}
+
+func (q *Queries) SetVideoStreamID(ctx context.Context, arg SetVideoStreamIDParams) error {
+	_, err := q.db.Exec(ctx, setVideoStreamID, arg.VideoID, arg.StreamID)
+	return err
+}
+
+const upsertCountry = `-- name: UpsertCountry :exec
+INSERT INTO countries (code, name, region_type)
+VALUES ($1, $2, $3)
+ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, region_type = EXCLUDED.region_type
+`
+
+type UpsertCountryParams struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	RegionType string `json:"region_type"`
+}
+
+func (q *Queries) UpsertCountry(ctx context.Context, arg UpsertCountryParams) error {
+	_, err := q.db.Exec(ctx, upsertCountry, arg.Code, arg.Name, arg.RegionType)
+	return err
+}
+
+const upsertStateBySlug = `-- name: UpsertStateBySlug :one
+INSERT INTO states (slug, name, description, country_code, region_type, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (slug) DO UPDATE
+SET name = EXCLUDED.name, description = EXCLUDED.description,
+    country_code = EXCLUDED.country_code, region_type = EXCLUDED.region_type,
+    sort_order = EXCLUDED.sort_order, deleted_at = NULL
+RETURNING state_id
+`
+
+type UpsertStateBySlugParams struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CountryCode string `json:"country_code"`
+	RegionType  string `json:"region_type"`
+	SortOrder   *int32 `json:"sort_order"`
+}
+
+func (q *Queries) UpsertStateBySlug(ctx context.Context, arg UpsertStateBySlugParams) (int32, error) {
+	row := q.db.QueryRow(ctx, upsertStateBySlug,
+		arg.Slug,
+		arg.Name,
+		arg.Description,
+		arg.CountryCode,
+		arg.RegionType,
+		arg.SortOrder,
+	)
+	var state_id int32
+	err := row.Scan(&state_id)
+	return state_id, err
+}
+
+const upsertSublocationBySlug = `-- name: UpsertSublocationBySlug :one
+INSERT INTO sublocations (state_id, slug, name, description, sort_order)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (state_id, slug) DO UPDATE
+SET name = EXCLUDED.name, description = EXCLUDED.description,
+    sort_order = EXCLUDED.sort_order, deleted_at = NULL
+RETURNING sublocation_id
+`
+
+type UpsertSublocationBySlugParams struct {
+	StateID     int32  `json:"state_id"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	SortOrder   *int32 `json:"sort_order"`
+}
+
+func (q *Queries) UpsertSublocationBySlug(ctx context.Context, arg UpsertSublocationBySlugParams) (int32, error) {
+	row := q.db.QueryRow(ctx, upsertSublocationBySlug,
+		arg.StateID,
+		arg.Slug,
+		arg.Name,
+		arg.Description,
+		arg.SortOrder,
+	)
+	var sublocation_id int32
+	err := row.Scan(&sublocation_id)
+	return sublocation_id, err
+}
+
+const upsertTagBySlug = `-- name: UpsertTagBySlug :exec
+INSERT INTO tags (slug, name)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name
+`
+
+type UpsertTagBySlugParams struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) UpsertTagBySlug(ctx context.Context, arg UpsertTagBySlugParams) error {
+	_, err := q.db.Exec(ctx, upsertTagBySlug, arg.Slug, arg.Name)
+	return err
+}
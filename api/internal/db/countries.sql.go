@@ -0,0 +1,139 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: countries.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createCountry = `-- name: CreateCountry :one
+INSERT INTO countries (code, name, region_type)
+VALUES ($1, $2, $3)
+RETURNING code, name, region_type, created_at, updated_at
+`
+
+type CreateCountryParams struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	RegionType string `json:"region_type"`
+}
+
+func (q *Queries) CreateCountry(ctx context.Context, arg CreateCountryParams) (Country, error) {
+	row := q.db.QueryRow(ctx, createCountry, arg.Code, arg.Name, arg.RegionType)
+	var i Country
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.RegionType,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCountry = `-- name: GetCountry :one
+SELECT c.code, c.name, c.region_type, c.created_at, c.updated_at,
+       (SELECT COUNT(*) FROM states s
+        WHERE s.country_code = c.code AND s.deleted_at IS NULL)::int AS region_count,
+       (SELECT COUNT(*) FROM videos v JOIN states s ON s.state_id = v.state_id
+        WHERE s.country_code = c.code AND s.deleted_at IS NULL
+          AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL)::int AS video_count
+FROM countries c
+WHERE c.code = $1
+`
+
+type GetCountryRow struct {
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	RegionType  string    `json:"region_type"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	RegionCount int32     `json:"region_count"`
+	VideoCount  int32     `json:"video_count"`
+}
+
+func (q *Queries) GetCountry(ctx context.Context, code string) (GetCountryRow, error) {
+	row := q.db.QueryRow(ctx, getCountry, code)
+	var i GetCountryRow
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.RegionType,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RegionCount,
+		&i.VideoCount,
+	)
+	return i, err
+}
+
+const listCountries = `-- name: ListCountries :many
+SELECT c.code, c.name, c.region_type, c.created_at, c.updated_at,
+       (SELECT COUNT(*) FROM states s
+        WHERE s.country_code = c.code AND s.deleted_at IS NULL)::int AS region_count,
+       (SELECT COUNT(*) FROM videos v JOIN states s ON s.state_id = v.state_id
+        WHERE s.country_code = c.code AND s.deleted_at IS NULL
+          AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL)::int AS video_count
+FROM countries c
+ORDER BY c.name
+`
+
+type ListCountriesRow struct {
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	RegionType  string    `json:"region_type"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	RegionCount int32     `json:"region_count"`
+	VideoCount  int32     `json:"video_count"`
+}
+
+func (q *Queries) ListCountries(ctx context.Context) ([]ListCountriesRow, error) {
+	rows, err := q.db.Query(ctx, listCountries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListCountriesRow{}
+	for rows.Next() {
+		var i ListCountriesRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.Name,
+			&i.RegionType,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RegionCount,
+			&i.VideoCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCountry = `-- name: UpdateCountry :execrows
+UPDATE countries SET name = $2, region_type = $3 WHERE code = $1
+`
+
+type UpdateCountryParams struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	RegionType string `json:"region_type"`
+}
+
+func (q *Queries) UpdateCountry(ctx context.Context, arg UpdateCountryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateCountry, arg.Code, arg.Name, arg.RegionType)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
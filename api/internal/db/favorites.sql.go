@@ -0,0 +1,453 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: favorites.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const addFavorite = `-- name: AddFavorite :one
+INSERT INTO user_favorites (user_id, video_id)
+SELECT $1, v.video_id FROM videos v
+WHERE v.video_id = $2 AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ON CONFLICT (user_id, video_id) DO UPDATE SET created_at = user_favorites.created_at
+RETURNING video_id, created_at
+`
+
+type AddFavoriteParams struct {
+	UserID  string `json:"user_id"`
+	VideoID int32  `json:"video_id"`
+}
+
+type AddFavoriteRow struct {
+	VideoID   int32     `json:"video_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Favorites a listed video; favoriting it again keeps the original time.
+// No row means the video isn't listed.
+func (q *Queries) AddFavorite(ctx context.Context, arg AddFavoriteParams) (AddFavoriteRow, error) {
+	row := q.db.QueryRow(ctx, addFavorite, arg.UserID, arg.VideoID)
+	var i AddFavoriteRow
+	err := row.Scan(&i.VideoID, &i.CreatedAt)
+	return i, err
+}
+
+const addUserListVideos = `-- name: AddUserListVideos :execrows
+INSERT INTO user_list_videos (list_id, video_id, position)
+SELECT $1, v.video_id, o.position
+FROM unnest($2::int[]) WITH ORDINALITY AS o(video_id, position)
+JOIN videos v ON v.video_id = o.video_id
+WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+`
+
+type AddUserListVideosParams struct {
+	ListID int32   `json:"list_id"`
+	Ids    []int32 `json:"ids"`
+}
+
+// Adds ids to a list at their 1-based positions, skipping any that aren't
+// listed videos.
+func (q *Queries) AddUserListVideos(ctx context.Context, arg AddUserListVideosParams) (int64, error) {
+	result, err := q.db.Exec(ctx, addUserListVideos, arg.ListID, arg.Ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const clearUserListVideos = `-- name: ClearUserListVideos :exec
+DELETE FROM user_list_videos WHERE list_id = $1
+`
+
+func (q *Queries) ClearUserListVideos(ctx context.Context, listID int32) error {
+	_, err := q.db.Exec(ctx, clearUserListVideos, listID)
+	return err
+}
+
+const countUserLists = `-- name: CountUserLists :one
+SELECT COUNT(*)::int FROM user_lists WHERE user_id = $1
+`
+
+func (q *Queries) CountUserLists(ctx context.Context, userID string) (int32, error) {
+	row := q.db.QueryRow(ctx, countUserLists, userID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createUserList = `-- name: CreateUserList :one
+INSERT INTO user_lists (user_id, name, sort_order)
+VALUES ($1, $2,
+        (SELECT COALESCE(MAX(sort_order), 0) + 1 FROM user_lists WHERE user_id = $1))
+RETURNING list_id
+`
+
+type CreateUserListParams struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// New lists go last.
+func (q *Queries) CreateUserList(ctx context.Context, arg CreateUserListParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createUserList, arg.UserID, arg.Name)
+	var list_id int32
+	err := row.Scan(&list_id)
+	return list_id, err
+}
+
+const deleteFavorite = `-- name: DeleteFavorite :execrows
+DELETE FROM user_favorites WHERE user_id = $1 AND video_id = $2
+`
+
+type DeleteFavoriteParams struct {
+	UserID  string `json:"user_id"`
+	VideoID int32  `json:"video_id"`
+}
+
+func (q *Queries) DeleteFavorite(ctx context.Context, arg DeleteFavoriteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteFavorite, arg.UserID, arg.VideoID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserList = `-- name: DeleteUserList :execrows
+DELETE FROM user_lists WHERE list_id = $1 AND user_id = $2
+`
+
+type DeleteUserListParams struct {
+	ListID int32  `json:"list_id"`
+	UserID string `json:"user_id"`
+}
+
+func (q *Queries) DeleteUserList(ctx context.Context, arg DeleteUserListParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserList, arg.ListID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getFavorite = `-- name: GetFavorite :one
+SELECT video_id, created_at FROM user_favorites
+WHERE user_id = $1 AND video_id = $2
+`
+
+type GetFavoriteParams struct {
+	UserID  string `json:"user_id"`
+	VideoID int32  `json:"video_id"`
+}
+
+type GetFavoriteRow struct {
+	VideoID   int32     `json:"video_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) GetFavorite(ctx context.Context, arg GetFavoriteParams) (GetFavoriteRow, error) {
+	row := q.db.QueryRow(ctx, getFavorite, arg.UserID, arg.VideoID)
+	var i GetFavoriteRow
+	err := row.Scan(&i.VideoID, &i.CreatedAt)
+	return i, err
+}
+
+const getUserList = `-- name: GetUserList :one
+SELECT list_id, name, sort_order, created_at, updated_at
+FROM user_lists WHERE list_id = $1 AND user_id = $2
+`
+
+type GetUserListParams struct {
+	ListID int32  `json:"list_id"`
+	UserID string `json:"user_id"`
+}
+
+type GetUserListRow struct {
+	ListID    int32     `json:"list_id"`
+	Name      string    `json:"name"`
+	SortOrder int32     `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) GetUserList(ctx context.Context, arg GetUserListParams) (GetUserListRow, error) {
+	row := q.db.QueryRow(ctx, getUserList, arg.ListID, arg.UserID)
+	var i GetUserListRow
+	err := row.Scan(
+		&i.ListID,
+		&i.Name,
+		&i.SortOrder,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listFavoriteVideoIDs = `-- name: ListFavoriteVideoIDs :many
+SELECT video_id FROM user_favorites WHERE user_id = $1
+`
+
+func (q *Queries) ListFavoriteVideoIDs(ctx context.Context, userID string) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listFavoriteVideoIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var video_id int32
+		if err := rows.Scan(&video_id); err != nil {
+			return nil, err
+		}
+		items = append(items, video_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFavoriteVideos = `-- name: ListFavoriteVideos :many
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.featured, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name,
+       f.created_at AS favorited_at
+FROM user_favorites f
+JOIN videos v ON v.video_id = f.video_id
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE f.user_id = $1 AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY f.created_at DESC, v.video_id
+`
+
+type ListFavoriteVideosRow struct {
+	VideoID         int32     `json:"video_id"`
+	Title           string    `json:"title"`
+	Src             string    `json:"src"`
+	Type            string    `json:"type"`
+	StateID         int32     `json:"state_id"`
+	SublocationID   *int32    `json:"sublocation_id"`
+	Latitude        *float64  `json:"latitude"`
+	Longitude       *float64  `json:"longitude"`
+	Heading         *float64  `json:"heading"`
+	FieldOfView     *float64  `json:"field_of_view"`
+	Elevation       *float64  `json:"elevation"`
+	Timezone        *string   `json:"timezone"`
+	Status          string    `json:"status"`
+	Badge           string    `json:"badge"`
+	Featured        bool      `json:"featured"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	StateName       string    `json:"state_name"`
+	SublocationName string    `json:"sublocation_name"`
+	FavoritedAt     time.Time `json:"favorited_at"`
+}
+
+// A user's listed favorites, most recently added first.
+func (q *Queries) ListFavoriteVideos(ctx context.Context, userID string) ([]ListFavoriteVideosRow, error) {
+	rows, err := q.db.Query(ctx, listFavoriteVideos, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFavoriteVideosRow{}
+	for rows.Next() {
+		var i ListFavoriteVideosRow
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.Src,
+			&i.Type,
+			&i.StateID,
+			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
+			&i.Status,
+			&i.Badge,
+			&i.Featured,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StateName,
+			&i.SublocationName,
+			&i.FavoritedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserListVideos = `-- name: ListUserListVideos :many
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.featured, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name
+FROM user_list_videos lv
+JOIN videos v ON v.video_id = lv.video_id
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE lv.list_id = $1 AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY lv.position
+`
+
+type ListUserListVideosRow struct {
+	VideoID         int32     `json:"video_id"`
+	Title           string    `json:"title"`
+	Src             string    `json:"src"`
+	Type            string    `json:"type"`
+	StateID         int32     `json:"state_id"`
+	SublocationID   *int32    `json:"sublocation_id"`
+	Latitude        *float64  `json:"latitude"`
+	Longitude       *float64  `json:"longitude"`
+	Heading         *float64  `json:"heading"`
+	FieldOfView     *float64  `json:"field_of_view"`
+	Elevation       *float64  `json:"elevation"`
+	Timezone        *string   `json:"timezone"`
+	Status          string    `json:"status"`
+	Badge           string    `json:"badge"`
+	Featured        bool      `json:"featured"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	StateName       string    `json:"state_name"`
+	SublocationName string    `json:"sublocation_name"`
+}
+
+// A list's listed videos in the user's order.
+func (q *Queries) ListUserListVideos(ctx context.Context, listID int32) ([]ListUserListVideosRow, error) {
+	rows, err := q.db.Query(ctx, listUserListVideos, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserListVideosRow{}
+	for rows.Next() {
+		var i ListUserListVideosRow
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.Src,
+			&i.Type,
+			&i.StateID,
+			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
+			&i.Status,
+			&i.Badge,
+			&i.Featured,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StateName,
+			&i.SublocationName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserLists = `-- name: ListUserLists :many
+SELECT l.list_id, l.name, l.sort_order, l.created_at, l.updated_at,
+       COUNT(v.video_id)::int AS video_count
+FROM user_lists l
+LEFT JOIN user_list_videos lv ON lv.list_id = l.list_id
+LEFT JOIN videos v ON v.video_id = lv.video_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE l.user_id = $1
+GROUP BY l.list_id
+ORDER BY l.sort_order, l.list_id
+`
+
+type ListUserListsRow struct {
+	ListID     int32     `json:"list_id"`
+	Name       string    `json:"name"`
+	SortOrder  int32     `json:"sort_order"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	VideoCount int32     `json:"video_count"`
+}
+
+// A user's lists in their order, with how many listed videos each holds.
+func (q *Queries) ListUserLists(ctx context.Context, userID string) ([]ListUserListsRow, error) {
+	rows, err := q.db.Query(ctx, listUserLists, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserListsRow{}
+	for rows.Next() {
+		var i ListUserListsRow
+		if err := rows.Scan(
+			&i.ListID,
+			&i.Name,
+			&i.SortOrder,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.VideoCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const renameUserList = `-- name: RenameUserList :exec
+UPDATE user_lists SET name = $2 WHERE list_id = $1
+`
+
+type RenameUserListParams struct {
+	ListID int32  `json:"list_id"`
+	Name   string `json:"name"`
+}
+
+func (q *Queries) RenameUserList(ctx context.Context, arg RenameUserListParams) error {
+	_, err := q.db.Exec(ctx, renameUserList, arg.ListID, arg.Name)
+	return err
+}
+
+const reorderUserLists = `-- name: ReorderUserLists :execrows
+UPDATE user_lists l SET sort_order = o.position
+FROM unnest($2::int[]) WITH ORDINALITY AS o(list_id, position)
+WHERE l.list_id = o.list_id AND l.user_id = $1
+`
+
+type ReorderUserListsParams struct {
+	UserID string  `json:"user_id"`
+	Ids    []int32 `json:"ids"`
+}
+
+// Sets sort_order to each ID's 1-based position in ids, among the user's lists.
+func (q *Queries) ReorderUserLists(ctx context.Context, arg ReorderUserListsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reorderUserLists, arg.UserID, arg.Ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -5,37 +5,176 @@
 package db
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuditEvent struct {
+	AuditID        int64           `json:"audit_id"`
+	Actor          string          `json:"actor"`
+	ActorType      string          `json:"actor_type"`
+	Action         string          `json:"action"`
+	EntityType     string          `json:"entity_type"`
+	EntityID       string          `json:"entity_id"`
+	Before         json.RawMessage `json:"before"`
+	After          json.RawMessage `json:"after"`
+	RevertsAuditID *int64          `json:"reverts_audit_id"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type Country struct {
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	RegionType string    `json:"region_type"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type Promotion struct {
+	PromotionID int32      `json:"promotion_id"`
+	Slot        string     `json:"slot"`
+	VideoID     int32      `json:"video_id"`
+	Title       *string    `json:"title"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Timezone    string     `json:"timezone"`
+	Priority    int32      `json:"priority"`
+	IsDefault   bool       `json:"is_default"`
+	CreatedBy   *string    `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type SlugHistory struct {
+	EntityType string    `json:"entity_type"`
+	Slug       string    `json:"slug"`
+	EntityID   int32     `json:"entity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	ScopeID    int32     `json:"scope_id"`
+}
+
 type State struct {
-	StateID     int32     `json:"state_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Slug        string    `json:"slug"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	StateID      int32       `json:"state_id"`
+	Name         string      `json:"name"`
+	Description  string      `json:"description"`
+	Slug         string      `json:"slug"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+	SearchVector interface{} `json:"search_vector"`
+	DeletedAt    *time.Time  `json:"deleted_at"`
+	CountryCode  string      `json:"country_code"`
+	RegionType   string      `json:"region_type"`
+	SortOrder    *int32      `json:"sort_order"`
 }
 
 type Sublocation struct {
-	SublocationID int32     `json:"sublocation_id"`
-	Name          string    `json:"name"`
-	Description   string    `json:"description"`
-	StateID       int32     `json:"state_id"`
-	Slug          string    `json:"slug"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	SublocationID int32       `json:"sublocation_id"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	StateID       int32       `json:"state_id"`
+	Slug          string      `json:"slug"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	SearchVector  interface{} `json:"search_vector"`
+	DeletedAt     *time.Time  `json:"deleted_at"`
+	ParentID      *int32      `json:"parent_id"`
+	SortOrder     *int32      `json:"sort_order"`
+}
+
+type Submission struct {
+	SubmissionID int32      `json:"submission_id"`
+	Title        string     `json:"title"`
+	Src          string     `json:"src"`
+	StateID      *int32     `json:"state_id"`
+	ContactName  *string    `json:"contact_name"`
+	ContactEmail string     `json:"contact_email"`
+	Notes        *string    `json:"notes"`
+	ClientHash   string     `json:"client_hash"`
+	Status       string     `json:"status"`
+	RejectReason *string    `json:"reject_reason"`
+	ReviewedBy   *string    `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	VideoID      *int32     `json:"video_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type Tag struct {
+	TagID     int32     `json:"tag_id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type UserFavorite struct {
+	UserID    string    `json:"user_id"`
+	VideoID   int32     `json:"video_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserList struct {
+	ListID    int32     `json:"list_id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	SortOrder int32     `json:"sort_order"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type UserListVideo struct {
+	ListID   int32 `json:"list_id"`
+	VideoID  int32 `json:"video_id"`
+	Position int32 `json:"position"`
 }
 
 type Video struct {
-	VideoID       int32     `json:"video_id"`
-	Title         string    `json:"title"`
-	Src           string    `json:"src"`
-	Type          string    `json:"type"`
-	StateID       int32     `json:"state_id"`
-	SublocationID *int32    `json:"sublocation_id"`
-	Status        string    `json:"status"`
-	CreatedBy     string    `json:"created_by"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	VideoID       int32       `json:"video_id"`
+	Title         string      `json:"title"`
+	Src           string      `json:"src"`
+	Type          string      `json:"type"`
+	StateID       int32       `json:"state_id"`
+	SublocationID *int32      `json:"sublocation_id"`
+	Status        string      `json:"status"`
+	CreatedBy     string      `json:"created_by"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	Latitude      *float64    `json:"latitude"`
+	Longitude     *float64    `json:"longitude"`
+	Heading       *float64    `json:"heading"`
+	Elevation     *float64    `json:"elevation"`
+	SearchVector  interface{} `json:"search_vector"`
+	DeletedAt     *time.Time  `json:"deleted_at"`
+	StreamID      *string     `json:"stream_id"`
+	PublishAt     *time.Time  `json:"publish_at"`
+	UnpublishAt   *time.Time  `json:"unpublish_at"`
+	SortOrder     *int32      `json:"sort_order"`
+	Featured      bool        `json:"featured"`
+	Timezone      *string     `json:"timezone"`
+	FieldOfView   *float64    `json:"field_of_view"`
+	CameraModel   *string     `json:"camera_model"`
+	Firmware      *string     `json:"firmware"`
+	OwnerContact  *string     `json:"owner_contact"`
+	InstallDate   pgtype.Date `json:"install_date"`
+	Notes         *string     `json:"notes"`
+}
+
+type VideoReport struct {
+	ReportID       int32      `json:"report_id"`
+	VideoID        int32      `json:"video_id"`
+	Reason         string     `json:"reason"`
+	Note           *string    `json:"note"`
+	ClientHash     string     `json:"client_hash"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	ResolvedBy     *string    `json:"resolved_by"`
+	ReportCount    int32      `json:"report_count"`
+	LastReportedAt time.Time  `json:"last_reported_at"`
+}
+
+type VideoTag struct {
+	VideoID int32 `json:"video_id"`
+	TagID   int32 `json:"tag_id"`
 }
@@ -0,0 +1,314 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: promotions.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createPromotion = `-- name: CreatePromotion :one
+INSERT INTO promotions (slot, video_id, title, starts_at, ends_at, timezone, priority, is_default, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING promotion_id
+`
+
+type CreatePromotionParams struct {
+	Slot      string     `json:"slot"`
+	VideoID   int32      `json:"video_id"`
+	Title     *string    `json:"title"`
+	StartsAt  *time.Time `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+	Timezone  string     `json:"timezone"`
+	Priority  int32      `json:"priority"`
+	IsDefault bool       `json:"is_default"`
+	CreatedBy *string    `json:"created_by"`
+}
+
+func (q *Queries) CreatePromotion(ctx context.Context, arg CreatePromotionParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createPromotion,
+		arg.Slot,
+		arg.VideoID,
+		arg.Title,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Timezone,
+		arg.Priority,
+		arg.IsDefault,
+		arg.CreatedBy,
+	)
+	var promotion_id int32
+	err := row.Scan(&promotion_id)
+	return promotion_id, err
+}
+
+const deletePromotion = `-- name: DeletePromotion :execrows
+DELETE FROM promotions WHERE promotion_id = $1
+`
+
+func (q *Queries) DeletePromotion(ctx context.Context, promotionID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePromotion, promotionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getCurrentPromotion = `-- name: GetCurrentPromotion :one
+SELECT p.promotion_id, p.slot, p.title, p.starts_at, p.ends_at, p.timezone,
+       p.priority, p.is_default,
+       v.video_id, v.title AS video_title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone AS video_timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name
+FROM promotions p
+JOIN videos v ON v.video_id = p.video_id
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE p.slot = $1
+  AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+  AND (p.is_default OR (p.starts_at <= $2::timestamptz
+                        AND (p.ends_at IS NULL OR p.ends_at > $2::timestamptz)))
+ORDER BY p.is_default, p.priority DESC, p.starts_at DESC, p.promotion_id DESC
+LIMIT 1
+`
+
+type GetCurrentPromotionParams struct {
+	Slot string    `json:"slot"`
+	Now  time.Time `json:"now"`
+}
+
+type GetCurrentPromotionRow struct {
+	PromotionID     int32      `json:"promotion_id"`
+	Slot            string     `json:"slot"`
+	Title           *string    `json:"title"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+	Timezone        string     `json:"timezone"`
+	Priority        int32      `json:"priority"`
+	IsDefault       bool       `json:"is_default"`
+	VideoID         int32      `json:"video_id"`
+	VideoTitle      string     `json:"video_title"`
+	Src             string     `json:"src"`
+	Type            string     `json:"type"`
+	StateID         int32      `json:"state_id"`
+	SublocationID   *int32     `json:"sublocation_id"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	Heading         *float64   `json:"heading"`
+	FieldOfView     *float64   `json:"field_of_view"`
+	Elevation       *float64   `json:"elevation"`
+	VideoTimezone   *string    `json:"video_timezone"`
+	Status          string     `json:"status"`
+	Badge           string     `json:"badge"`
+	StateName       string     `json:"state_name"`
+	SublocationName string     `json:"sublocation_name"`
+}
+
+// The promotion live in a slot at now: the highest-priority scheduled one
+// whose window contains now (latest start breaking ties), else the slot's
+// default. Promotions of unlisted videos are skipped.
+func (q *Queries) GetCurrentPromotion(ctx context.Context, arg GetCurrentPromotionParams) (GetCurrentPromotionRow, error) {
+	row := q.db.QueryRow(ctx, getCurrentPromotion, arg.Slot, arg.Now)
+	var i GetCurrentPromotionRow
+	err := row.Scan(
+		&i.PromotionID,
+		&i.Slot,
+		&i.Title,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Timezone,
+		&i.Priority,
+		&i.IsDefault,
+		&i.VideoID,
+		&i.VideoTitle,
+		&i.Src,
+		&i.Type,
+		&i.StateID,
+		&i.SublocationID,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Heading,
+		&i.FieldOfView,
+		&i.Elevation,
+		&i.VideoTimezone,
+		&i.Status,
+		&i.Badge,
+		&i.StateName,
+		&i.SublocationName,
+	)
+	return i, err
+}
+
+const getNextPromotionChange = `-- name: GetNextPromotionChange :one
+SELECT b.t::timestamptz AS changes_at
+FROM promotions p
+CROSS JOIN LATERAL (VALUES (p.starts_at), (p.ends_at)) AS b(t)
+WHERE p.slot = $1 AND NOT p.is_default
+  AND b.t > $2::timestamptz
+ORDER BY changes_at
+LIMIT 1
+`
+
+type GetNextPromotionChangeParams struct {
+	Slot string    `json:"slot"`
+	Now  time.Time `json:"now"`
+}
+
+// The next instant after now at which a scheduled promotion in the slot
+// starts or ends, i.e. when GetCurrentPromotion may next change.
+func (q *Queries) GetNextPromotionChange(ctx context.Context, arg GetNextPromotionChangeParams) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getNextPromotionChange, arg.Slot, arg.Now)
+	var changes_at time.Time
+	err := row.Scan(&changes_at)
+	return changes_at, err
+}
+
+const getPromotion = `-- name: GetPromotion :one
+SELECT p.promotion_id, p.slot, p.video_id, p.title, p.starts_at, p.ends_at,
+       p.timezone, p.priority, p.is_default, p.created_by, p.created_at, p.updated_at,
+       v.title AS video_title
+FROM promotions p
+JOIN videos v ON v.video_id = p.video_id
+WHERE p.promotion_id = $1
+`
+
+type GetPromotionRow struct {
+	PromotionID int32      `json:"promotion_id"`
+	Slot        string     `json:"slot"`
+	VideoID     int32      `json:"video_id"`
+	Title       *string    `json:"title"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Timezone    string     `json:"timezone"`
+	Priority    int32      `json:"priority"`
+	IsDefault   bool       `json:"is_default"`
+	CreatedBy   *string    `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	VideoTitle  string     `json:"video_title"`
+}
+
+func (q *Queries) GetPromotion(ctx context.Context, promotionID int32) (GetPromotionRow, error) {
+	row := q.db.QueryRow(ctx, getPromotion, promotionID)
+	var i GetPromotionRow
+	err := row.Scan(
+		&i.PromotionID,
+		&i.Slot,
+		&i.VideoID,
+		&i.Title,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Timezone,
+		&i.Priority,
+		&i.IsDefault,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.VideoTitle,
+	)
+	return i, err
+}
+
+const listPromotions = `-- name: ListPromotions :many
+SELECT p.promotion_id, p.slot, p.video_id, p.title, p.starts_at, p.ends_at,
+       p.timezone, p.priority, p.is_default, p.created_by, p.created_at, p.updated_at,
+       v.title AS video_title
+FROM promotions p
+JOIN videos v ON v.video_id = p.video_id
+WHERE $1::text IS NULL OR p.slot = $1
+ORDER BY p.slot, p.is_default DESC, p.starts_at DESC NULLS LAST, p.promotion_id
+`
+
+type ListPromotionsRow struct {
+	PromotionID int32      `json:"promotion_id"`
+	Slot        string     `json:"slot"`
+	VideoID     int32      `json:"video_id"`
+	Title       *string    `json:"title"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Timezone    string     `json:"timezone"`
+	Priority    int32      `json:"priority"`
+	IsDefault   bool       `json:"is_default"`
+	CreatedBy   *string    `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	VideoTitle  string     `json:"video_title"`
+}
+
+// Every promotion, optionally for one slot: defaults first, then newest
+// window first.
+func (q *Queries) ListPromotions(ctx context.Context, slot *string) ([]ListPromotionsRow, error) {
+	rows, err := q.db.Query(ctx, listPromotions, slot)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPromotionsRow{}
+	for rows.Next() {
+		var i ListPromotionsRow
+		if err := rows.Scan(
+			&i.PromotionID,
+			&i.Slot,
+			&i.VideoID,
+			&i.Title,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Timezone,
+			&i.Priority,
+			&i.IsDefault,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.VideoTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePromotion = `-- name: UpdatePromotion :execrows
+UPDATE promotions
+SET slot = $2, video_id = $3, title = $4, starts_at = $5, ends_at = $6,
+    timezone = $7, priority = $8, is_default = $9
+WHERE promotion_id = $1
+`
+
+type UpdatePromotionParams struct {
+	PromotionID int32      `json:"promotion_id"`
+	Slot        string     `json:"slot"`
+	VideoID     int32      `json:"video_id"`
+	Title       *string    `json:"title"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Timezone    string     `json:"timezone"`
+	Priority    int32      `json:"priority"`
+	IsDefault   bool       `json:"is_default"`
+}
+
+func (q *Queries) UpdatePromotion(ctx context.Context, arg UpdatePromotionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updatePromotion,
+		arg.PromotionID,
+		arg.Slot,
+		arg.VideoID,
+		arg.Title,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Timezone,
+		arg.Priority,
+		arg.IsDefault,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
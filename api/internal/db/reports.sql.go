@@ -0,0 +1,222 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: reports.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const countVideoReporters = `-- name: CountVideoReporters :one
+SELECT COUNT(DISTINCT client_hash)::int FROM video_reports
+WHERE video_id = $1 AND resolved_at IS NULL
+`
+
+// How many distinct clients have open reports on a video.
+func (q *Queries) CountVideoReporters(ctx context.Context, videoID int32) (int32, error) {
+	row := q.db.QueryRow(ctx, countVideoReporters, videoID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createVideoReport = `-- name: CreateVideoReport :one
+INSERT INTO video_reports (video_id, reason, note, client_hash)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (video_id, client_hash, reason) WHERE resolved_at IS NULL DO UPDATE
+SET report_count = video_reports.report_count + 1,
+    last_reported_at = now(),
+    note = COALESCE(video_reports.note, EXCLUDED.note)
+RETURNING (xmax = 0)::bool AS inserted
+`
+
+type CreateVideoReportParams struct {
+	VideoID    int32   `json:"video_id"`
+	Reason     string  `json:"reason"`
+	Note       *string `json:"note"`
+	ClientHash string  `json:"client_hash"`
+}
+
+// Opens a report, or counts a repeat on the client's open report for this
+// reason (keeping its first note). inserted is false for a repeat.
+func (q *Queries) CreateVideoReport(ctx context.Context, arg CreateVideoReportParams) (bool, error) {
+	row := q.db.QueryRow(ctx, createVideoReport,
+		arg.VideoID,
+		arg.Reason,
+		arg.Note,
+		arg.ClientHash,
+	)
+	var inserted bool
+	err := row.Scan(&inserted)
+	return inserted, err
+}
+
+const flagReportedVideo = `-- name: FlagReportedVideo :execrows
+UPDATE videos SET status = 'maintenance'
+WHERE video_id = $1 AND status = 'published' AND deleted_at IS NULL
+`
+
+// Puts a published video into maintenance. No row means it wasn't published.
+func (q *Queries) FlagReportedVideo(ctx context.Context, videoID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, flagReportedVideo, videoID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getListedVideoStatus = `-- name: GetListedVideoStatus :one
+SELECT status FROM videos
+WHERE video_id = $1 AND status IN ('published', 'maintenance', 'offline') AND deleted_at IS NULL
+`
+
+func (q *Queries) GetListedVideoStatus(ctx context.Context, videoID int32) (string, error) {
+	row := q.db.QueryRow(ctx, getListedVideoStatus, videoID)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const listReportedVideos = `-- name: ListReportedVideos :many
+SELECT v.video_id, v.title, v.status, s.name AS state_name,
+       SUM(r.report_count)::int AS report_count,
+       COUNT(DISTINCT r.client_hash)::int AS reporter_count,
+       (COUNT(DISTINCT r.client_hash) FILTER (WHERE r.reason = 'offline'))::int AS offline_reporters,
+       (COUNT(DISTINCT r.client_hash) FILTER (WHERE r.reason = 'wrong_location'))::int AS wrong_location_reporters,
+       (COUNT(DISTINCT r.client_hash) FILTER (WHERE r.reason = 'inappropriate'))::int AS inappropriate_reporters,
+       (COALESCE(SUM(r.report_count) FILTER (WHERE r.reason = 'offline'), 0))::int AS offline_count,
+       (COALESCE(SUM(r.report_count) FILTER (WHERE r.reason = 'wrong_location'), 0))::int AS wrong_location_count,
+       (COALESCE(SUM(r.report_count) FILTER (WHERE r.reason = 'inappropriate'), 0))::int AS inappropriate_count,
+       MIN(r.created_at)::timestamptz AS first_reported_at,
+       MAX(r.last_reported_at)::timestamptz AS last_reported_at
+FROM video_reports r
+JOIN videos v ON v.video_id = r.video_id
+JOIN states s ON s.state_id = v.state_id
+WHERE r.resolved_at IS NULL AND v.deleted_at IS NULL
+GROUP BY v.video_id, s.name
+ORDER BY COUNT(DISTINCT r.client_hash) DESC, SUM(r.report_count) DESC, MAX(r.last_reported_at) DESC, v.video_id
+`
+
+type ListReportedVideosRow struct {
+	VideoID                int32     `json:"video_id"`
+	Title                  string    `json:"title"`
+	Status                 string    `json:"status"`
+	StateName              string    `json:"state_name"`
+	ReportCount            int32     `json:"report_count"`
+	ReporterCount          int32     `json:"reporter_count"`
+	OfflineReporters       int32     `json:"offline_reporters"`
+	WrongLocationReporters int32     `json:"wrong_location_reporters"`
+	InappropriateReporters int32     `json:"inappropriate_reporters"`
+	OfflineCount           int32     `json:"offline_count"`
+	WrongLocationCount     int32     `json:"wrong_location_count"`
+	InappropriateCount     int32     `json:"inappropriate_count"`
+	FirstReportedAt        time.Time `json:"first_reported_at"`
+	LastReportedAt         time.Time `json:"last_reported_at"`
+}
+
+// Videos with open reports, most reported first, with counts by reason.
+// report_count includes repeats; reporter_count is distinct client hashes,
+// each of which may be many viewers sharing an address.
+func (q *Queries) ListReportedVideos(ctx context.Context) ([]ListReportedVideosRow, error) {
+	rows, err := q.db.Query(ctx, listReportedVideos)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListReportedVideosRow{}
+	for rows.Next() {
+		var i ListReportedVideosRow
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.Status,
+			&i.StateName,
+			&i.ReportCount,
+			&i.ReporterCount,
+			&i.OfflineReporters,
+			&i.WrongLocationReporters,
+			&i.InappropriateReporters,
+			&i.OfflineCount,
+			&i.WrongLocationCount,
+			&i.InappropriateCount,
+			&i.FirstReportedAt,
+			&i.LastReportedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideoReports = `-- name: ListVideoReports :many
+SELECT report_id, video_id, reason, note, client_hash, report_count, created_at, last_reported_at
+FROM video_reports
+WHERE video_id = $1 AND resolved_at IS NULL
+ORDER BY last_reported_at DESC, report_id DESC
+`
+
+type ListVideoReportsRow struct {
+	ReportID       int32     `json:"report_id"`
+	VideoID        int32     `json:"video_id"`
+	Reason         string    `json:"reason"`
+	Note           *string   `json:"note"`
+	ClientHash     string    `json:"client_hash"`
+	ReportCount    int32     `json:"report_count"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastReportedAt time.Time `json:"last_reported_at"`
+}
+
+// A video's open reports, most recently reported first.
+func (q *Queries) ListVideoReports(ctx context.Context, videoID int32) ([]ListVideoReportsRow, error) {
+	rows, err := q.db.Query(ctx, listVideoReports, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVideoReportsRow{}
+	for rows.Next() {
+		var i ListVideoReportsRow
+		if err := rows.Scan(
+			&i.ReportID,
+			&i.VideoID,
+			&i.Reason,
+			&i.Note,
+			&i.ClientHash,
+			&i.ReportCount,
+			&i.CreatedAt,
+			&i.LastReportedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resolveVideoReports = `-- name: ResolveVideoReports :execrows
+UPDATE video_reports SET resolved_at = now(), resolved_by = $2
+WHERE video_id = $1 AND resolved_at IS NULL
+`
+
+type ResolveVideoReportsParams struct {
+	VideoID    int32   `json:"video_id"`
+	ResolvedBy *string `json:"resolved_by"`
+}
+
+func (q *Queries) ResolveVideoReports(ctx context.Context, arg ResolveVideoReportsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, resolveVideoReports, arg.VideoID, arg.ResolvedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
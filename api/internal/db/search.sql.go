@@ -0,0 +1,89 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: search.sql
+
+package db
+
+import (
+	"context"
+)
+
+const search = `-- name: Search :many
+WITH q AS (SELECT to_tsquery('simple', $2::text) AS tsq)
+SELECT kind, id, name, slug, description, state_name, state_slug, rank FROM (
+  SELECT 'state'::text AS kind, s.state_id AS id, s.name, s.slug,
+         s.description, s.name AS state_name, s.slug AS state_slug,
+         ts_rank(s.search_vector, q.tsq)::float4 AS rank
+  FROM states s, q
+  WHERE s.deleted_at IS NULL AND s.search_vector @@ q.tsq
+
+  UNION ALL
+
+  SELECT 'sublocation'::text, sub.sublocation_id, sub.name, sub.slug,
+         sub.description, st.name, st.slug,
+         ts_rank(sub.search_vector, q.tsq)::float4
+  FROM sublocations sub
+  JOIN states st ON st.state_id = sub.state_id, q
+  WHERE sub.deleted_at IS NULL AND sub.search_vector @@ q.tsq
+
+  UNION ALL
+
+  SELECT 'video'::text, v.video_id, v.title, ''::text,
+         COALESCE(vsub.name, ''), st.name, st.slug,
+         ts_rank(v.search_vector, q.tsq)::float4
+  FROM videos v
+  JOIN states st ON st.state_id = v.state_id
+  LEFT JOIN sublocations vsub ON vsub.sublocation_id = v.sublocation_id AND vsub.deleted_at IS NULL, q
+  WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL AND v.search_vector @@ q.tsq
+) results
+ORDER BY rank DESC, name
+LIMIT $1
+`
+
+type SearchParams struct {
+	MaxResults int32  `json:"max_results"`
+	Query      string `json:"query"`
+}
+
+type SearchRow struct {
+	Kind        string  `json:"kind"`
+	ID          int32   `json:"id"`
+	Name        string  `json:"name"`
+	Slug        string  `json:"slug"`
+	Description string  `json:"description"`
+	StateName   string  `json:"state_name"`
+	StateSlug   string  `json:"state_slug"`
+	Rank        float32 `json:"rank"`
+}
+
+// Ranked search across states, sublocations and listed videos.
+// query must be a valid to_tsquery('simple', ...) expression.
+func (q *Queries) Search(ctx context.Context, arg SearchParams) ([]SearchRow, error) {
+	rows, err := q.db.Query(ctx, search, arg.MaxResults, arg.Query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchRow{}
+	for rows.Next() {
+		var i SearchRow
+		if err := rows.Scan(
+			&i.Kind,
+			&i.ID,
+			&i.Name,
+			&i.Slug,
+			&i.Description,
+			&i.StateName,
+			&i.StateSlug,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
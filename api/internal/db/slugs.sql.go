@@ -0,0 +1,55 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: slugs.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const listSlugHistory = `-- name: ListSlugHistory :many
+SELECT entity_type, slug, entity_id, created_at
+FROM slug_history
+WHERE entity_type = $1 AND entity_id = $2
+ORDER BY created_at DESC
+`
+
+type ListSlugHistoryParams struct {
+	EntityType string `json:"entity_type"`
+	EntityID   int32  `json:"entity_id"`
+}
+
+type ListSlugHistoryRow struct {
+	EntityType string    `json:"entity_type"`
+	Slug       string    `json:"slug"`
+	EntityID   int32     `json:"entity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (q *Queries) ListSlugHistory(ctx context.Context, arg ListSlugHistoryParams) ([]ListSlugHistoryRow, error) {
+	rows, err := q.db.Query(ctx, listSlugHistory, arg.EntityType, arg.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSlugHistoryRow{}
+	for rows.Next() {
+		var i ListSlugHistoryRow
+		if err := rows.Scan(
+			&i.EntityType,
+			&i.Slug,
+			&i.EntityID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -10,45 +10,77 @@ import (
 	"time"
 )
 
+const changeStateSlug = `-- name: ChangeStateSlug :one
+UPDATE states SET slug = $1
+WHERE state_id = $2 AND deleted_at IS NULL
+RETURNING slug
+`
+
+type ChangeStateSlugParams struct {
+	Slug    string `json:"slug"`
+	StateID int32  `json:"state_id"`
+}
+
+func (q *Queries) ChangeStateSlug(ctx context.Context, arg ChangeStateSlugParams) (string, error) {
+	row := q.db.QueryRow(ctx, changeStateSlug, arg.Slug, arg.StateID)
+	var slug string
+	err := row.Scan(&slug)
+	return slug, err
+}
+
 const createState = `-- name: CreateState :one
-INSERT INTO states (name, description)
-VALUES ($1, $2)
-RETURNING state_id, name, description, slug, created_at, updated_at
+INSERT INTO states (name, description, slug, country_code, region_type)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING state_id, name, description, slug, country_code, region_type, created_at, updated_at
 `
 
 type CreateStateParams struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Slug        string `json:"slug"`
+	CountryCode string `json:"country_code"`
+	RegionType  string `json:"region_type"`
 }
 
-func (q *Queries) CreateState(ctx context.Context, arg CreateStateParams) (State, error) {
-	row := q.db.QueryRow(ctx, createState, arg.Name, arg.Description)
-	var i State
+type CreateStateRow struct {
+	StateID     int32     `json:"state_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Slug        string    `json:"slug"`
+	CountryCode string    `json:"country_code"`
+	RegionType  string    `json:"region_type"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) CreateState(ctx context.Context, arg CreateStateParams) (CreateStateRow, error) {
+	row := q.db.QueryRow(ctx, createState,
+		arg.Name,
+		arg.Description,
+		arg.Slug,
+		arg.CountryCode,
+		arg.RegionType,
+	)
+	var i CreateStateRow
 	err := row.Scan(
 		&i.StateID,
 		&i.Name,
 		&i.Description,
 		&i.Slug,
+		&i.CountryCode,
+		&i.RegionType,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const deleteState = `-- name: DeleteState :exec
-DELETE FROM states WHERE slug = $1
-`
-
-func (q *Queries) DeleteState(ctx context.Context, slug string) error {
-	_, err := q.db.Exec(ctx, deleteState, slug)
-	return err
-}
-
 const getStateByID = `-- name: GetStateByID :one
-SELECT s.state_id, s.name, s.description, s.slug, s.created_at, s.updated_at,
+SELECT s.state_id, s.name, s.description, s.slug, s.country_code, s.region_type,
+       s.sort_order, s.created_at, s.updated_at,
        COUNT(v.video_id)::int AS video_count
 FROM states s
-LEFT JOIN videos v ON v.state_id = s.state_id AND v.status = 'active'
+LEFT JOIN videos v ON v.state_id = s.state_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
 WHERE s.state_id = $1
 GROUP BY s.state_id
 `
@@ -58,6 +90,9 @@ type GetStateByIDRow struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Slug        string    `json:"slug"`
+	CountryCode string    `json:"country_code"`
+	RegionType  string    `json:"region_type"`
+	SortOrder   *int32    `json:"sort_order"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	VideoCount  int32     `json:"video_count"`
@@ -71,6 +106,9 @@ func (q *Queries) GetStateByID(ctx context.Context, stateID int32) (GetStateByID
 		&i.Name,
 		&i.Description,
 		&i.Slug,
+		&i.CountryCode,
+		&i.RegionType,
+		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.VideoCount,
@@ -78,12 +116,36 @@ func (q *Queries) GetStateByID(ctx context.Context, stateID int32) (GetStateByID
 	return i, err
 }
 
+const getStateByRef = `-- name: GetStateByRef :one
+SELECT state_id, name, slug
+FROM states
+WHERE deleted_at IS NULL
+  AND (slug = lower($1::text) OR lower(name) = lower($1::text))
+ORDER BY slug = lower($1::text) DESC
+LIMIT 1
+`
+
+type GetStateByRefRow struct {
+	StateID int32  `json:"state_id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+}
+
+// Resolves a live state by slug or case-insensitive name, preferring a slug match.
+func (q *Queries) GetStateByRef(ctx context.Context, ref string) (GetStateByRefRow, error) {
+	row := q.db.QueryRow(ctx, getStateByRef, ref)
+	var i GetStateByRefRow
+	err := row.Scan(&i.StateID, &i.Name, &i.Slug)
+	return i, err
+}
+
 const getStateBySlug = `-- name: GetStateBySlug :one
-SELECT s.state_id, s.name, s.description, s.slug, s.created_at, s.updated_at,
+SELECT s.state_id, s.name, s.description, s.slug, s.country_code, s.region_type,
+       s.sort_order, s.created_at, s.updated_at,
        COUNT(v.video_id)::int AS video_count
 FROM states s
-LEFT JOIN videos v ON v.state_id = s.state_id AND v.status = 'active'
-WHERE s.slug = $1
+LEFT JOIN videos v ON v.state_id = s.state_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE s.slug = $1 AND s.deleted_at IS NULL
 GROUP BY s.state_id
 `
 
@@ -92,6 +154,9 @@ type GetStateBySlugRow struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Slug        string    `json:"slug"`
+	CountryCode string    `json:"country_code"`
+	RegionType  string    `json:"region_type"`
+	SortOrder   *int32    `json:"sort_order"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	VideoCount  int32     `json:"video_count"`
@@ -105,6 +170,9 @@ func (q *Queries) GetStateBySlug(ctx context.Context, slug string) (GetStateBySl
 		&i.Name,
 		&i.Description,
 		&i.Slug,
+		&i.CountryCode,
+		&i.RegionType,
+		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.VideoCount,
@@ -112,46 +180,46 @@ func (q *Queries) GetStateBySlug(ctx context.Context, slug string) (GetStateBySl
 	return i, err
 }
 
-const listStates = `-- name: ListStates :many
-SELECT s.state_id, s.name, s.description, s.slug, s.created_at, s.updated_at,
-       COUNT(v.video_id)::int AS video_count
-FROM states s
-LEFT JOIN videos v ON v.state_id = s.state_id AND v.status = 'active'
-GROUP BY s.state_id
-ORDER BY s.name
+const getStateSlugRedirect = `-- name: GetStateSlugRedirect :one
+SELECT s.slug
+FROM slug_history h
+JOIN states s ON s.state_id = h.entity_id
+WHERE h.entity_type = 'state' AND h.slug = $1 AND s.deleted_at IS NULL
 `
 
-type ListStatesRow struct {
-	StateID     int32     `json:"state_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Slug        string    `json:"slug"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	VideoCount  int32     `json:"video_count"`
+// The current slug of the live state that used to be reachable at slug.
+func (q *Queries) GetStateSlugRedirect(ctx context.Context, slug string) (string, error) {
+	row := q.db.QueryRow(ctx, getStateSlugRedirect, slug)
+	err := row.Scan(&slug)
+	return slug, err
+}
+
+const listStateSlugs = `-- name: ListStateSlugs :many
+SELECT slug FROM states
+WHERE (slug = $1::text OR slug LIKE $1::text || '-%')
+  AND state_id <> $2
+`
+
+type ListStateSlugsParams struct {
+	Base     string `json:"base"`
+	ExceptID int32  `json:"except_id"`
 }
 
-func (q *Queries) ListStates(ctx context.Context) ([]ListStatesRow, error) {
-	rows, err := q.db.Query(ctx, listStates)
+// Slugs equal to base or base plus a "-N" suffix, trashed states included
+// (they still hold their slug). except_id's own slug is left out.
+func (q *Queries) ListStateSlugs(ctx context.Context, arg ListStateSlugsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listStateSlugs, arg.Base, arg.ExceptID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListStatesRow{}
+	items := []string{}
 	for rows.Next() {
-		var i ListStatesRow
-		if err := rows.Scan(
-			&i.StateID,
-			&i.Name,
-			&i.Description,
-			&i.Slug,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.VideoCount,
-		); err != nil {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, slug)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -159,51 +227,51 @@ func (q *Queries) ListStates(ctx context.Context) ([]ListStatesRow, error) {
 	return items, nil
 }
 
-const listStatesPaginated = `-- name: ListStatesPaginated :many
-SELECT s.state_id, s.name, s.description, s.slug, s.created_at, s.updated_at,
-       COUNT(v.video_id)::int AS video_count,
-       COUNT(*) OVER()::int AS total_count
+const listStates = `-- name: ListStates :many
+SELECT s.state_id, s.name, s.description, s.slug, s.country_code, s.region_type,
+       s.sort_order, s.created_at, s.updated_at,
+       COUNT(v.video_id)::int AS video_count
 FROM states s
-LEFT JOIN videos v ON v.state_id = s.state_id AND v.status = 'active'
+LEFT JOIN videos v ON v.state_id = s.state_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE s.deleted_at IS NULL AND s.country_code = $1
 GROUP BY s.state_id
-ORDER BY s.name
-LIMIT $1 OFFSET $2
+ORDER BY s.sort_order NULLS LAST, s.name
 `
 
-type ListStatesPaginatedParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
-}
-
-type ListStatesPaginatedRow struct {
+type ListStatesRow struct {
 	StateID     int32     `json:"state_id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Slug        string    `json:"slug"`
+	CountryCode string    `json:"country_code"`
+	RegionType  string    `json:"region_type"`
+	SortOrder   *int32    `json:"sort_order"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	VideoCount  int32     `json:"video_count"`
-	TotalCount  int32     `json:"total_count"`
 }
 
-func (q *Queries) ListStatesPaginated(ctx context.Context, arg ListStatesPaginatedParams) ([]ListStatesPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, listStatesPaginated, arg.Limit, arg.Offset)
+// The regions of one country; /states lists the US.
+func (q *Queries) ListStates(ctx context.Context, countryCode string) ([]ListStatesRow, error) {
+	rows, err := q.db.Query(ctx, listStates, countryCode)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListStatesPaginatedRow{}
+	items := []ListStatesRow{}
 	for rows.Next() {
-		var i ListStatesPaginatedRow
+		var i ListStatesRow
 		if err := rows.Scan(
 			&i.StateID,
 			&i.Name,
 			&i.Description,
 			&i.Slug,
+			&i.CountryCode,
+			&i.RegionType,
+			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.VideoCount,
-			&i.TotalCount,
 		); err != nil {
 			return nil, err
 		}
@@ -215,17 +283,76 @@ func (q *Queries) ListStatesPaginated(ctx context.Context, arg ListStatesPaginat
 	return items, nil
 }
 
+const reorderStates = `-- name: ReorderStates :one
+WITH ranked AS (
+  SELECT s.state_id, s.sort_order,
+         ROW_NUMBER() OVER (ORDER BY s.sort_order NULLS LAST, s.name, s.state_id)::int AS slot
+  FROM states s WHERE s.deleted_at IS NULL
+), listed AS (
+  SELECT r.state_id, r.slot, ROW_NUMBER() OVER (ORDER BY o.position) AS rank
+  FROM unnest($1::int[]) WITH ORDINALITY AS o(state_id, position)
+  JOIN ranked r ON r.state_id = o.state_id
+), slots AS (
+  SELECT slot, ROW_NUMBER() OVER (ORDER BY slot) AS rank FROM listed
+), target AS (
+  SELECT l.state_id, s.slot FROM listed l JOIN slots s ON s.rank = l.rank
+  UNION ALL
+  SELECT r.state_id, r.slot FROM ranked r
+  WHERE r.sort_order IS NOT NULL AND r.state_id NOT IN (SELECT state_id FROM listed)
+), moved AS (
+  UPDATE states s SET sort_order = t.slot
+  FROM target t
+  WHERE s.state_id = t.state_id AND s.sort_order IS DISTINCT FROM t.slot
+  RETURNING s.state_id
+)
+SELECT COUNT(*) FROM listed
+`
+
+// Puts ids in the given order, in the positions they hold now: states left
+// out keep theirs, and positions stay unique. Unpositioned states (NULL)
+// stay so unless listed. Returns how many of ids are live.
+func (q *Queries) ReorderStates(ctx context.Context, ids []int32) (int64, error) {
+	row := q.db.QueryRow(ctx, reorderStates, ids)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const softDeleteState = `-- name: SoftDeleteState :one
+UPDATE states SET deleted_at = now()
+WHERE slug = $1 AND deleted_at IS NULL
+RETURNING state_id, deleted_at::timestamptz AS deleted_at
+`
+
+type SoftDeleteStateRow struct {
+	StateID   int32     `json:"state_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func (q *Queries) SoftDeleteState(ctx context.Context, slug string) (SoftDeleteStateRow, error) {
+	row := q.db.QueryRow(ctx, softDeleteState, slug)
+	var i SoftDeleteStateRow
+	err := row.Scan(&i.StateID, &i.DeletedAt)
+	return i, err
+}
+
 const updateState = `-- name: UpdateState :exec
-UPDATE states SET name = $2, description = $3 WHERE state_id = $1
+UPDATE states SET name = $2, description = $3, region_type = $4 WHERE state_id = $1
 `
 
 type UpdateStateParams struct {
 	StateID     int32  `json:"state_id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	RegionType  string `json:"region_type"`
 }
 
 func (q *Queries) UpdateState(ctx context.Context, arg UpdateStateParams) error {
-	_, err := q.db.Exec(ctx, updateState, arg.StateID, arg.Name, arg.Description)
+	_, err := q.db.Exec(ctx, updateState,
+		arg.StateID,
+		arg.Name,
+		arg.Description,
+		arg.RegionType,
+	)
 	return err
 }
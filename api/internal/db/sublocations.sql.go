@@ -10,26 +10,76 @@ import (
 	"time"
 )
 
+const changeSublocationSlug = `-- name: ChangeSublocationSlug :one
+UPDATE sublocations SET slug = $1
+WHERE sublocation_id = $2 AND deleted_at IS NULL
+RETURNING slug
+`
+
+type ChangeSublocationSlugParams struct {
+	Slug          string `json:"slug"`
+	SublocationID int32  `json:"sublocation_id"`
+}
+
+func (q *Queries) ChangeSublocationSlug(ctx context.Context, arg ChangeSublocationSlugParams) (string, error) {
+	row := q.db.QueryRow(ctx, changeSublocationSlug, arg.Slug, arg.SublocationID)
+	var slug string
+	err := row.Scan(&slug)
+	return slug, err
+}
+
+const countSublocationChildren = `-- name: CountSublocationChildren :one
+SELECT COUNT(*)::int FROM sublocations
+WHERE parent_id = $1::int AND deleted_at IS NULL
+`
+
+func (q *Queries) CountSublocationChildren(ctx context.Context, sublocationID int32) (int32, error) {
+	row := q.db.QueryRow(ctx, countSublocationChildren, sublocationID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const createSublocation = `-- name: CreateSublocation :one
-INSERT INTO sublocations (name, description, state_id)
-VALUES ($1, $2, $3)
-RETURNING sublocation_id, name, description, state_id, slug, created_at, updated_at
+INSERT INTO sublocations (name, description, state_id, parent_id, slug)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING sublocation_id, name, description, state_id, parent_id, slug, created_at, updated_at
 `
 
 type CreateSublocationParams struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	StateID     int32  `json:"state_id"`
+	ParentID    *int32 `json:"parent_id"`
+	Slug        string `json:"slug"`
 }
 
-func (q *Queries) CreateSublocation(ctx context.Context, arg CreateSublocationParams) (Sublocation, error) {
-	row := q.db.QueryRow(ctx, createSublocation, arg.Name, arg.Description, arg.StateID)
-	var i Sublocation
+type CreateSublocationRow struct {
+	SublocationID int32     `json:"sublocation_id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	StateID       int32     `json:"state_id"`
+	ParentID      *int32    `json:"parent_id"`
+	Slug          string    `json:"slug"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (q *Queries) CreateSublocation(ctx context.Context, arg CreateSublocationParams) (CreateSublocationRow, error) {
+	row := q.db.QueryRow(ctx, createSublocation,
+		arg.Name,
+		arg.Description,
+		arg.StateID,
+		arg.ParentID,
+		arg.Slug,
+	)
+	var i CreateSublocationRow
 	err := row.Scan(
 		&i.SublocationID,
 		&i.Name,
 		&i.Description,
 		&i.StateID,
+		&i.ParentID,
 		&i.Slug,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -37,23 +87,14 @@ func (q *Queries) CreateSublocation(ctx context.Context, arg CreateSublocationPa
 	return i, err
 }
 
-const deleteSublocation = `-- name: DeleteSublocation :exec
-DELETE FROM sublocations WHERE sublocation_id = $1
-`
-
-func (q *Queries) DeleteSublocation(ctx context.Context, sublocationID int32) error {
-	_, err := q.db.Exec(ctx, deleteSublocation, sublocationID)
-	return err
-}
-
 const getSublocationByID = `-- name: GetSublocationByID :one
-SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.slug,
-       sub.created_at, sub.updated_at,
+SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.parent_id, sub.slug,
+       sub.sort_order, sub.created_at, sub.updated_at,
        s.name AS state_name,
        COUNT(v.video_id)::int AS video_count
 FROM sublocations sub
 JOIN states s ON s.state_id = sub.state_id
-LEFT JOIN videos v ON v.sublocation_id = sub.sublocation_id AND v.status = 'active'
+LEFT JOIN videos v ON v.sublocation_id IN (SELECT sublocation_subtree(sub.sublocation_id)) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
 WHERE sub.sublocation_id = $1
 GROUP BY sub.sublocation_id, s.name
 `
@@ -63,7 +104,9 @@ type GetSublocationByIDRow struct {
 	Name          string    `json:"name"`
 	Description   string    `json:"description"`
 	StateID       int32     `json:"state_id"`
+	ParentID      *int32    `json:"parent_id"`
 	Slug          string    `json:"slug"`
+	SortOrder     *int32    `json:"sort_order"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 	StateName     string    `json:"state_name"`
@@ -78,7 +121,9 @@ func (q *Queries) GetSublocationByID(ctx context.Context, sublocationID int32) (
 		&i.Name,
 		&i.Description,
 		&i.StateID,
+		&i.ParentID,
 		&i.Slug,
+		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.StateName,
@@ -87,39 +132,82 @@ func (q *Queries) GetSublocationByID(ctx context.Context, sublocationID int32) (
 	return i, err
 }
 
+const getSublocationByRef = `-- name: GetSublocationByRef :one
+SELECT sublocation_id, name, slug
+FROM sublocations
+WHERE deleted_at IS NULL
+  AND state_id = $1
+  AND (slug = lower($2::text) OR lower(name) = lower($2::text))
+ORDER BY slug = lower($2::text) DESC
+LIMIT 1
+`
+
+type GetSublocationByRefParams struct {
+	StateID int32  `json:"state_id"`
+	Ref     string `json:"ref"`
+}
+
+type GetSublocationByRefRow struct {
+	SublocationID int32  `json:"sublocation_id"`
+	Name          string `json:"name"`
+	Slug          string `json:"slug"`
+}
+
+// Resolves a live sublocation within a state by slug or case-insensitive name,
+// preferring a slug match.
+func (q *Queries) GetSublocationByRef(ctx context.Context, arg GetSublocationByRefParams) (GetSublocationByRefRow, error) {
+	row := q.db.QueryRow(ctx, getSublocationByRef, arg.StateID, arg.Ref)
+	var i GetSublocationByRefRow
+	err := row.Scan(&i.SublocationID, &i.Name, &i.Slug)
+	return i, err
+}
+
 const getSublocationBySlug = `-- name: GetSublocationBySlug :one
-SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.slug,
-       sub.created_at, sub.updated_at,
+SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.parent_id, sub.slug,
+       sub.sort_order, sub.created_at, sub.updated_at,
        s.name AS state_name,
        COUNT(v.video_id)::int AS video_count
 FROM sublocations sub
 JOIN states s ON s.state_id = sub.state_id
-LEFT JOIN videos v ON v.sublocation_id = sub.sublocation_id AND v.status = 'active'
-WHERE sub.slug = $1
+LEFT JOIN videos v ON v.sublocation_id IN (SELECT sublocation_subtree(sub.sublocation_id)) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE sub.slug = $1 AND sub.deleted_at IS NULL
+  AND ($2::text IS NULL OR s.slug = $2::text)
 GROUP BY sub.sublocation_id, s.name
+ORDER BY sub.sublocation_id
+LIMIT 1
 `
 
+type GetSublocationBySlugParams struct {
+	Slug  string  `json:"slug"`
+	State *string `json:"state"`
+}
+
 type GetSublocationBySlugRow struct {
 	SublocationID int32     `json:"sublocation_id"`
 	Name          string    `json:"name"`
 	Description   string    `json:"description"`
 	StateID       int32     `json:"state_id"`
+	ParentID      *int32    `json:"parent_id"`
 	Slug          string    `json:"slug"`
+	SortOrder     *int32    `json:"sort_order"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 	StateName     string    `json:"state_name"`
 	VideoCount    int32     `json:"video_count"`
 }
 
-func (q *Queries) GetSublocationBySlug(ctx context.Context, slug string) (GetSublocationBySlugRow, error) {
-	row := q.db.QueryRow(ctx, getSublocationBySlug, slug)
+// Slugs are unique per state only; state, a state slug, picks between them.
+func (q *Queries) GetSublocationBySlug(ctx context.Context, arg GetSublocationBySlugParams) (GetSublocationBySlugRow, error) {
+	row := q.db.QueryRow(ctx, getSublocationBySlug, arg.Slug, arg.State)
 	var i GetSublocationBySlugRow
 	err := row.Scan(
 		&i.SublocationID,
 		&i.Name,
 		&i.Description,
 		&i.StateID,
+		&i.ParentID,
 		&i.Slug,
+		&i.SortOrder,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.StateName,
@@ -128,51 +216,88 @@ func (q *Queries) GetSublocationBySlug(ctx context.Context, slug string) (GetSub
 	return i, err
 }
 
-const listSublocationsByState = `-- name: ListSublocationsByState :many
-SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.slug,
-       sub.created_at, sub.updated_at,
-       s.name AS state_name,
-       COUNT(v.video_id)::int AS video_count
-FROM sublocations sub
-JOIN states s ON s.state_id = sub.state_id
-LEFT JOIN videos v ON v.sublocation_id = sub.sublocation_id AND v.status = 'active'
-WHERE sub.state_id = $1
-GROUP BY sub.sublocation_id, s.name
-ORDER BY sub.name
+const getSublocationSlugRedirect = `-- name: GetSublocationSlugRedirect :one
+SELECT sub.slug
+FROM slug_history h
+JOIN sublocations sub ON sub.sublocation_id = h.entity_id
+JOIN states s ON s.state_id = h.scope_id
+WHERE h.entity_type = 'sublocation' AND h.slug = $1 AND sub.deleted_at IS NULL
+  AND ($2::text IS NULL OR s.slug = $2::text)
+ORDER BY h.created_at DESC
+LIMIT 1
 `
 
-type ListSublocationsByStateRow struct {
-	SublocationID int32     `json:"sublocation_id"`
-	Name          string    `json:"name"`
-	Description   string    `json:"description"`
-	StateID       int32     `json:"state_id"`
-	Slug          string    `json:"slug"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	StateName     string    `json:"state_name"`
-	VideoCount    int32     `json:"video_count"`
+type GetSublocationSlugRedirectParams struct {
+	Slug  string  `json:"slug"`
+	State *string `json:"state"`
 }
 
-func (q *Queries) ListSublocationsByState(ctx context.Context, stateID int32) ([]ListSublocationsByStateRow, error) {
-	rows, err := q.db.Query(ctx, listSublocationsByState, stateID)
+// The current slug of the live sublocation that used to be reachable at slug,
+// in state if given (history is kept per state), else the latest renamed.
+func (q *Queries) GetSublocationSlugRedirect(ctx context.Context, arg GetSublocationSlugRedirectParams) (string, error) {
+	row := q.db.QueryRow(ctx, getSublocationSlugRedirect, arg.Slug, arg.State)
+	var slug string
+	err := row.Scan(&slug)
+	return slug, err
+}
+
+const isSublocationInSubtree = `-- name: IsSublocationInSubtree :one
+WITH RECURSIVE down AS (
+  SELECT $2::int AS sublocation_id
+  UNION
+  SELECT c.sublocation_id FROM sublocations c JOIN down ON c.parent_id = down.sublocation_id
+)
+SELECT EXISTS (SELECT 1 FROM down WHERE sublocation_id = $1::int)
+`
+
+type IsSublocationInSubtreeParams struct {
+	Candidate int32 `json:"candidate"`
+	Root      int32 `json:"root"`
+}
+
+// Whether candidate is root or one of its descendants, trashed ones included.
+func (q *Queries) IsSublocationInSubtree(ctx context.Context, arg IsSublocationInSubtreeParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isSublocationInSubtree, arg.Candidate, arg.Root)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listSublocationAncestors = `-- name: ListSublocationAncestors :many
+WITH RECURSIVE up AS (
+  SELECT p.sublocation_id, p.parent_id, p.name, p.slug, 1 AS depth
+  FROM sublocations sub
+  JOIN sublocations p ON p.sublocation_id = sub.parent_id
+  WHERE sub.sublocation_id = $1
+  UNION
+  SELECT p.sublocation_id, p.parent_id, p.name, p.slug, up.depth + 1
+  FROM sublocations p
+  JOIN up ON p.sublocation_id = up.parent_id
+)
+SELECT up.sublocation_id, up.name, up.slug
+FROM up
+JOIN sublocations sub ON sub.sublocation_id = up.sublocation_id
+WHERE sub.deleted_at IS NULL
+ORDER BY up.depth DESC
+`
+
+type ListSublocationAncestorsRow struct {
+	SublocationID int32  `json:"sublocation_id"`
+	Name          string `json:"name"`
+	Slug          string `json:"slug"`
+}
+
+// Breadcrumbs: the live ancestors of a sublocation, root first.
+func (q *Queries) ListSublocationAncestors(ctx context.Context, sublocationID int32) ([]ListSublocationAncestorsRow, error) {
+	rows, err := q.db.Query(ctx, listSublocationAncestors, sublocationID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListSublocationsByStateRow{}
+	items := []ListSublocationAncestorsRow{}
 	for rows.Next() {
-		var i ListSublocationsByStateRow
-		if err := rows.Scan(
-			&i.SublocationID,
-			&i.Name,
-			&i.Description,
-			&i.StateID,
-			&i.Slug,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.StateName,
-			&i.VideoCount,
-		); err != nil {
+		var i ListSublocationAncestorsRow
+		if err := rows.Scan(&i.SublocationID, &i.Name, &i.Slug); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -183,58 +308,89 @@ func (q *Queries) ListSublocationsByState(ctx context.Context, stateID int32) ([
 	return items, nil
 }
 
-const listSublocationsPaginated = `-- name: ListSublocationsPaginated :many
-SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.slug,
-       sub.created_at, sub.updated_at,
+const listSublocationSlugs = `-- name: ListSublocationSlugs :many
+SELECT slug FROM sublocations
+WHERE state_id = $1
+  AND (slug = $2::text OR slug LIKE $2::text || '-%')
+  AND sublocation_id <> $3
+`
+
+type ListSublocationSlugsParams struct {
+	StateID  int32  `json:"state_id"`
+	Base     string `json:"base"`
+	ExceptID int32  `json:"except_id"`
+}
+
+// ListStateSlugs for the sublocations of one state, where slugs are unique.
+func (q *Queries) ListSublocationSlugs(ctx context.Context, arg ListSublocationSlugsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listSublocationSlugs, arg.StateID, arg.Base, arg.ExceptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSublocationsByState = `-- name: ListSublocationsByState :many
+SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.parent_id, sub.slug,
+       sub.sort_order, sub.created_at, sub.updated_at,
        s.name AS state_name,
-       COUNT(v.video_id)::int AS video_count,
-       COUNT(*) OVER()::int AS total_count
+       COUNT(v.video_id)::int AS video_count
 FROM sublocations sub
 JOIN states s ON s.state_id = sub.state_id
-LEFT JOIN videos v ON v.sublocation_id = sub.sublocation_id AND v.status = 'active'
+LEFT JOIN videos v ON v.sublocation_id IN (SELECT sublocation_subtree(sub.sublocation_id)) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE sub.state_id = $1 AND sub.deleted_at IS NULL
 GROUP BY sub.sublocation_id, s.name
-ORDER BY sub.name
-LIMIT $1 OFFSET $2
+ORDER BY sub.sort_order NULLS LAST, sub.name
 `
 
-type ListSublocationsPaginatedParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
-}
-
-type ListSublocationsPaginatedRow struct {
+type ListSublocationsByStateRow struct {
 	SublocationID int32     `json:"sublocation_id"`
 	Name          string    `json:"name"`
 	Description   string    `json:"description"`
 	StateID       int32     `json:"state_id"`
+	ParentID      *int32    `json:"parent_id"`
 	Slug          string    `json:"slug"`
+	SortOrder     *int32    `json:"sort_order"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 	StateName     string    `json:"state_name"`
 	VideoCount    int32     `json:"video_count"`
-	TotalCount    int32     `json:"total_count"`
 }
 
-func (q *Queries) ListSublocationsPaginated(ctx context.Context, arg ListSublocationsPaginatedParams) ([]ListSublocationsPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, listSublocationsPaginated, arg.Limit, arg.Offset)
+// video_count here and below rolls up every live descendant's videos.
+func (q *Queries) ListSublocationsByState(ctx context.Context, stateID int32) ([]ListSublocationsByStateRow, error) {
+	rows, err := q.db.Query(ctx, listSublocationsByState, stateID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListSublocationsPaginatedRow{}
+	items := []ListSublocationsByStateRow{}
 	for rows.Next() {
-		var i ListSublocationsPaginatedRow
+		var i ListSublocationsByStateRow
 		if err := rows.Scan(
 			&i.SublocationID,
 			&i.Name,
 			&i.Description,
 			&i.StateID,
+			&i.ParentID,
 			&i.Slug,
+			&i.SortOrder,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.StateName,
 			&i.VideoCount,
-			&i.TotalCount,
 		); err != nil {
 			return nil, err
 		}
@@ -246,23 +402,108 @@ func (q *Queries) ListSublocationsPaginated(ctx context.Context, arg ListSubloca
 	return items, nil
 }
 
+const reorderSublocations = `-- name: ReorderSublocations :one
+WITH ranked AS (
+  SELECT sub.sublocation_id, sub.sort_order,
+         ROW_NUMBER() OVER (ORDER BY sub.sort_order NULLS LAST, sub.name, sub.sublocation_id)::int AS slot
+  FROM sublocations sub WHERE sub.deleted_at IS NULL
+), listed AS (
+  SELECT r.sublocation_id, r.slot, ROW_NUMBER() OVER (ORDER BY o.position) AS rank
+  FROM unnest($1::int[]) WITH ORDINALITY AS o(sublocation_id, position)
+  JOIN ranked r ON r.sublocation_id = o.sublocation_id
+), slots AS (
+  SELECT slot, ROW_NUMBER() OVER (ORDER BY slot) AS rank FROM listed
+), target AS (
+  SELECT l.sublocation_id, s.slot FROM listed l JOIN slots s ON s.rank = l.rank
+  UNION ALL
+  SELECT r.sublocation_id, r.slot FROM ranked r
+  WHERE r.sort_order IS NOT NULL AND r.sublocation_id NOT IN (SELECT sublocation_id FROM listed)
+), moved AS (
+  UPDATE sublocations sub SET sort_order = t.slot
+  FROM target t
+  WHERE sub.sublocation_id = t.sublocation_id AND sub.sort_order IS DISTINCT FROM t.slot
+  RETURNING sub.sublocation_id
+)
+SELECT COUNT(*) FROM listed
+`
+
+// Puts ids in the given order, in the positions they hold now: sublocations left
+// out keep theirs, and positions stay unique. Unpositioned sublocations (NULL)
+// stay so unless listed. Returns how many of ids are live.
+func (q *Queries) ReorderSublocations(ctx context.Context, ids []int32) (int64, error) {
+	row := q.db.QueryRow(ctx, reorderSublocations, ids)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const setSublocationParent = `-- name: SetSublocationParent :exec
+UPDATE sublocations SET parent_id = $1 WHERE sublocation_id = $2
+`
+
+type SetSublocationParentParams struct {
+	ParentID      *int32 `json:"parent_id"`
+	SublocationID int32  `json:"sublocation_id"`
+}
+
+func (q *Queries) SetSublocationParent(ctx context.Context, arg SetSublocationParentParams) error {
+	_, err := q.db.Exec(ctx, setSublocationParent, arg.ParentID, arg.SublocationID)
+	return err
+}
+
+const softDeleteSublocation = `-- name: SoftDeleteSublocation :execrows
+UPDATE sublocations SET deleted_at = now()
+WHERE sublocation_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteSublocation(ctx context.Context, sublocationID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteSublocation, sublocationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteSublocationsByState = `-- name: SoftDeleteSublocationsByState :exec
+UPDATE sublocations SET deleted_at = $1::timestamptz
+WHERE state_id = $2 AND deleted_at IS NULL
+`
+
+type SoftDeleteSublocationsByStateParams struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	StateID   int32     `json:"state_id"`
+}
+
+func (q *Queries) SoftDeleteSublocationsByState(ctx context.Context, arg SoftDeleteSublocationsByStateParams) error {
+	_, err := q.db.Exec(ctx, softDeleteSublocationsByState, arg.DeletedAt, arg.StateID)
+	return err
+}
+
 const updateSublocation = `-- name: UpdateSublocation :exec
-UPDATE sublocations SET name = $2, description = $3, state_id = $4 WHERE sublocation_id = $1
+UPDATE sublocations
+SET name = $1, description = $2, state_id = $3,
+    parent_id = $4, slug = COALESCE($5, slug)
+WHERE sublocation_id = $6
 `
 
 type UpdateSublocationParams struct {
-	SublocationID int32  `json:"sublocation_id"`
-	Name          string `json:"name"`
-	Description   string `json:"description"`
-	StateID       int32  `json:"state_id"`
+	Name          string  `json:"name"`
+	Description   string  `json:"description"`
+	StateID       int32   `json:"state_id"`
+	ParentID      *int32  `json:"parent_id"`
+	Slug          *string `json:"slug"`
+	SublocationID int32   `json:"sublocation_id"`
 }
 
+// A null slug keeps the current one.
 func (q *Queries) UpdateSublocation(ctx context.Context, arg UpdateSublocationParams) error {
 	_, err := q.db.Exec(ctx, updateSublocation,
-		arg.SublocationID,
 		arg.Name,
 		arg.Description,
 		arg.StateID,
+		arg.ParentID,
+		arg.Slug,
+		arg.SublocationID,
 	)
 	return err
 }
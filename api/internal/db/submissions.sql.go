@@ -0,0 +1,242 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: submissions.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const countRecentSubmissions = `-- name: CountRecentSubmissions :one
+SELECT COUNT(*)::int FROM submissions
+WHERE client_hash = $1 AND created_at >= $2
+`
+
+type CountRecentSubmissionsParams struct {
+	ClientHash string    `json:"client_hash"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Submissions from one client since a time, for the per-client daily cap.
+func (q *Queries) CountRecentSubmissions(ctx context.Context, arg CountRecentSubmissionsParams) (int32, error) {
+	row := q.db.QueryRow(ctx, countRecentSubmissions, arg.ClientHash, arg.CreatedAt)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const countSubmissions = `-- name: CountSubmissions :one
+SELECT COUNT(*)::int FROM submissions
+WHERE $1::text IS NULL OR status = $1::text
+`
+
+func (q *Queries) CountSubmissions(ctx context.Context, status *string) (int32, error) {
+	row := q.db.QueryRow(ctx, countSubmissions, status)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createSubmission = `-- name: CreateSubmission :one
+INSERT INTO submissions (title, src, state_id, contact_name, contact_email, notes, client_hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING submission_id
+`
+
+type CreateSubmissionParams struct {
+	Title        string  `json:"title"`
+	Src          string  `json:"src"`
+	StateID      *int32  `json:"state_id"`
+	ContactName  *string `json:"contact_name"`
+	ContactEmail string  `json:"contact_email"`
+	Notes        *string `json:"notes"`
+	ClientHash   string  `json:"client_hash"`
+}
+
+func (q *Queries) CreateSubmission(ctx context.Context, arg CreateSubmissionParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createSubmission,
+		arg.Title,
+		arg.Src,
+		arg.StateID,
+		arg.ContactName,
+		arg.ContactEmail,
+		arg.Notes,
+		arg.ClientHash,
+	)
+	var submission_id int32
+	err := row.Scan(&submission_id)
+	return submission_id, err
+}
+
+const getSubmission = `-- name: GetSubmission :one
+SELECT sm.submission_id, sm.title, sm.src, sm.state_id, sm.contact_name, sm.contact_email,
+       sm.notes, sm.client_hash, sm.status, sm.reject_reason, sm.reviewed_by, sm.reviewed_at,
+       sm.video_id, sm.created_at, sm.updated_at,
+       s.name AS state_name
+FROM submissions sm
+LEFT JOIN states s ON s.state_id = sm.state_id
+WHERE sm.submission_id = $1
+`
+
+type GetSubmissionRow struct {
+	SubmissionID int32      `json:"submission_id"`
+	Title        string     `json:"title"`
+	Src          string     `json:"src"`
+	StateID      *int32     `json:"state_id"`
+	ContactName  *string    `json:"contact_name"`
+	ContactEmail string     `json:"contact_email"`
+	Notes        *string    `json:"notes"`
+	ClientHash   string     `json:"client_hash"`
+	Status       string     `json:"status"`
+	RejectReason *string    `json:"reject_reason"`
+	ReviewedBy   *string    `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	VideoID      *int32     `json:"video_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	StateName    *string    `json:"state_name"`
+}
+
+func (q *Queries) GetSubmission(ctx context.Context, submissionID int32) (GetSubmissionRow, error) {
+	row := q.db.QueryRow(ctx, getSubmission, submissionID)
+	var i GetSubmissionRow
+	err := row.Scan(
+		&i.SubmissionID,
+		&i.Title,
+		&i.Src,
+		&i.StateID,
+		&i.ContactName,
+		&i.ContactEmail,
+		&i.Notes,
+		&i.ClientHash,
+		&i.Status,
+		&i.RejectReason,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.VideoID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.StateName,
+	)
+	return i, err
+}
+
+const listSubmissions = `-- name: ListSubmissions :many
+SELECT sm.submission_id, sm.title, sm.src, sm.state_id, sm.contact_name, sm.contact_email,
+       sm.notes, sm.client_hash, sm.status, sm.reject_reason, sm.reviewed_by, sm.reviewed_at,
+       sm.video_id, sm.created_at, sm.updated_at,
+       s.name AS state_name
+FROM submissions sm
+LEFT JOIN states s ON s.state_id = sm.state_id
+WHERE ($1::text IS NULL OR sm.status = $1::text)
+  AND ($2::int IS NULL OR
+       CASE WHEN $3::bool THEN sm.submission_id < $2::int
+            ELSE sm.submission_id > $2::int END)
+ORDER BY CASE WHEN $3::bool THEN -sm.submission_id ELSE sm.submission_id END
+LIMIT $5 OFFSET $4
+`
+
+type ListSubmissionsParams struct {
+	Status     *string `json:"status"`
+	CursorID   *int32  `json:"cursor_id"`
+	Backward   bool    `json:"backward"`
+	PageOffset int32   `json:"page_offset"`
+	PageLimit  int32   `json:"page_limit"`
+}
+
+type ListSubmissionsRow struct {
+	SubmissionID int32      `json:"submission_id"`
+	Title        string     `json:"title"`
+	Src          string     `json:"src"`
+	StateID      *int32     `json:"state_id"`
+	ContactName  *string    `json:"contact_name"`
+	ContactEmail string     `json:"contact_email"`
+	Notes        *string    `json:"notes"`
+	ClientHash   string     `json:"client_hash"`
+	Status       string     `json:"status"`
+	RejectReason *string    `json:"reject_reason"`
+	ReviewedBy   *string    `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	VideoID      *int32     `json:"video_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	StateName    *string    `json:"state_name"`
+}
+
+// The moderation queue, oldest first, optionally by status. A cursor pages
+// to newer submissions, or to older ones (in reverse) when backward.
+func (q *Queries) ListSubmissions(ctx context.Context, arg ListSubmissionsParams) ([]ListSubmissionsRow, error) {
+	rows, err := q.db.Query(ctx, listSubmissions,
+		arg.Status,
+		arg.CursorID,
+		arg.Backward,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSubmissionsRow{}
+	for rows.Next() {
+		var i ListSubmissionsRow
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.Title,
+			&i.Src,
+			&i.StateID,
+			&i.ContactName,
+			&i.ContactEmail,
+			&i.Notes,
+			&i.ClientHash,
+			&i.Status,
+			&i.RejectReason,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+			&i.VideoID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StateName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reviewSubmission = `-- name: ReviewSubmission :execrows
+UPDATE submissions
+SET status = $1, reject_reason = $2, video_id = $3,
+    reviewed_by = $4, reviewed_at = now()
+WHERE submission_id = $5 AND status = 'pending'
+`
+
+type ReviewSubmissionParams struct {
+	Status       string  `json:"status"`
+	RejectReason *string `json:"reject_reason"`
+	VideoID      *int32  `json:"video_id"`
+	ReviewedBy   *string `json:"reviewed_by"`
+	SubmissionID int32   `json:"submission_id"`
+}
+
+// Approves or rejects a pending submission. No rows means it isn't pending.
+func (q *Queries) ReviewSubmission(ctx context.Context, arg ReviewSubmissionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reviewSubmission,
+		arg.Status,
+		arg.RejectReason,
+		arg.VideoID,
+		arg.ReviewedBy,
+		arg.SubmissionID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,205 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: tags.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const addVideoTags = `-- name: AddVideoTags :exec
+INSERT INTO video_tags (video_id, tag_id)
+SELECT $1, t.tag_id FROM tags t WHERE t.slug = ANY($2::text[])
+ON CONFLICT DO NOTHING
+`
+
+type AddVideoTagsParams struct {
+	VideoID int32    `json:"video_id"`
+	Slugs   []string `json:"slugs"`
+}
+
+func (q *Queries) AddVideoTags(ctx context.Context, arg AddVideoTagsParams) error {
+	_, err := q.db.Exec(ctx, addVideoTags, arg.VideoID, arg.Slugs)
+	return err
+}
+
+const clearVideoTags = `-- name: ClearVideoTags :exec
+DELETE FROM video_tags WHERE video_id = $1
+`
+
+func (q *Queries) ClearVideoTags(ctx context.Context, videoID int32) error {
+	_, err := q.db.Exec(ctx, clearVideoTags, videoID)
+	return err
+}
+
+const countTagsBySlug = `-- name: CountTagsBySlug :one
+SELECT COUNT(*)::int FROM tags WHERE slug = ANY($1::text[])
+`
+
+func (q *Queries) CountTagsBySlug(ctx context.Context, slugs []string) (int32, error) {
+	row := q.db.QueryRow(ctx, countTagsBySlug, slugs)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const createTag = `-- name: CreateTag :one
+INSERT INTO tags (name, slug)
+VALUES ($1, $2)
+RETURNING tag_id, name, slug, created_at, updated_at
+`
+
+type CreateTagParams struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, createTag, arg.Name, arg.Slug)
+	var i Tag
+	err := row.Scan(
+		&i.TagID,
+		&i.Name,
+		&i.Slug,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteTag = `-- name: DeleteTag :exec
+DELETE FROM tags WHERE tag_id = $1
+`
+
+func (q *Queries) DeleteTag(ctx context.Context, tagID int32) error {
+	_, err := q.db.Exec(ctx, deleteTag, tagID)
+	return err
+}
+
+const getTagByID = `-- name: GetTagByID :one
+SELECT t.tag_id, t.name, t.slug, t.created_at, t.updated_at,
+       COUNT(v.video_id)::int AS video_count
+FROM tags t
+LEFT JOIN video_tags vt ON vt.tag_id = t.tag_id
+LEFT JOIN videos v ON v.video_id = vt.video_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE t.tag_id = $1
+GROUP BY t.tag_id
+`
+
+type GetTagByIDRow struct {
+	TagID      int32     `json:"tag_id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	VideoCount int32     `json:"video_count"`
+}
+
+func (q *Queries) GetTagByID(ctx context.Context, tagID int32) (GetTagByIDRow, error) {
+	row := q.db.QueryRow(ctx, getTagByID, tagID)
+	var i GetTagByIDRow
+	err := row.Scan(
+		&i.TagID,
+		&i.Name,
+		&i.Slug,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.VideoCount,
+	)
+	return i, err
+}
+
+const listTagSlugs = `-- name: ListTagSlugs :many
+SELECT slug FROM tags
+WHERE (slug = $1::text OR slug LIKE $1::text || '-%')
+  AND tag_id <> $2
+`
+
+type ListTagSlugsParams struct {
+	Base     string `json:"base"`
+	ExceptID int32  `json:"except_id"`
+}
+
+// ListStateSlugs for tags.
+func (q *Queries) ListTagSlugs(ctx context.Context, arg ListTagSlugsParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listTagSlugs, arg.Base, arg.ExceptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTags = `-- name: ListTags :many
+SELECT t.tag_id, t.name, t.slug, t.created_at, t.updated_at,
+       COUNT(v.video_id)::int AS video_count
+FROM tags t
+LEFT JOIN video_tags vt ON vt.tag_id = t.tag_id
+LEFT JOIN videos v ON v.video_id = vt.video_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+GROUP BY t.tag_id
+ORDER BY t.name
+`
+
+type ListTagsRow struct {
+	TagID      int32     `json:"tag_id"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	VideoCount int32     `json:"video_count"`
+}
+
+func (q *Queries) ListTags(ctx context.Context) ([]ListTagsRow, error) {
+	rows, err := q.db.Query(ctx, listTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTagsRow{}
+	for rows.Next() {
+		var i ListTagsRow
+		if err := rows.Scan(
+			&i.TagID,
+			&i.Name,
+			&i.Slug,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.VideoCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTag = `-- name: UpdateTag :exec
+UPDATE tags SET name = $2 WHERE tag_id = $1
+`
+
+type UpdateTagParams struct {
+	TagID int32  `json:"tag_id"`
+	Name  string `json:"name"`
+}
+
+func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) error {
+	_, err := q.db.Exec(ctx, updateTag, arg.TagID, arg.Name)
+	return err
+}
@@ -0,0 +1,222 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: trash.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const getStateDeletedAt = `-- name: GetStateDeletedAt :one
+SELECT deleted_at FROM states WHERE state_id = $1
+`
+
+func (q *Queries) GetStateDeletedAt(ctx context.Context, stateID int32) (*time.Time, error) {
+	row := q.db.QueryRow(ctx, getStateDeletedAt, stateID)
+	var deleted_at *time.Time
+	err := row.Scan(&deleted_at)
+	return deleted_at, err
+}
+
+const getSublocationDeletedAt = `-- name: GetSublocationDeletedAt :one
+SELECT sub.deleted_at, s.deleted_at AS state_deleted_at,
+       p.deleted_at AS parent_deleted_at
+FROM sublocations sub
+JOIN states s ON s.state_id = sub.state_id
+LEFT JOIN sublocations p ON p.sublocation_id = sub.parent_id
+WHERE sub.sublocation_id = $1
+`
+
+type GetSublocationDeletedAtRow struct {
+	DeletedAt       *time.Time `json:"deleted_at"`
+	StateDeletedAt  *time.Time `json:"state_deleted_at"`
+	ParentDeletedAt *time.Time `json:"parent_deleted_at"`
+}
+
+func (q *Queries) GetSublocationDeletedAt(ctx context.Context, sublocationID int32) (GetSublocationDeletedAtRow, error) {
+	row := q.db.QueryRow(ctx, getSublocationDeletedAt, sublocationID)
+	var i GetSublocationDeletedAtRow
+	err := row.Scan(&i.DeletedAt, &i.StateDeletedAt, &i.ParentDeletedAt)
+	return i, err
+}
+
+const getVideoDeletedAt = `-- name: GetVideoDeletedAt :one
+SELECT v.deleted_at, s.deleted_at AS state_deleted_at,
+       sub.deleted_at AS sublocation_deleted_at
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
+WHERE v.video_id = $1
+`
+
+type GetVideoDeletedAtRow struct {
+	DeletedAt            *time.Time `json:"deleted_at"`
+	StateDeletedAt       *time.Time `json:"state_deleted_at"`
+	SublocationDeletedAt *time.Time `json:"sublocation_deleted_at"`
+}
+
+func (q *Queries) GetVideoDeletedAt(ctx context.Context, videoID int32) (GetVideoDeletedAtRow, error) {
+	row := q.db.QueryRow(ctx, getVideoDeletedAt, videoID)
+	var i GetVideoDeletedAtRow
+	err := row.Scan(&i.DeletedAt, &i.StateDeletedAt, &i.SublocationDeletedAt)
+	return i, err
+}
+
+const listTrash = `-- name: ListTrash :many
+SELECT kind, id, name, state_name, deleted_at FROM (
+  SELECT 'state'::text AS kind, s.state_id AS id, s.name, ''::text AS state_name,
+         s.deleted_at::timestamptz AS deleted_at
+  FROM states s
+  WHERE s.deleted_at IS NOT NULL
+
+  UNION ALL
+
+  SELECT 'sublocation'::text, sub.sublocation_id, sub.name, st.name,
+         sub.deleted_at::timestamptz
+  FROM sublocations sub
+  JOIN states st ON st.state_id = sub.state_id
+  WHERE sub.deleted_at IS NOT NULL
+
+  UNION ALL
+
+  SELECT 'video'::text, v.video_id, v.title, st.name,
+         v.deleted_at::timestamptz
+  FROM videos v
+  JOIN states st ON st.state_id = v.state_id
+  WHERE v.deleted_at IS NOT NULL
+) trash
+ORDER BY deleted_at DESC, kind, id
+`
+
+type ListTrashRow struct {
+	Kind      string    `json:"kind"`
+	ID        int32     `json:"id"`
+	Name      string    `json:"name"`
+	StateName string    `json:"state_name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Everything currently soft-deleted, most recent first.
+func (q *Queries) ListTrash(ctx context.Context) ([]ListTrashRow, error) {
+	rows, err := q.db.Query(ctx, listTrash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTrashRow{}
+	for rows.Next() {
+		var i ListTrashRow
+		if err := rows.Scan(
+			&i.Kind,
+			&i.ID,
+			&i.Name,
+			&i.StateName,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeStates = `-- name: PurgeStates :execrows
+DELETE FROM states WHERE deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeStates(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeStates, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeSublocations = `-- name: PurgeSublocations :execrows
+DELETE FROM sublocations WHERE deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeSublocations(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeSublocations, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeVideos = `-- name: PurgeVideos :execrows
+DELETE FROM videos WHERE deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeVideos(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeVideos, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreState = `-- name: RestoreState :exec
+UPDATE states SET deleted_at = NULL WHERE state_id = $1
+`
+
+func (q *Queries) RestoreState(ctx context.Context, stateID int32) error {
+	_, err := q.db.Exec(ctx, restoreState, stateID)
+	return err
+}
+
+const restoreSublocation = `-- name: RestoreSublocation :exec
+UPDATE sublocations SET deleted_at = NULL WHERE sublocation_id = $1
+`
+
+func (q *Queries) RestoreSublocation(ctx context.Context, sublocationID int32) error {
+	_, err := q.db.Exec(ctx, restoreSublocation, sublocationID)
+	return err
+}
+
+const restoreSublocationsByState = `-- name: RestoreSublocationsByState :exec
+UPDATE sublocations SET deleted_at = NULL
+WHERE state_id = $1 AND deleted_at = $2::timestamptz
+`
+
+type RestoreSublocationsByStateParams struct {
+	StateID   int32     `json:"state_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Restores only the sublocations trashed together with the state.
+func (q *Queries) RestoreSublocationsByState(ctx context.Context, arg RestoreSublocationsByStateParams) error {
+	_, err := q.db.Exec(ctx, restoreSublocationsByState, arg.StateID, arg.DeletedAt)
+	return err
+}
+
+const restoreVideo = `-- name: RestoreVideo :exec
+UPDATE videos SET deleted_at = NULL WHERE video_id = $1
+`
+
+func (q *Queries) RestoreVideo(ctx context.Context, videoID int32) error {
+	_, err := q.db.Exec(ctx, restoreVideo, videoID)
+	return err
+}
+
+const restoreVideosByState = `-- name: RestoreVideosByState :exec
+UPDATE videos SET deleted_at = NULL
+WHERE state_id = $1 AND deleted_at = $2::timestamptz
+`
+
+type RestoreVideosByStateParams struct {
+	StateID   int32     `json:"state_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Restores only the videos trashed together with the state.
+func (q *Queries) RestoreVideosByState(ctx context.Context, arg RestoreVideosByStateParams) error {
+	_, err := q.db.Exec(ctx, restoreVideosByState, arg.StateID, arg.DeletedAt)
+	return err
+}
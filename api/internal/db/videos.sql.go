@@ -8,25 +8,96 @@ package db
 import (
 	"context"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createVideo = `-- name: CreateVideo :one
-INSERT INTO videos (title, src, type, state_id, sublocation_id, status, created_by)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING video_id, title, src, type, state_id, sublocation_id, status, created_by, created_at, updated_at
+const checkVideoPlace = `-- name: CheckVideoPlace :one
+SELECT EXISTS (SELECT 1 FROM states s
+               WHERE s.state_id = $1::int AND s.deleted_at IS NULL) AS state_ok,
+       ($2::int IS NULL OR
+        EXISTS (SELECT 1 FROM sublocations sub
+                WHERE sub.sublocation_id = $2::int
+                  AND sub.state_id = $1::int AND sub.deleted_at IS NULL))::bool AS sublocation_ok
 `
 
-type CreateVideoParams struct {
-	Title         string `json:"title"`
-	Src           string `json:"src"`
-	Type          string `json:"type"`
+type CheckVideoPlaceParams struct {
 	StateID       int32  `json:"state_id"`
 	SublocationID *int32 `json:"sublocation_id"`
-	Status        string `json:"status"`
-	CreatedBy     string `json:"created_by"`
 }
 
-func (q *Queries) CreateVideo(ctx context.Context, arg CreateVideoParams) (Video, error) {
+type CheckVideoPlaceRow struct {
+	StateOk       bool `json:"state_ok"`
+	SublocationOk bool `json:"sublocation_ok"`
+}
+
+// Whether a state, and a sublocation within it when given, exist and aren't
+// trashed: where a new video may go.
+func (q *Queries) CheckVideoPlace(ctx context.Context, arg CheckVideoPlaceParams) (CheckVideoPlaceRow, error) {
+	row := q.db.QueryRow(ctx, checkVideoPlace, arg.StateID, arg.SublocationID)
+	var i CheckVideoPlaceRow
+	err := row.Scan(&i.StateOk, &i.SublocationOk)
+	return i, err
+}
+
+const createVideo = `-- name: CreateVideo :one
+INSERT INTO videos (title, src, type, state_id, sublocation_id, status, created_by,
+                    latitude, longitude, heading, elevation, stream_id, publish_at, unpublish_at, featured,
+                    timezone, field_of_view, camera_model, firmware, owner_contact, install_date, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+        $16, $17, $18, $19, $20, $21, $22)
+RETURNING video_id, title, src, type, state_id, sublocation_id, status, created_by, created_at, updated_at,
+          latitude, longitude, heading, elevation, stream_id, publish_at, unpublish_at, featured, sort_order
+`
+
+type CreateVideoParams struct {
+	Title         string      `json:"title"`
+	Src           string      `json:"src"`
+	Type          string      `json:"type"`
+	StateID       int32       `json:"state_id"`
+	SublocationID *int32      `json:"sublocation_id"`
+	Status        string      `json:"status"`
+	CreatedBy     string      `json:"created_by"`
+	Latitude      *float64    `json:"latitude"`
+	Longitude     *float64    `json:"longitude"`
+	Heading       *float64    `json:"heading"`
+	Elevation     *float64    `json:"elevation"`
+	StreamID      *string     `json:"stream_id"`
+	PublishAt     *time.Time  `json:"publish_at"`
+	UnpublishAt   *time.Time  `json:"unpublish_at"`
+	Featured      bool        `json:"featured"`
+	Timezone      *string     `json:"timezone"`
+	FieldOfView   *float64    `json:"field_of_view"`
+	CameraModel   *string     `json:"camera_model"`
+	Firmware      *string     `json:"firmware"`
+	OwnerContact  *string     `json:"owner_contact"`
+	InstallDate   pgtype.Date `json:"install_date"`
+	Notes         *string     `json:"notes"`
+}
+
+type CreateVideoRow struct {
+	VideoID       int32      `json:"video_id"`
+	Title         string     `json:"title"`
+	Src           string     `json:"src"`
+	Type          string     `json:"type"`
+	StateID       int32      `json:"state_id"`
+	SublocationID *int32     `json:"sublocation_id"`
+	Status        string     `json:"status"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Latitude      *float64   `json:"latitude"`
+	Longitude     *float64   `json:"longitude"`
+	Heading       *float64   `json:"heading"`
+	Elevation     *float64   `json:"elevation"`
+	StreamID      *string    `json:"stream_id"`
+	PublishAt     *time.Time `json:"publish_at"`
+	UnpublishAt   *time.Time `json:"unpublish_at"`
+	Featured      bool       `json:"featured"`
+	SortOrder     *int32     `json:"sort_order"`
+}
+
+func (q *Queries) CreateVideo(ctx context.Context, arg CreateVideoParams) (CreateVideoRow, error) {
 	row := q.db.QueryRow(ctx, createVideo,
 		arg.Title,
 		arg.Src,
@@ -35,8 +106,23 @@ func (q *Queries) CreateVideo(ctx context.Context, arg CreateVideoParams) (Video
 		arg.SublocationID,
 		arg.Status,
 		arg.CreatedBy,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Heading,
+		arg.Elevation,
+		arg.StreamID,
+		arg.PublishAt,
+		arg.UnpublishAt,
+		arg.Featured,
+		arg.Timezone,
+		arg.FieldOfView,
+		arg.CameraModel,
+		arg.Firmware,
+		arg.OwnerContact,
+		arg.InstallDate,
+		arg.Notes,
 	)
-	var i Video
+	var i CreateVideoRow
 	err := row.Scan(
 		&i.VideoID,
 		&i.Title,
@@ -48,43 +134,112 @@ func (q *Queries) CreateVideo(ctx context.Context, arg CreateVideoParams) (Video
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Heading,
+		&i.Elevation,
+		&i.StreamID,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Featured,
+		&i.SortOrder,
 	)
 	return i, err
 }
 
-const deleteVideo = `-- name: DeleteVideo :exec
-DELETE FROM videos WHERE video_id = $1
+const deactivateVideosByStream = `-- name: DeactivateVideosByStream :many
+UPDATE videos v SET status = 'archived'
+FROM videos old
+WHERE old.video_id = v.video_id AND v.stream_id = $1 AND v.deleted_at IS NULL
+RETURNING v.video_id, old.status AS previous_status
 `
 
-func (q *Queries) DeleteVideo(ctx context.Context, videoID int32) error {
-	_, err := q.db.Exec(ctx, deleteVideo, videoID)
-	return err
+type DeactivateVideosByStreamRow struct {
+	VideoID        int32  `json:"video_id"`
+	PreviousStatus string `json:"previous_status"`
+}
+
+func (q *Queries) DeactivateVideosByStream(ctx context.Context, streamID *string) ([]DeactivateVideosByStreamRow, error) {
+	rows, err := q.db.Query(ctx, deactivateVideosByStream, streamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []DeactivateVideosByStreamRow{}
+	for rows.Next() {
+		var i DeactivateVideosByStreamRow
+		if err := rows.Scan(&i.VideoID, &i.PreviousStatus); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getListedVideoTimezone = `-- name: GetListedVideoTimezone :one
+SELECT timezone FROM videos
+WHERE video_id = $1 AND status IN ('published', 'maintenance', 'offline') AND deleted_at IS NULL
+`
+
+func (q *Queries) GetListedVideoTimezone(ctx context.Context, videoID int32) (*string, error) {
+	row := q.db.QueryRow(ctx, getListedVideoTimezone, videoID)
+	var timezone *string
+	err := row.Scan(&timezone)
+	return timezone, err
 }
 
 const getVideoByID = `-- name: GetVideoByID :one
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone, v.stream_id,
+       v.camera_model, v.firmware, v.owner_contact, v.install_date, v.notes,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
-       COALESCE(sub.name, '') AS sublocation_name
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
 WHERE v.video_id = $1
 `
 
 type GetVideoByIDRow struct {
-	VideoID         int32     `json:"video_id"`
-	Title           string    `json:"title"`
-	Src             string    `json:"src"`
-	Type            string    `json:"type"`
-	StateID         int32     `json:"state_id"`
-	SublocationID   *int32    `json:"sublocation_id"`
-	Status          string    `json:"status"`
-	CreatedBy       string    `json:"created_by"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-	StateName       string    `json:"state_name"`
-	SublocationName string    `json:"sublocation_name"`
+	VideoID         int32       `json:"video_id"`
+	Title           string      `json:"title"`
+	Src             string      `json:"src"`
+	Type            string      `json:"type"`
+	StateID         int32       `json:"state_id"`
+	SublocationID   *int32      `json:"sublocation_id"`
+	Latitude        *float64    `json:"latitude"`
+	Longitude       *float64    `json:"longitude"`
+	Heading         *float64    `json:"heading"`
+	FieldOfView     *float64    `json:"field_of_view"`
+	Elevation       *float64    `json:"elevation"`
+	Timezone        *string     `json:"timezone"`
+	StreamID        *string     `json:"stream_id"`
+	CameraModel     *string     `json:"camera_model"`
+	Firmware        *string     `json:"firmware"`
+	OwnerContact    *string     `json:"owner_contact"`
+	InstallDate     pgtype.Date `json:"install_date"`
+	Notes           *string     `json:"notes"`
+	Status          string      `json:"status"`
+	Badge           string      `json:"badge"`
+	PublishAt       *time.Time  `json:"publish_at"`
+	UnpublishAt     *time.Time  `json:"unpublish_at"`
+	Featured        bool        `json:"featured"`
+	SortOrder       *int32      `json:"sort_order"`
+	CreatedBy       string      `json:"created_by"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+	StateName       string      `json:"state_name"`
+	SublocationName string      `json:"sublocation_name"`
+	Tags            []string    `json:"tags"`
 }
 
 func (q *Queries) GetVideoByID(ctx context.Context, videoID int32) (GetVideoByIDRow, error) {
@@ -97,41 +252,168 @@ func (q *Queries) GetVideoByID(ctx context.Context, videoID int32) (GetVideoByID
 		&i.Type,
 		&i.StateID,
 		&i.SublocationID,
+		&i.Latitude,
+		&i.Longitude,
+		&i.Heading,
+		&i.FieldOfView,
+		&i.Elevation,
+		&i.Timezone,
+		&i.StreamID,
+		&i.CameraModel,
+		&i.Firmware,
+		&i.OwnerContact,
+		&i.InstallDate,
+		&i.Notes,
 		&i.Status,
+		&i.Badge,
+		&i.PublishAt,
+		&i.UnpublishAt,
+		&i.Featured,
+		&i.SortOrder,
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.StateName,
 		&i.SublocationName,
+		&i.Tags,
 	)
 	return i, err
 }
 
+const listFeaturedVideos = `-- name: ListFeaturedVideos :many
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.featured AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY v.sort_order NULLS LAST, v.title
+`
+
+type ListFeaturedVideosRow struct {
+	VideoID         int32      `json:"video_id"`
+	Title           string     `json:"title"`
+	Src             string     `json:"src"`
+	Type            string     `json:"type"`
+	StateID         int32      `json:"state_id"`
+	SublocationID   *int32     `json:"sublocation_id"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	Heading         *float64   `json:"heading"`
+	FieldOfView     *float64   `json:"field_of_view"`
+	Elevation       *float64   `json:"elevation"`
+	Timezone        *string    `json:"timezone"`
+	Status          string     `json:"status"`
+	Badge           string     `json:"badge"`
+	PublishAt       *time.Time `json:"publish_at"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
+	Featured        bool       `json:"featured"`
+	SortOrder       *int32     `json:"sort_order"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	StateName       string     `json:"state_name"`
+	SublocationName string     `json:"sublocation_name"`
+	Tags            []string   `json:"tags"`
+}
+
+// Listed videos flagged for the homepage hero, in admin order.
+func (q *Queries) ListFeaturedVideos(ctx context.Context) ([]ListFeaturedVideosRow, error) {
+	rows, err := q.db.Query(ctx, listFeaturedVideos)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListFeaturedVideosRow{}
+	for rows.Next() {
+		var i ListFeaturedVideosRow
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.Src,
+			&i.Type,
+			&i.StateID,
+			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
+			&i.Status,
+			&i.Badge,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Featured,
+			&i.SortOrder,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StateName,
+			&i.SublocationName,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVideos = `-- name: ListVideos :many
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
-       COALESCE(sub.name, '') AS sublocation_name
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
-WHERE v.status = 'active'
-ORDER BY v.title
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY v.sort_order NULLS LAST, v.title
 `
 
 type ListVideosRow struct {
-	VideoID         int32     `json:"video_id"`
-	Title           string    `json:"title"`
-	Src             string    `json:"src"`
-	Type            string    `json:"type"`
-	StateID         int32     `json:"state_id"`
-	SublocationID   *int32    `json:"sublocation_id"`
-	Status          string    `json:"status"`
-	CreatedBy       string    `json:"created_by"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-	StateName       string    `json:"state_name"`
-	SublocationName string    `json:"sublocation_name"`
+	VideoID         int32      `json:"video_id"`
+	Title           string     `json:"title"`
+	Src             string     `json:"src"`
+	Type            string     `json:"type"`
+	StateID         int32      `json:"state_id"`
+	SublocationID   *int32     `json:"sublocation_id"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	Heading         *float64   `json:"heading"`
+	FieldOfView     *float64   `json:"field_of_view"`
+	Elevation       *float64   `json:"elevation"`
+	Timezone        *string    `json:"timezone"`
+	Status          string     `json:"status"`
+	Badge           string     `json:"badge"`
+	PublishAt       *time.Time `json:"publish_at"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
+	Featured        bool       `json:"featured"`
+	SortOrder       *int32     `json:"sort_order"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	StateName       string     `json:"state_name"`
+	SublocationName string     `json:"sublocation_name"`
+	Tags            []string   `json:"tags"`
 }
 
 func (q *Queries) ListVideos(ctx context.Context) ([]ListVideosRow, error) {
@@ -150,12 +432,24 @@ func (q *Queries) ListVideos(ctx context.Context) ([]ListVideosRow, error) {
 			&i.Type,
 			&i.StateID,
 			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
 			&i.Status,
+			&i.Badge,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Featured,
+			&i.SortOrder,
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.StateName,
 			&i.SublocationName,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -169,29 +463,47 @@ func (q *Queries) ListVideos(ctx context.Context) ([]ListVideosRow, error) {
 
 const listVideosByState = `-- name: ListVideosByState :many
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
-       COALESCE(sub.name, '') AS sublocation_name
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
-WHERE v.state_id = $1 AND v.status = 'active'
-ORDER BY v.title
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.state_id = $1 AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY v.sort_order NULLS LAST, v.title
 `
 
 type ListVideosByStateRow struct {
-	VideoID         int32     `json:"video_id"`
-	Title           string    `json:"title"`
-	Src             string    `json:"src"`
-	Type            string    `json:"type"`
-	StateID         int32     `json:"state_id"`
-	SublocationID   *int32    `json:"sublocation_id"`
-	Status          string    `json:"status"`
-	CreatedBy       string    `json:"created_by"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-	StateName       string    `json:"state_name"`
-	SublocationName string    `json:"sublocation_name"`
+	VideoID         int32      `json:"video_id"`
+	Title           string     `json:"title"`
+	Src             string     `json:"src"`
+	Type            string     `json:"type"`
+	StateID         int32      `json:"state_id"`
+	SublocationID   *int32     `json:"sublocation_id"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	Heading         *float64   `json:"heading"`
+	FieldOfView     *float64   `json:"field_of_view"`
+	Elevation       *float64   `json:"elevation"`
+	Timezone        *string    `json:"timezone"`
+	Status          string     `json:"status"`
+	Badge           string     `json:"badge"`
+	PublishAt       *time.Time `json:"publish_at"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
+	Featured        bool       `json:"featured"`
+	SortOrder       *int32     `json:"sort_order"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	StateName       string     `json:"state_name"`
+	SublocationName string     `json:"sublocation_name"`
+	Tags            []string   `json:"tags"`
 }
 
 func (q *Queries) ListVideosByState(ctx context.Context, stateID int32) ([]ListVideosByStateRow, error) {
@@ -210,12 +522,24 @@ func (q *Queries) ListVideosByState(ctx context.Context, stateID int32) ([]ListV
 			&i.Type,
 			&i.StateID,
 			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
 			&i.Status,
+			&i.Badge,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Featured,
+			&i.SortOrder,
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.StateName,
 			&i.SublocationName,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -229,29 +553,47 @@ func (q *Queries) ListVideosByState(ctx context.Context, stateID int32) ([]ListV
 
 const listVideosBySublocation = `-- name: ListVideosBySublocation :many
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
-       COALESCE(sub.name, '') AS sublocation_name
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
-WHERE v.sublocation_id = $1 AND v.status = 'active'
-ORDER BY v.title
+JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.sublocation_id = $1 AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY v.sort_order NULLS LAST, v.title
 `
 
 type ListVideosBySublocationRow struct {
-	VideoID         int32     `json:"video_id"`
-	Title           string    `json:"title"`
-	Src             string    `json:"src"`
-	Type            string    `json:"type"`
-	StateID         int32     `json:"state_id"`
-	SublocationID   *int32    `json:"sublocation_id"`
-	Status          string    `json:"status"`
-	CreatedBy       string    `json:"created_by"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-	StateName       string    `json:"state_name"`
-	SublocationName string    `json:"sublocation_name"`
+	VideoID         int32      `json:"video_id"`
+	Title           string     `json:"title"`
+	Src             string     `json:"src"`
+	Type            string     `json:"type"`
+	StateID         int32      `json:"state_id"`
+	SublocationID   *int32     `json:"sublocation_id"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	Heading         *float64   `json:"heading"`
+	FieldOfView     *float64   `json:"field_of_view"`
+	Elevation       *float64   `json:"elevation"`
+	Timezone        *string    `json:"timezone"`
+	Status          string     `json:"status"`
+	Badge           string     `json:"badge"`
+	PublishAt       *time.Time `json:"publish_at"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
+	Featured        bool       `json:"featured"`
+	SortOrder       *int32     `json:"sort_order"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	StateName       string     `json:"state_name"`
+	SublocationName string     `json:"sublocation_name"`
+	Tags            []string   `json:"tags"`
 }
 
 func (q *Queries) ListVideosBySublocation(ctx context.Context, sublocationID *int32) ([]ListVideosBySublocationRow, error) {
@@ -270,12 +612,288 @@ func (q *Queries) ListVideosBySublocation(ctx context.Context, sublocationID *in
 			&i.Type,
 			&i.StateID,
 			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
+			&i.Status,
+			&i.Badge,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Featured,
+			&i.SortOrder,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StateName,
+			&i.SublocationName,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideosByTags = `-- name: ListVideosByTags :many
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+  AND ($1::int IS NULL OR v.state_id = $1::int)
+  AND ($2::int IS NULL OR v.sublocation_id = $2::int)
+  AND v.video_id IN (
+    SELECT vt.video_id FROM video_tags vt
+    JOIN tags t ON t.tag_id = vt.tag_id
+    WHERE t.slug = ANY($3::text[])
+    GROUP BY vt.video_id
+    HAVING COUNT(*) >= $4::int
+  )
+ORDER BY v.sort_order NULLS LAST, v.title
+`
+
+type ListVideosByTagsParams struct {
+	StateID       *int32   `json:"state_id"`
+	SublocationID *int32   `json:"sublocation_id"`
+	Tags          []string `json:"tags"`
+	MinMatches    int32    `json:"min_matches"`
+}
+
+type ListVideosByTagsRow struct {
+	VideoID         int32      `json:"video_id"`
+	Title           string     `json:"title"`
+	Src             string     `json:"src"`
+	Type            string     `json:"type"`
+	StateID         int32      `json:"state_id"`
+	SublocationID   *int32     `json:"sublocation_id"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	Heading         *float64   `json:"heading"`
+	FieldOfView     *float64   `json:"field_of_view"`
+	Elevation       *float64   `json:"elevation"`
+	Timezone        *string    `json:"timezone"`
+	Status          string     `json:"status"`
+	Badge           string     `json:"badge"`
+	PublishAt       *time.Time `json:"publish_at"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
+	Featured        bool       `json:"featured"`
+	SortOrder       *int32     `json:"sort_order"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	StateName       string     `json:"state_name"`
+	SublocationName string     `json:"sublocation_name"`
+	Tags            []string   `json:"tags"`
+}
+
+// Listed videos carrying at least min_matches of the given tag slugs:
+// len(tags) for AND semantics, 1 for OR. state_id/sublocation_id narrow further.
+func (q *Queries) ListVideosByTags(ctx context.Context, arg ListVideosByTagsParams) ([]ListVideosByTagsRow, error) {
+	rows, err := q.db.Query(ctx, listVideosByTags,
+		arg.StateID,
+		arg.SublocationID,
+		arg.Tags,
+		arg.MinMatches,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVideosByTagsRow{}
+	for rows.Next() {
+		var i ListVideosByTagsRow
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.Src,
+			&i.Type,
+			&i.StateID,
+			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
+			&i.Status,
+			&i.Badge,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Featured,
+			&i.SortOrder,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StateName,
+			&i.SublocationName,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideosForHealthCheck = `-- name: ListVideosForHealthCheck :many
+SELECT video_id, src, status
+FROM videos
+WHERE status IN ('published', 'offline') AND deleted_at IS NULL
+ORDER BY video_id
+`
+
+type ListVideosForHealthCheckRow struct {
+	VideoID int32  `json:"video_id"`
+	Src     string `json:"src"`
+	Status  string `json:"status"`
+}
+
+// Videos whose stream the health check probes: published ones that may have
+// gone offline, and offline ones that may have come back.
+func (q *Queries) ListVideosForHealthCheck(ctx context.Context) ([]ListVideosForHealthCheckRow, error) {
+	rows, err := q.db.Query(ctx, listVideosForHealthCheck)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVideosForHealthCheckRow{}
+	for rows.Next() {
+		var i ListVideosForHealthCheckRow
+		if err := rows.Scan(&i.VideoID, &i.Src, &i.Status); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideosInBBox = `-- name: ListVideosInBBox :many
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags,
+       (6371 * 2 * asin(LEAST(1, sqrt(
+         power(sin(radians(v.latitude - $1::float8) / 2), 2) +
+         cos(radians($1::float8)) * cos(radians(v.latitude)) *
+         power(sin(radians(v.longitude - $2::float8) / 2), 2)
+       ))))::float8 AS distance_km
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+  AND v.latitude BETWEEN $3::float8 AND $4::float8
+  AND v.longitude BETWEEN $5::float8 AND $6::float8
+ORDER BY distance_km, v.title
+`
+
+type ListVideosInBBoxParams struct {
+	CenterLat float64 `json:"center_lat"`
+	CenterLon float64 `json:"center_lon"`
+	MinLat    float64 `json:"min_lat"`
+	MaxLat    float64 `json:"max_lat"`
+	MinLon    float64 `json:"min_lon"`
+	MaxLon    float64 `json:"max_lon"`
+}
+
+type ListVideosInBBoxRow struct {
+	VideoID         int32      `json:"video_id"`
+	Title           string     `json:"title"`
+	Src             string     `json:"src"`
+	Type            string     `json:"type"`
+	StateID         int32      `json:"state_id"`
+	SublocationID   *int32     `json:"sublocation_id"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	Heading         *float64   `json:"heading"`
+	FieldOfView     *float64   `json:"field_of_view"`
+	Elevation       *float64   `json:"elevation"`
+	Timezone        *string    `json:"timezone"`
+	Status          string     `json:"status"`
+	Badge           string     `json:"badge"`
+	PublishAt       *time.Time `json:"publish_at"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
+	Featured        bool       `json:"featured"`
+	SortOrder       *int32     `json:"sort_order"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	StateName       string     `json:"state_name"`
+	SublocationName string     `json:"sublocation_name"`
+	Tags            []string   `json:"tags"`
+	DistanceKm      float64    `json:"distance_km"`
+}
+
+// Videos inside a bounding box, ordered by distance from the box centre.
+func (q *Queries) ListVideosInBBox(ctx context.Context, arg ListVideosInBBoxParams) ([]ListVideosInBBoxRow, error) {
+	rows, err := q.db.Query(ctx, listVideosInBBox,
+		arg.CenterLat,
+		arg.CenterLon,
+		arg.MinLat,
+		arg.MaxLat,
+		arg.MinLon,
+		arg.MaxLon,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVideosInBBoxRow{}
+	for rows.Next() {
+		var i ListVideosInBBoxRow
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.Src,
+			&i.Type,
+			&i.StateID,
+			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
 			&i.Status,
+			&i.Badge,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Featured,
+			&i.SortOrder,
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.StateName,
 			&i.SublocationName,
+			&i.Tags,
+			&i.DistanceKm,
 		); err != nil {
 			return nil, err
 		}
@@ -287,50 +905,177 @@ func (q *Queries) ListVideosBySublocation(ctx context.Context, sublocationID *in
 	return items, nil
 }
 
-const listVideosPaginated = `-- name: ListVideosPaginated :many
+const listVideosInSublocationTree = `-- name: ListVideosInSublocationTree :many
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
        COALESCE(sub.name, '') AS sublocation_name,
-       COUNT(*) OVER()::int AS total_count
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
-WHERE v.status = 'active'
-ORDER BY v.title
-LIMIT $1 OFFSET $2
+JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.sublocation_id IN (SELECT sublocation_subtree($1::int)) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY sub.sort_order NULLS LAST, sub.name, v.sort_order NULLS LAST, v.title
+`
+
+type ListVideosInSublocationTreeRow struct {
+	VideoID         int32      `json:"video_id"`
+	Title           string     `json:"title"`
+	Src             string     `json:"src"`
+	Type            string     `json:"type"`
+	StateID         int32      `json:"state_id"`
+	SublocationID   *int32     `json:"sublocation_id"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	Heading         *float64   `json:"heading"`
+	FieldOfView     *float64   `json:"field_of_view"`
+	Elevation       *float64   `json:"elevation"`
+	Timezone        *string    `json:"timezone"`
+	Status          string     `json:"status"`
+	Badge           string     `json:"badge"`
+	PublishAt       *time.Time `json:"publish_at"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
+	Featured        bool       `json:"featured"`
+	SortOrder       *int32     `json:"sort_order"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	StateName       string     `json:"state_name"`
+	SublocationName string     `json:"sublocation_name"`
+	Tags            []string   `json:"tags"`
+}
+
+// Videos in a sublocation or any live sublocation below it.
+func (q *Queries) ListVideosInSublocationTree(ctx context.Context, sublocationID int32) ([]ListVideosInSublocationTreeRow, error) {
+	rows, err := q.db.Query(ctx, listVideosInSublocationTree, sublocationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListVideosInSublocationTreeRow{}
+	for rows.Next() {
+		var i ListVideosInSublocationTreeRow
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Title,
+			&i.Src,
+			&i.Type,
+			&i.StateID,
+			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
+			&i.Status,
+			&i.Badge,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Featured,
+			&i.SortOrder,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StateName,
+			&i.SublocationName,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideosNearby = `-- name: ListVideosNearby :many
+SELECT video_id, title, src, type, state_id, sublocation_id, latitude, longitude, heading, field_of_view, elevation, timezone, status, badge, publish_at, unpublish_at, featured, sort_order, created_by, created_at, updated_at, state_name, sublocation_name, tags, distance_km FROM (
+  SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+         v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+         v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+         v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+         v.created_by, v.created_at, v.updated_at,
+         s.name AS state_name,
+         COALESCE(sub.name, '') AS sublocation_name,
+         COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                   JOIN tags t ON t.tag_id = vt.tag_id
+                   WHERE vt.video_id = v.video_id), '{}')::text[] AS tags,
+         (6371 * 2 * asin(LEAST(1, sqrt(
+           power(sin(radians(v.latitude - $1::float8) / 2), 2) +
+           cos(radians($1::float8)) * cos(radians(v.latitude)) *
+           power(sin(radians(v.longitude - $2::float8) / 2), 2)
+         ))))::float8 AS distance_km
+  FROM videos v
+  JOIN states s ON s.state_id = v.state_id
+  LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+  WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+    AND v.latitude BETWEEN $3::float8 AND $4::float8
+) nearby
+WHERE distance_km <= $5::float8
+ORDER BY distance_km, title
 `
 
-type ListVideosPaginatedParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
-}
-
-type ListVideosPaginatedRow struct {
-	VideoID         int32     `json:"video_id"`
-	Title           string    `json:"title"`
-	Src             string    `json:"src"`
-	Type            string    `json:"type"`
-	StateID         int32     `json:"state_id"`
-	SublocationID   *int32    `json:"sublocation_id"`
-	Status          string    `json:"status"`
-	CreatedBy       string    `json:"created_by"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
-	StateName       string    `json:"state_name"`
-	SublocationName string    `json:"sublocation_name"`
-	TotalCount      int32     `json:"total_count"`
-}
-
-func (q *Queries) ListVideosPaginated(ctx context.Context, arg ListVideosPaginatedParams) ([]ListVideosPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, listVideosPaginated, arg.Limit, arg.Offset)
+type ListVideosNearbyParams struct {
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	MinLat   float64 `json:"min_lat"`
+	MaxLat   float64 `json:"max_lat"`
+	RadiusKm float64 `json:"radius_km"`
+}
+
+type ListVideosNearbyRow struct {
+	VideoID         int32      `json:"video_id"`
+	Title           string     `json:"title"`
+	Src             string     `json:"src"`
+	Type            string     `json:"type"`
+	StateID         int32      `json:"state_id"`
+	SublocationID   *int32     `json:"sublocation_id"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	Heading         *float64   `json:"heading"`
+	FieldOfView     *float64   `json:"field_of_view"`
+	Elevation       *float64   `json:"elevation"`
+	Timezone        *string    `json:"timezone"`
+	Status          string     `json:"status"`
+	Badge           string     `json:"badge"`
+	PublishAt       *time.Time `json:"publish_at"`
+	UnpublishAt     *time.Time `json:"unpublish_at"`
+	Featured        bool       `json:"featured"`
+	SortOrder       *int32     `json:"sort_order"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	StateName       string     `json:"state_name"`
+	SublocationName string     `json:"sublocation_name"`
+	Tags            []string   `json:"tags"`
+	DistanceKm      float64    `json:"distance_km"`
+}
+
+// Great-circle (haversine) distance from a point, limited to radius_km.
+// The lat/lon pre-filter lets idx_videos_lat_lon narrow the scan first.
+func (q *Queries) ListVideosNearby(ctx context.Context, arg ListVideosNearbyParams) ([]ListVideosNearbyRow, error) {
+	rows, err := q.db.Query(ctx, listVideosNearby,
+		arg.Lat,
+		arg.Lon,
+		arg.MinLat,
+		arg.MaxLat,
+		arg.RadiusKm,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListVideosPaginatedRow{}
+	items := []ListVideosNearbyRow{}
 	for rows.Next() {
-		var i ListVideosPaginatedRow
+		var i ListVideosNearbyRow
 		if err := rows.Scan(
 			&i.VideoID,
 			&i.Title,
@@ -338,13 +1083,25 @@ func (q *Queries) ListVideosPaginated(ctx context.Context, arg ListVideosPaginat
 			&i.Type,
 			&i.StateID,
 			&i.SublocationID,
+			&i.Latitude,
+			&i.Longitude,
+			&i.Heading,
+			&i.FieldOfView,
+			&i.Elevation,
+			&i.Timezone,
 			&i.Status,
+			&i.Badge,
+			&i.PublishAt,
+			&i.UnpublishAt,
+			&i.Featured,
+			&i.SortOrder,
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.StateName,
 			&i.SublocationName,
-			&i.TotalCount,
+			&i.Tags,
+			&i.DistanceKm,
 		); err != nil {
 			return nil, err
 		}
@@ -356,18 +1113,213 @@ func (q *Queries) ListVideosPaginated(ctx context.Context, arg ListVideosPaginat
 	return items, nil
 }
 
+const publishScheduledVideos = `-- name: PublishScheduledVideos :many
+UPDATE videos SET status = 'published', publish_at = NULL
+WHERE status = 'draft' AND publish_at <= $1::timestamptz AND deleted_at IS NULL
+RETURNING video_id
+`
+
+func (q *Queries) PublishScheduledVideos(ctx context.Context, now time.Time) ([]int32, error) {
+	rows, err := q.db.Query(ctx, publishScheduledVideos, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var video_id int32
+		if err := rows.Scan(&video_id); err != nil {
+			return nil, err
+		}
+		items = append(items, video_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reorderVideos = `-- name: ReorderVideos :one
+WITH ranked AS (
+  SELECT v.video_id, v.sort_order,
+         ROW_NUMBER() OVER (ORDER BY v.sort_order NULLS LAST, v.title, v.video_id)::int AS slot
+  FROM videos v WHERE v.deleted_at IS NULL
+), listed AS (
+  SELECT r.video_id, r.slot, ROW_NUMBER() OVER (ORDER BY o.position) AS rank
+  FROM unnest($1::int[]) WITH ORDINALITY AS o(video_id, position)
+  JOIN ranked r ON r.video_id = o.video_id
+), slots AS (
+  SELECT slot, ROW_NUMBER() OVER (ORDER BY slot) AS rank FROM listed
+), target AS (
+  SELECT l.video_id, s.slot FROM listed l JOIN slots s ON s.rank = l.rank
+  UNION ALL
+  SELECT r.video_id, r.slot FROM ranked r
+  WHERE r.sort_order IS NOT NULL AND r.video_id NOT IN (SELECT video_id FROM listed)
+), moved AS (
+  UPDATE videos v SET sort_order = t.slot
+  FROM target t
+  WHERE v.video_id = t.video_id AND v.sort_order IS DISTINCT FROM t.slot
+  RETURNING v.video_id
+)
+SELECT COUNT(*) FROM listed
+`
+
+// Puts ids in the given order, in the positions they hold now: videos left
+// out keep theirs, and positions stay unique. Unpositioned videos (NULL)
+// stay so unless listed. Returns how many of ids are live.
+func (q *Queries) ReorderVideos(ctx context.Context, ids []int32) (int64, error) {
+	row := q.db.QueryRow(ctx, reorderVideos, ids)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const setVideoOnline = `-- name: SetVideoOnline :execrows
+UPDATE videos SET status = CASE WHEN $1::boolean THEN 'published' ELSE 'offline' END
+WHERE video_id = $2
+  AND status = CASE WHEN $1::boolean THEN 'offline' ELSE 'published' END
+  AND deleted_at IS NULL
+`
+
+type SetVideoOnlineParams struct {
+	Online  bool  `json:"online"`
+	VideoID int32 `json:"video_id"`
+}
+
+func (q *Queries) SetVideoOnline(ctx context.Context, arg SetVideoOnlineParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setVideoOnline, arg.Online, arg.VideoID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setVideoSortOrder = `-- name: SetVideoSortOrder :exec
+UPDATE videos SET sort_order = $2 WHERE video_id = $1
+`
+
+type SetVideoSortOrderParams struct {
+	VideoID   int32  `json:"video_id"`
+	SortOrder *int32 `json:"sort_order"`
+}
+
+func (q *Queries) SetVideoSortOrder(ctx context.Context, arg SetVideoSortOrderParams) error {
+	_, err := q.db.Exec(ctx, setVideoSortOrder, arg.VideoID, arg.SortOrder)
+	return err
+}
+
+const softDeleteVideo = `-- name: SoftDeleteVideo :execrows
+UPDATE videos SET deleted_at = now()
+WHERE video_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteVideo(ctx context.Context, videoID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteVideo, videoID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteVideosByState = `-- name: SoftDeleteVideosByState :exec
+UPDATE videos SET deleted_at = $1::timestamptz
+WHERE state_id = $2 AND deleted_at IS NULL
+`
+
+type SoftDeleteVideosByStateParams struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	StateID   int32     `json:"state_id"`
+}
+
+func (q *Queries) SoftDeleteVideosByState(ctx context.Context, arg SoftDeleteVideosByStateParams) error {
+	_, err := q.db.Exec(ctx, softDeleteVideosByState, arg.DeletedAt, arg.StateID)
+	return err
+}
+
+const softDeleteVideosByStream = `-- name: SoftDeleteVideosByStream :many
+UPDATE videos SET deleted_at = now()
+WHERE stream_id = $1 AND deleted_at IS NULL
+RETURNING video_id
+`
+
+func (q *Queries) SoftDeleteVideosByStream(ctx context.Context, streamID *string) ([]int32, error) {
+	rows, err := q.db.Query(ctx, softDeleteVideosByStream, streamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var video_id int32
+		if err := rows.Scan(&video_id); err != nil {
+			return nil, err
+		}
+		items = append(items, video_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unpublishScheduledVideos = `-- name: UnpublishScheduledVideos :many
+UPDATE videos SET status = 'archived', unpublish_at = NULL
+WHERE status IN ('published', 'maintenance', 'offline')
+  AND unpublish_at <= $1::timestamptz AND deleted_at IS NULL
+RETURNING video_id
+`
+
+func (q *Queries) UnpublishScheduledVideos(ctx context.Context, now time.Time) ([]int32, error) {
+	rows, err := q.db.Query(ctx, unpublishScheduledVideos, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int32{}
+	for rows.Next() {
+		var video_id int32
+		if err := rows.Scan(&video_id); err != nil {
+			return nil, err
+		}
+		items = append(items, video_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateVideo = `-- name: UpdateVideo :exec
-UPDATE videos SET title = $2, src = $3, type = $4, state_id = $5, sublocation_id = $6, status = $7 WHERE video_id = $1
+UPDATE videos SET title = $2, src = $3, type = $4, state_id = $5, sublocation_id = $6, status = $7,
+                  latitude = $8, longitude = $9, heading = $10, elevation = $11,
+                  publish_at = $12, unpublish_at = $13, featured = $14,
+                  timezone = $15, field_of_view = $16, camera_model = $17, firmware = $18,
+                  owner_contact = $19, install_date = $20, notes = $21
+WHERE video_id = $1
 `
 
 type UpdateVideoParams struct {
-	VideoID       int32  `json:"video_id"`
-	Title         string `json:"title"`
-	Src           string `json:"src"`
-	Type          string `json:"type"`
-	StateID       int32  `json:"state_id"`
-	SublocationID *int32 `json:"sublocation_id"`
-	Status        string `json:"status"`
+	VideoID       int32       `json:"video_id"`
+	Title         string      `json:"title"`
+	Src           string      `json:"src"`
+	Type          string      `json:"type"`
+	StateID       int32       `json:"state_id"`
+	SublocationID *int32      `json:"sublocation_id"`
+	Status        string      `json:"status"`
+	Latitude      *float64    `json:"latitude"`
+	Longitude     *float64    `json:"longitude"`
+	Heading       *float64    `json:"heading"`
+	Elevation     *float64    `json:"elevation"`
+	PublishAt     *time.Time  `json:"publish_at"`
+	UnpublishAt   *time.Time  `json:"unpublish_at"`
+	Featured      bool        `json:"featured"`
+	Timezone      *string     `json:"timezone"`
+	FieldOfView   *float64    `json:"field_of_view"`
+	CameraModel   *string     `json:"camera_model"`
+	Firmware      *string     `json:"firmware"`
+	OwnerContact  *string     `json:"owner_contact"`
+	InstallDate   pgtype.Date `json:"install_date"`
+	Notes         *string     `json:"notes"`
 }
 
 func (q *Queries) UpdateVideo(ctx context.Context, arg UpdateVideoParams) error {
@@ -379,6 +1331,20 @@ func (q *Queries) UpdateVideo(ctx context.Context, arg UpdateVideoParams) error
 		arg.StateID,
 		arg.SublocationID,
 		arg.Status,
+		arg.Latitude,
+		arg.Longitude,
+		arg.Heading,
+		arg.Elevation,
+		arg.PublishAt,
+		arg.UnpublishAt,
+		arg.Featured,
+		arg.Timezone,
+		arg.FieldOfView,
+		arg.CameraModel,
+		arg.Firmware,
+		arg.OwnerContact,
+		arg.InstallDate,
+		arg.Notes,
 	)
 	return err
 }
@@ -0,0 +1,416 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Audit actions.
+const (
+	actionCreate  = "create"
+	actionUpdate  = "update"
+	actionDelete  = "delete"
+	actionRestore = "restore"
+	actionRevert  = "revert"
+	actionRestart = "restart"
+	actionApprove = "approve"
+	actionReject  = "reject"
+)
+
+// Audited entity types.
+const (
+	entityState       = "state"
+	entitySublocation = "sublocation"
+	entityVideo       = "video"
+	entityTag         = "tag"
+	entityStream      = "stream"
+	entityCountry     = "country"
+	entityPromotion   = "promotion"
+	entitySubmission  = "submission"
+)
+
+// errCannotRevert is returned when an audit event has no inverse.
+var errCannotRevert = errors.New("event cannot be reverted")
+
+// ListAudit handles GET /audit — audit events, newest first, filterable by
+// actor, action, entity_type, entity_id and an RFC 3339 since/until range.
+// Paged by number or cursor; see parsePagination (admin only).
+func ListAudit(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		p, msg := parsePagination(r)
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		params := db.ListAuditEventsParams{
+			Actor:      optionalParam(q.Get("actor")),
+			Action:     optionalParam(q.Get("action")),
+			EntityType: optionalParam(q.Get("entity_type")),
+			EntityID:   optionalParam(q.Get("entity_id")),
+			CursorID:   cursorID[int64](p),
+			Backward:   p.backward(),
+			PageLimit:  p.limit(),
+			PageOffset: p.offset(),
+		}
+		for name, dst := range map[string]**time.Time{"since": &params.Since, "until": &params.Until} {
+			s := q.Get(name)
+			if s == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": name + " must be an RFC 3339 timestamp"})
+				return
+			}
+			*dst = &t
+		}
+
+		queries := db.New(pool)
+		rows, err := queries.ListAuditEvents(r.Context(), params)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		resp := paginate(p, rows, func(ev db.AuditEvent) pageCursor {
+			return pageCursor{ID: ev.AuditID}
+		})
+		if p.WithTotal {
+			total, err := queries.CountAuditEvents(r.Context(), db.CountAuditEventsParams{
+				Actor:      params.Actor,
+				Action:     params.Action,
+				EntityType: params.EntityType,
+				EntityID:   params.EntityID,
+				Since:      params.Since,
+				Until:      params.Until,
+			})
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			resp.Total = &total
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// RevertAudit handles POST /audit/{id}/revert — undoes a single state,
+// sublocation or video change: creates and restores are trashed, deletes are
+// restored and updates get their previous values back. The revert is itself
+// recorded (admin only).
+func RevertAudit(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id <= 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid audit id"})
+			return
+		}
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
+
+		ev, err := qtx.GetAuditEvent(r.Context(), id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "audit event not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		entityID, err := strconv.Atoi(ev.EntityID)
+		if err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": ev.EntityType + " events cannot be reverted"})
+			return
+		}
+
+		var before, after any
+		switch ev.EntityType {
+		case entityState:
+			before, after, err = revertState(r.Context(), qtx, ev, int32(entityID))
+		case entitySublocation:
+			before, after, err = revertSublocation(r.Context(), qtx, ev, int32(entityID))
+		case entityVideo:
+			before, after, err = revertVideo(r.Context(), qtx, ev, int32(entityID))
+		default:
+			err = errCannotRevert
+		}
+		if err != nil {
+			writeRevertError(w, ev, err)
+			return
+		}
+
+		params := auditParams(r.Context(), actionRevert, ev.EntityType, ev.EntityID, before, after)
+		params.RevertsAuditID = &ev.AuditID
+		created, err := qtx.CreateAuditEvent(r.Context(), params)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		invalidate(r.Context(), c, "states:*", "sublocations:*", "videos:*", "tags:*", "search:*")
+		writeJSON(w, http.StatusOK, created)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// recordAudit logs a mutation made by the request's actor. before and after
+// are JSON snapshots of the entity (nil when there is none). Failures are
+// logged rather than returned: the audit trail must never block the change
+// it describes. q must not be bound to a transaction, where a failed insert
+// would abort it; use recordAuditTx there.
+func recordAudit(ctx context.Context, q *db.Queries, action, entityType string, entityID, before, after any) {
+	params := auditParams(ctx, action, entityType, fmt.Sprint(entityID), before, after)
+	if _, err := q.CreateAuditEvent(ctx, params); err != nil {
+		slog.Warn("audit: record failed",
+			"action", action, "entity_type", entityType, "entity_id", params.EntityID, "error", err)
+	}
+}
+
+// recordAuditTx is recordAudit inside tx, so the event commits or rolls
+// back with the change. The insert runs under a savepoint: if it fails, the
+// failure is logged and tx carries on without the event.
+func recordAuditTx(ctx context.Context, tx pgx.Tx, action, entityType string, entityID, before, after any) {
+	params := auditParams(ctx, action, entityType, fmt.Sprint(entityID), before, after)
+	sp, err := tx.Begin(ctx)
+	if err == nil {
+		if _, err = db.New(sp).CreateAuditEvent(ctx, params); err == nil {
+			err = sp.Commit(ctx)
+		}
+		_ = sp.Rollback(ctx)
+	}
+	if err != nil {
+		slog.Warn("audit: record failed",
+			"action", action, "entity_type", entityType, "entity_id", params.EntityID, "error", err)
+	}
+}
+
+// auditParams builds an audit event attributed to the request's actor, or to
+// "system" when there is none.
+func auditParams(ctx context.Context, action, entityType, entityID string, before, after any) db.CreateAuditEventParams {
+	actor, actorType := middleware.Actor(ctx)
+	if actor == "" {
+		actor, actorType = "system", "system"
+	}
+	return db.CreateAuditEventParams{
+		Actor:      actor,
+		ActorType:  actorType,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     auditSnapshot(before),
+		After:      auditSnapshot(after),
+	}
+}
+
+// auditSnapshot marshals v for a JSONB column; nil becomes SQL NULL.
+func auditSnapshot(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		slog.Warn("audit: snapshot failed", "error", err)
+		return nil
+	}
+	return b
+}
+
+// revertState undoes ev on state id and returns the before/after snapshots
+// of the revert. q should be bound to a transaction.
+func revertState(ctx context.Context, q *db.Queries, ev db.AuditEvent, id int32) (before, after any, err error) {
+	current, err := q.GetStateByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, errNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch ev.Action {
+	case actionCreate, actionRestore:
+		_, err = trashState(ctx, q, current.Slug)
+	case actionDelete:
+		err = restoreState(ctx, q, id)
+	case actionUpdate:
+		var prev db.GetStateByIDRow
+		if err := unmarshalBefore(ev, &prev); err != nil {
+			return nil, nil, err
+		}
+		if prev.RegionType == "" {
+			// Snapshot from before regions had types.
+			prev.RegionType = current.RegionType
+		}
+		err = q.UpdateState(ctx, db.UpdateStateParams{
+			StateID:     id,
+			Name:        prev.Name,
+			Description: prev.Description,
+			RegionType:  prev.RegionType,
+		})
+		if err == nil && prev.Slug != "" && prev.Slug != current.Slug {
+			_, err = q.ChangeStateSlug(ctx, db.ChangeStateSlugParams{StateID: id, Slug: prev.Slug})
+		}
+	default:
+		err = errCannotRevert
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reverted, err := q.GetStateByID(ctx, id)
+	return current, reverted, err
+}
+
+// revertSublocation is revertState for sublocations.
+func revertSublocation(ctx context.Context, q *db.Queries, ev db.AuditEvent, id int32) (before, after any, err error) {
+	current, err := q.GetSublocationByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, errNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch ev.Action {
+	case actionCreate, actionRestore:
+		_, err = q.SoftDeleteSublocation(ctx, id)
+	case actionDelete:
+		err = restoreSublocation(ctx, q, id)
+	case actionUpdate:
+		var prev db.GetSublocationByIDRow
+		if err := unmarshalBefore(ev, &prev); err != nil {
+			return nil, nil, err
+		}
+		var slug *string
+		if prev.Slug != "" {
+			slug = &prev.Slug
+		}
+		err = q.UpdateSublocation(ctx, db.UpdateSublocationParams{
+			SublocationID: id,
+			Name:          prev.Name,
+			Description:   prev.Description,
+			StateID:       prev.StateID,
+			ParentID:      prev.ParentID,
+			Slug:          slug,
+		})
+	default:
+		err = errCannotRevert
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reverted, err := q.GetSublocationByID(ctx, id)
+	return current, reverted, err
+}
+
+// revertVideo is revertState for videos, including their tags.
+func revertVideo(ctx context.Context, q *db.Queries, ev db.AuditEvent, id int32) (before, after any, err error) {
+	current, err := q.GetVideoByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, errNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch ev.Action {
+	case actionCreate, actionRestore:
+		_, err = q.SoftDeleteVideo(ctx, id)
+	case actionDelete:
+		err = restoreVideo(ctx, q, id)
+	case actionUpdate:
+		var prev db.GetVideoByIDRow
+		if err := unmarshalBefore(ev, &prev); err != nil {
+			return nil, nil, err
+		}
+		if err := q.UpdateVideo(ctx, db.UpdateVideoParams{
+			VideoID:       id,
+			Title:         prev.Title,
+			Src:           prev.Src,
+			Type:          prev.Type,
+			StateID:       prev.StateID,
+			SublocationID: prev.SublocationID,
+			Status:        prev.Status,
+			Latitude:      prev.Latitude,
+			Longitude:     prev.Longitude,
+			Heading:       prev.Heading,
+			Elevation:     prev.Elevation,
+			PublishAt:     prev.PublishAt,
+			UnpublishAt:   prev.UnpublishAt,
+			Featured:      prev.Featured,
+			Timezone:      prev.Timezone,
+			FieldOfView:   prev.FieldOfView,
+			CameraModel:   prev.CameraModel,
+			Firmware:      prev.Firmware,
+			OwnerContact:  prev.OwnerContact,
+			InstallDate:   prev.InstallDate,
+			Notes:         prev.Notes,
+		}); err != nil {
+			return nil, nil, err
+		}
+		err = setVideoTags(ctx, q, id, prev.Tags)
+	default:
+		err = errCannotRevert
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reverted, err := q.GetVideoByID(ctx, id)
+	return current, reverted, err
+}
+
+// unmarshalBefore decodes the "before" snapshot of an update event.
+func unmarshalBefore(ev db.AuditEvent, v any) error {
+	if len(ev.Before) == 0 {
+		return errCannotRevert
+	}
+	return json.Unmarshal(ev.Before, v)
+}
+
+// writeRevertError maps a revert error to a response.
+func writeRevertError(w http.ResponseWriter, ev db.AuditEvent, err error) {
+	switch {
+	case errors.Is(err, errCannotRevert):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": ev.EntityType + " " + ev.Action + " events cannot be reverted"})
+	case errors.Is(err, errUnknownTag):
+		writeTagError(w, err)
+	default:
+		writeTrashError(w, ev.EntityType, err)
+	}
+}
+
+// optionalParam returns nil for an empty query param.
+func optionalParam(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
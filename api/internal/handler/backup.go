@@ -0,0 +1,636 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// Version 2 added videos' stream links; restoring a version 1 backup
+	// leaves them alone.
+	catalogVersion  = 2
+	maxRestoreBytes = 50 << 20
+
+	actionImport  = "import"
+	entityCatalog = "catalog"
+)
+
+// catalogBackup is the export/restore document. Relationships are expressed
+// by slug so it can be loaded into a database with different serial IDs.
+// Trashed rows are not included.
+type catalogBackup struct {
+	Version      int                        `json:"version"`
+	ExportedAt   time.Time                  `json:"exported_at"`
+	Countries    []db.ExportCountriesRow    `json:"countries"`
+	States       []db.ExportStatesRow       `json:"states"`
+	Sublocations []db.ExportSublocationsRow `json:"sublocations"`
+	Tags         []db.ExportTagsRow         `json:"tags"`
+	Videos       []db.ExportVideosRow       `json:"videos"`
+	Promotions   []db.ExportPromotionsRow   `json:"promotions"`
+}
+
+type restoreResult struct {
+	Mode          string `json:"mode"`
+	Countries     int    `json:"countries"`
+	States        int    `json:"states"`
+	Sublocations  int    `json:"sublocations"`
+	Tags          int    `json:"tags"`
+	VideosCreated int    `json:"videos_created"`
+	VideosUpdated int    `json:"videos_updated"`
+	VideosRemoved int    `json:"videos_removed,omitempty"`
+	Promotions    int    `json:"promotions"`
+}
+
+// ExportCatalog handles GET /admin/export — a point-in-time JSON backup of
+// states, sublocations, tags, videos and homepage promotions (admin only).
+func ExportCatalog(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// A repeatable-read snapshot keeps the lists consistent with
+		// each other even while admins are editing.
+		tx, err := pool.BeginTx(r.Context(), pgx.TxOptions{
+			IsoLevel:   pgx.RepeatableRead,
+			AccessMode: pgx.ReadOnly,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+
+		backup, err := exportCatalog(r.Context(), db.New(pool).WithTx(tx))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		filename := "nationcam-catalog-" + backup.ExportedAt.Format("20060102-150405") + ".json"
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		writeJSON(w, http.StatusOK, backup)
+	}
+}
+
+// RestoreCatalog handles POST /admin/restore?mode=merge|replace — loads an
+// export. merge (default) upserts states, sublocations and tags by slug,
+// videos by src and promotions by slot, video and start, leaving everything
+// else alone. replace does the same, then deletes whatever the backup doesn't
+// hold, trash included; matched videos keep their IDs, so viewers' favorites
+// and lists of them survive. Either way
+// it's one transaction; a row whose name another row already has fails it
+// with a 409 (admin only).
+func RestoreCatalog(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mode := r.URL.Query().Get("mode")
+		switch mode {
+		case "":
+			mode = "merge"
+		case "merge", "replace":
+		default:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "mode must be merge or replace"})
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxRestoreBytes)
+		var backup catalogBackup
+		if err := readJSON(r, &backup); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		if backup.Version < 1 || backup.Version > catalogVersion {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("unsupported backup version %d", backup.Version)})
+			return
+		}
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
+
+		res, err := restoreCatalog(r.Context(), qtx, backup, mode)
+		var invalid *invalidBackupError
+		if errors.As(err, &invalid) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": invalid.Error()})
+			return
+		}
+		var conflict *restoreConflictError
+		if errors.As(err, &conflict) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": conflict.Error()})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAuditTx(r.Context(), tx, actionImport, entityCatalog, mode, nil, res)
+
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		invalidate(r.Context(), c, "states:*", "sublocations:*", "videos:*", "tags:*", "search:*")
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// invalidBackupError reports a backup that references something it (and,
+// for merges, the database) doesn't contain.
+type invalidBackupError struct{ msg string }
+
+func (e *invalidBackupError) Error() string { return e.msg }
+
+func invalidBackup(format string, args ...any) error {
+	return &invalidBackupError{msg: fmt.Sprintf(format, args...)}
+}
+
+// restoreConflictError reports a backup row that clashes with another row
+// (in the backup or the database, which replace prunes only afterwards) on a
+// unique name: rows are matched by slug, but names must be unique too.
+type restoreConflictError struct{ msg string }
+
+func (e *restoreConflictError) Error() string { return e.msg }
+
+func restoreConflict(format string, args ...any) error {
+	return &restoreConflictError{msg: fmt.Sprintf(format, args...)}
+}
+
+func exportCatalog(ctx context.Context, q *db.Queries) (catalogBackup, error) {
+	backup := catalogBackup{Version: catalogVersion, ExportedAt: time.Now().UTC()}
+
+	var err error
+	if backup.Countries, err = q.ExportCountries(ctx); err != nil {
+		return backup, err
+	}
+	if backup.States, err = q.ExportStates(ctx); err != nil {
+		return backup, err
+	}
+	if backup.Sublocations, err = q.ExportSublocations(ctx); err != nil {
+		return backup, err
+	}
+	if backup.Tags, err = q.ExportTags(ctx); err != nil {
+		return backup, err
+	}
+	if backup.Videos, err = q.ExportVideos(ctx); err != nil {
+		return backup, err
+	}
+	if backup.Promotions, err = q.ExportPromotions(ctx); err != nil {
+		return backup, err
+	}
+	return backup, nil
+}
+
+// restoreCatalog loads backup using q, which must be bound to a transaction:
+// a failure part-way leaves the catalog half-restored otherwise. A replace
+// upserts like a merge, then deletes every row the backup didn't touch, so
+// surviving videos keep their IDs and with them viewers' favorites and lists.
+func restoreCatalog(ctx context.Context, q *db.Queries, backup catalogBackup, mode string) (restoreResult, error) {
+	res := restoreResult{Mode: mode}
+	replace := mode == "replace"
+
+	// Countries are reference data: replace mode upserts rather than prunes them.
+	for _, co := range backup.Countries {
+		if co.RegionType == "" {
+			co.RegionType = regionState
+		}
+		if !countryCodePattern.MatchString(co.Code) || co.Name == "" || validateRegionType(co.RegionType) != "" {
+			return res, invalidBackup("invalid country %q", co.Code)
+		}
+		if err := q.UpsertCountry(ctx, db.UpsertCountryParams{
+			Code:       co.Code,
+			Name:       co.Name,
+			RegionType: co.RegionType,
+		}); err != nil {
+			return res, fmt.Errorf("country %q: %w", co.Code, err)
+		}
+		res.Countries++
+	}
+
+	stateIDs := make(map[string]int32, len(backup.States))
+	for _, s := range backup.States {
+		if s.Slug == "" || s.Name == "" {
+			return res, invalidBackup("every state needs a slug and name")
+		}
+		// Backups from before countries hold only US states.
+		if s.CountryCode == "" {
+			s.CountryCode = usCountryCode
+		}
+		if s.RegionType == "" {
+			s.RegionType = regionState
+		}
+		if msg := validateRegionType(s.RegionType); msg != "" {
+			return res, invalidBackup("state %q: %s", s.Slug, msg)
+		}
+		id, err := q.UpsertStateBySlug(ctx, db.UpsertStateBySlugParams{
+			Slug:        s.Slug,
+			Name:        s.Name,
+			Description: s.Description,
+			CountryCode: s.CountryCode,
+			RegionType:  s.RegionType,
+			SortOrder:   s.SortOrder,
+		})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return res, invalidBackup("state %q: unknown country %q", s.Slug, s.CountryCode)
+		}
+		if isUniqueViolation(err) {
+			return res, restoreConflict("state %q: another state in %s is already named %q", s.Slug, s.CountryCode, s.Name)
+		}
+		if err != nil {
+			return res, fmt.Errorf("state %q: %w", s.Slug, err)
+		}
+		stateIDs[s.Slug] = id
+		res.States++
+	}
+	// Only what the backup itself holds survives a replace, so there
+	// references to anything else are errors rather than database lookups.
+	stateID := func(slug string) (int32, error) {
+		if id, ok := stateIDs[slug]; ok {
+			return id, nil
+		}
+		if replace {
+			return 0, invalidBackup("unknown state %q", slug)
+		}
+		// Merges may reference states already in the database.
+		row, err := q.GetStateBySlug(ctx, slug)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, invalidBackup("unknown state %q", slug)
+		}
+		if err != nil {
+			return 0, err
+		}
+		stateIDs[slug] = row.StateID
+		return row.StateID, nil
+	}
+
+	// Sublocation IDs by state and slug, for linking parents once all exist.
+	type subKey struct {
+		stateID int32
+		slug    string
+	}
+	subIDs := make(map[subKey]int32, len(backup.Sublocations))
+	for _, sub := range backup.Sublocations {
+		if sub.Slug == "" || sub.Name == "" {
+			return res, invalidBackup("every sublocation needs a slug and name")
+		}
+		sid, err := stateID(sub.State)
+		if err != nil {
+			return res, fmt.Errorf("sublocation %q: %w", sub.Slug, err)
+		}
+		id, err := q.UpsertSublocationBySlug(ctx, db.UpsertSublocationBySlugParams{
+			StateID:     sid,
+			Slug:        sub.Slug,
+			Name:        sub.Name,
+			Description: sub.Description,
+			SortOrder:   sub.SortOrder,
+		})
+		if err != nil {
+			return res, fmt.Errorf("sublocation %q: %w", sub.Slug, err)
+		}
+		subIDs[subKey{sid, sub.Slug}] = id
+		res.Sublocations++
+	}
+	keptSubs := make([]int32, 0, len(subIDs))
+	for _, id := range subIDs {
+		keptSubs = append(keptSubs, id)
+	}
+
+	sublocationID := func(stateID int32, ref string) (int32, bool, error) {
+		if id, ok := subIDs[subKey{stateID, ref}]; ok {
+			return id, true, nil
+		}
+		if replace {
+			return 0, false, nil
+		}
+		// Merges may reference sublocations already in the database.
+		found, err := q.GetSublocationByRef(ctx, db.GetSublocationByRefParams{StateID: stateID, Ref: ref})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		return found.SublocationID, true, nil
+	}
+
+	for _, sub := range backup.Sublocations {
+		sid, _ := stateID(sub.State)
+		var parentID *int32
+		if sub.Parent != nil && *sub.Parent != "" {
+			id, ok, err := sublocationID(sid, *sub.Parent)
+			if err != nil {
+				return res, err
+			}
+			if !ok {
+				return res, invalidBackup("sublocation %q has unknown parent %q", sub.Slug, *sub.Parent)
+			}
+			parentID = &id
+		}
+		if err := q.SetSublocationParent(ctx, db.SetSublocationParentParams{
+			SublocationID: subIDs[subKey{sid, sub.Slug}],
+			ParentID:      parentID,
+		}); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+				return res, invalidBackup("sublocation %q is nested under its own descendant", sub.Slug)
+			}
+			return res, fmt.Errorf("sublocation %q: %w", sub.Slug, err)
+		}
+	}
+
+	tagSlugs := make([]string, 0, len(backup.Tags))
+	for _, t := range backup.Tags {
+		if t.Slug == "" || t.Name == "" {
+			return res, invalidBackup("every tag needs a slug and name")
+		}
+		err := q.UpsertTagBySlug(ctx, db.UpsertTagBySlugParams{Slug: t.Slug, Name: t.Name})
+		if isUniqueViolation(err) {
+			return res, restoreConflict("tag %q: another tag is already named %q", t.Slug, t.Name)
+		}
+		if err != nil {
+			return res, fmt.Errorf("tag %q: %w", t.Slug, err)
+		}
+		tagSlugs = append(tagSlugs, t.Slug)
+		res.Tags++
+	}
+
+	videoIDs := make(map[string]int32, len(backup.Videos))
+	keptVideos := make([]int32, 0, len(backup.Videos))
+	for _, v := range backup.Videos {
+		if v.Title == "" || v.Src == "" {
+			return res, invalidBackup("every video needs a title and src")
+		}
+		if msg := validateGeo(v.Latitude, v.Longitude, v.Heading); msg != "" {
+			return res, invalidBackup("video %q: %s", v.Src, msg)
+		}
+		if replace {
+			for _, slug := range normalizeTagSlugs(v.Tags) {
+				if !slices.Contains(tagSlugs, slug) {
+					return res, invalidBackup("video %q has a tag missing from the backup", v.Src)
+				}
+			}
+		}
+		id, created, err := restoreVideoRow(ctx, q, v, stateID, sublocationID)
+		if err != nil {
+			return res, fmt.Errorf("video %q: %w", v.Src, err)
+		}
+		videoIDs[v.Src] = id
+		keptVideos = append(keptVideos, id)
+		if created {
+			res.VideosCreated++
+		} else {
+			res.VideosUpdated++
+		}
+	}
+
+	// Backups from before promotions leave them alone.
+	if replace && backup.Promotions != nil {
+		if _, err := q.DeleteAllPromotions(ctx); err != nil {
+			return res, err
+		}
+	}
+	for _, p := range backup.Promotions {
+		id, ok := videoIDs[p.Video]
+		if !ok && !replace {
+			// Merges may promote videos already in the database.
+			found, err := q.GetLiveVideoIDBySrc(ctx, p.Video)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return res, err
+			}
+			id, ok = found, err == nil
+		}
+		if !ok {
+			return res, invalidBackup("promotion in slot %q has unknown video %q", p.Slot, p.Video)
+		}
+		if err := restorePromotionRow(ctx, q, p, id); err != nil {
+			return res, fmt.Errorf("promotion in slot %q: %w", p.Slot, err)
+		}
+		res.Promotions++
+	}
+
+	if replace {
+		// Videos first so their tag assignments cascade away before the tags.
+		n, err := q.PruneVideos(ctx, keptVideos)
+		if err != nil {
+			return res, err
+		}
+		res.VideosRemoved = int(n)
+		if _, err := q.PruneSublocations(ctx, keptSubs); err != nil {
+			return res, err
+		}
+		states := make([]int32, 0, len(stateIDs))
+		for _, id := range stateIDs {
+			states = append(states, id)
+		}
+		if _, err := q.PruneStates(ctx, states); err != nil {
+			return res, err
+		}
+		if _, err := q.PruneTags(ctx, tagSlugs); err != nil {
+			return res, err
+		}
+	}
+
+	// Streams are relinked last, once a replace has pruned the videos that
+	// held them before.
+	if backup.Version >= 2 {
+		if err := q.ClearVideoStreamIDs(ctx, keptVideos); err != nil {
+			return res, err
+		}
+		for _, v := range backup.Videos {
+			if v.StreamID == nil {
+				continue
+			}
+			err := q.SetVideoStreamID(ctx, db.SetVideoStreamIDParams{VideoID: videoIDs[v.Src], StreamID: v.StreamID})
+			if isUniqueViolation(err) {
+				return res, restoreConflict("video %q: stream %q is linked to another video", v.Src, *v.StreamID)
+			}
+			if err != nil {
+				return res, fmt.Errorf("video %q: %w", v.Src, err)
+			}
+		}
+	}
+	return res, nil
+}
+
+// restorePromotionRow upserts one exported promotion of videoID, matching
+// the slot's default or a scheduled promotion of the same video and start.
+func restorePromotionRow(ctx context.Context, q *db.Queries, p db.ExportPromotionsRow, videoID int32) error {
+	if !slotPattern.MatchString(p.Slot) {
+		return invalidBackup("invalid slot")
+	}
+	if p.Timezone == "" {
+		p.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil {
+		return invalidBackup("unknown timezone %q", p.Timezone)
+	}
+	switch {
+	case p.IsDefault && (p.StartsAt != nil || p.EndsAt != nil):
+		return invalidBackup("a default promotion has no starts_at or ends_at")
+	case !p.IsDefault && p.StartsAt == nil:
+		return invalidBackup("starts_at is required unless is_default is set")
+	case p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt):
+		return invalidBackup("ends_at must be after starts_at")
+	}
+
+	id, err := q.GetRestoredPromotionID(ctx, db.GetRestoredPromotionIDParams{
+		Slot:      p.Slot,
+		IsDefault: p.IsDefault,
+		VideoID:   videoID,
+		StartsAt:  p.StartsAt,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = q.CreatePromotion(ctx, db.CreatePromotionParams{
+			Slot:      p.Slot,
+			VideoID:   videoID,
+			Title:     p.Title,
+			StartsAt:  p.StartsAt,
+			EndsAt:    p.EndsAt,
+			Timezone:  p.Timezone,
+			Priority:  p.Priority,
+			IsDefault: p.IsDefault,
+			CreatedBy: p.CreatedBy,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	_, err = q.UpdatePromotion(ctx, db.UpdatePromotionParams{
+		PromotionID: id,
+		Slot:        p.Slot,
+		VideoID:     videoID,
+		Title:       p.Title,
+		StartsAt:    p.StartsAt,
+		EndsAt:      p.EndsAt,
+		Timezone:    p.Timezone,
+		Priority:    p.Priority,
+		IsDefault:   p.IsDefault,
+	})
+	return err
+}
+
+// restoreVideoRow upserts one exported video, matching an existing live
+// video by src. It returns the video's ID and whether a new row was created.
+func restoreVideoRow(ctx context.Context, q *db.Queries, v db.ExportVideosRow,
+	stateID func(string) (int32, error),
+	sublocationID func(int32, string) (int32, bool, error),
+) (int32, bool, error) {
+	sid, err := stateID(v.State)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var subID *int32
+	if v.Sublocation != nil && *v.Sublocation != "" {
+		id, ok, err := sublocationID(sid, *v.Sublocation)
+		if err != nil {
+			return 0, false, err
+		}
+		if !ok {
+			return 0, false, invalidBackup("unknown sublocation %q in state %q", *v.Sublocation, v.State)
+		}
+		subID = &id
+	}
+
+	if v.Type == "" {
+		v.Type = "application/x-mpegURL"
+	}
+	v.Status = normalizeVideoStatus(v.Status)
+	if v.Status == "" {
+		v.Status = statusPublished
+	}
+	if _, known := videoTransitions[v.Status]; !known {
+		return 0, false, invalidBackup("unknown status %q", v.Status)
+	}
+	if v.Timezone != nil {
+		if _, err := time.LoadLocation(*v.Timezone); err != nil {
+			return 0, false, invalidBackup("unknown timezone %q", *v.Timezone)
+		}
+	}
+
+	id, err := q.GetLiveVideoIDBySrc(ctx, v.Src)
+	created := errors.Is(err, pgx.ErrNoRows)
+	switch {
+	case created:
+		row, err := q.CreateVideo(ctx, db.CreateVideoParams{
+			Title:         v.Title,
+			Src:           v.Src,
+			Type:          v.Type,
+			StateID:       sid,
+			SublocationID: subID,
+			Status:        v.Status,
+			CreatedBy:     v.CreatedBy,
+			Latitude:      v.Latitude,
+			Longitude:     v.Longitude,
+			Heading:       v.Heading,
+			Elevation:     v.Elevation,
+			PublishAt:     v.PublishAt,
+			UnpublishAt:   v.UnpublishAt,
+			Featured:      v.Featured,
+			Timezone:      v.Timezone,
+			FieldOfView:   v.FieldOfView,
+			CameraModel:   v.CameraModel,
+			Firmware:      v.Firmware,
+			OwnerContact:  v.OwnerContact,
+			InstallDate:   v.InstallDate,
+			Notes:         v.Notes,
+		})
+		if err != nil {
+			return 0, false, err
+		}
+		id = row.VideoID
+	case err != nil:
+		return 0, false, err
+	default:
+		if err := q.UpdateVideo(ctx, db.UpdateVideoParams{
+			VideoID:       id,
+			Title:         v.Title,
+			Src:           v.Src,
+			Type:          v.Type,
+			StateID:       sid,
+			SublocationID: subID,
+			Status:        v.Status,
+			Latitude:      v.Latitude,
+			Longitude:     v.Longitude,
+			Heading:       v.Heading,
+			Elevation:     v.Elevation,
+			PublishAt:     v.PublishAt,
+			UnpublishAt:   v.UnpublishAt,
+			Featured:      v.Featured,
+			Timezone:      v.Timezone,
+			FieldOfView:   v.FieldOfView,
+			CameraModel:   v.CameraModel,
+			Firmware:      v.Firmware,
+			OwnerContact:  v.OwnerContact,
+			InstallDate:   v.InstallDate,
+			Notes:         v.Notes,
+		}); err != nil {
+			return 0, false, err
+		}
+	}
+
+	if err := q.SetVideoSortOrder(ctx, db.SetVideoSortOrderParams{VideoID: id, SortOrder: v.SortOrder}); err != nil {
+		return 0, false, err
+	}
+	if err := setVideoTags(ctx, q, id, v.Tags); err != nil {
+		if errors.Is(err, errUnknownTag) {
+			return 0, false, invalidBackup("video %q has a tag missing from the backup", v.Src)
+		}
+		return 0, false, err
+	}
+	return id, created, nil
+}
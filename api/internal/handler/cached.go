@@ -2,38 +2,248 @@ package handler
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/brandon-relentnet/nationcam/api/internal/cache"
 )
 
+// cacheControls is how long clients and the nginx front end may reuse a
+// cached response before revalidating it, by cache key prefix; the first
+// match wins. Revalidation is cheap: a matching If-None-Match gets a 304.
+var cacheControls = []struct {
+	prefix string
+	maxAge time.Duration
+}{
+	// Map exports feed GIS tools that poll; positions rarely move.
+	{"videos:geojson:", 5 * time.Minute},
+	{"videos:kml:", 5 * time.Minute},
+	// Video listings carry lifecycle status and daylight, which change
+	// without an edit.
+	{"videos:", 30 * time.Second},
+	{"search:", time.Minute},
+	{"states:", 5 * time.Minute},
+	{"sublocations:", 5 * time.Minute},
+	{"tags:", 5 * time.Minute},
+}
+
+// defaultMaxAge is the Cache-Control max-age for keys cacheControls misses.
+const defaultMaxAge = time.Minute
+
 // cachedHandler wraps a handler so its JSON response is cached in Redis.
 // On cache hit the stored JSON is returned directly; on miss the handler runs
-// and its output is stored.
+// and its output is stored. Successful responses carry a strong ETag, a
+// Last-Modified from the newest updated_at in the body and a Cache-Control
+// from cacheControls; a request whose If-None-Match matches gets a 304.
 func cachedHandler(c *cache.Cache, key string, handler http.HandlerFunc) http.HandlerFunc {
+	return cachedHandlerWithType(c, key, "application/json", handler)
+}
+
+// cachedHandlerWithType is cachedHandler for non-JSON bodies (GeoJSON, KML);
+// contentType is sent on cache hits.
+func cachedHandlerWithType(c *cache.Cache, key, contentType string, handler http.HandlerFunc) http.HandlerFunc {
+	return cachedResponse(c, key, contentType, func(w http.ResponseWriter, r *http.Request) time.Duration {
+		handler(w, r)
+		return cache.DefaultTTL
+	})
+}
+
+// cachedHandlerTTL is cachedHandler for responses that go stale at a known
+// time: handler writes the response and returns how long it stays valid.
+func cachedHandlerTTL(c *cache.Cache, key string, handler func(http.ResponseWriter, *http.Request) time.Duration) http.HandlerFunc {
+	return cachedResponse(c, key, "application/json", handler)
+}
+
+func cachedResponse(c *cache.Cache, key, contentType string, handler func(http.ResponseWriter, *http.Request) time.Duration) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
 		// Try cache first.
 		if cached, err := c.Get(ctx, key); err == nil && cached != "" {
-			w.Header().Set("Content-Type", "application/json")
-			w.Header().Set("X-Cache", "HIT")
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte(cached))
-			return
+			if entry, ok := decodeCacheEntry(cached); ok {
+				w.Header().Set("Content-Type", contentType)
+				w.Header().Set("X-Cache", "HIT")
+				serveCacheEntry(w, r, key, entry)
+				return
+			}
 		}
 
-		// Cache miss — run handler and capture output.
+		// Cache miss — run handler and capture output. The body is held
+		// back until the validators are known.
 		rec := &responseRecorder{ResponseWriter: w, body: &bytes.Buffer{}}
-		handler(rec, r)
+		ttl := handler(rec, r)
 
 		// Only cache successful responses.
-		if rec.status == http.StatusOK || rec.status == 0 {
-			_ = c.Set(ctx, key, rec.body.String(), cache.DefaultTTL)
+		if rec.status != http.StatusOK && rec.status != 0 {
+			w.WriteHeader(rec.status)
+			_, _ = w.Write(rec.body.Bytes())
+			return
+		}
+		entry := newCacheEntry(rec.body.Bytes(), time.Now().Add(ttl))
+		if ttl > 0 {
+			_ = c.Set(ctx, key, entry.encode(), ttl)
+		}
+		serveCacheEntry(w, r, key, entry)
+	}
+}
+
+// invalidate deletes cached keys matching each pattern (e.g. "states:*").
+// Failures are logged, not returned: a stale entry expires with its TTL.
+func invalidate(ctx context.Context, c *cache.Cache, patterns ...string) {
+	for _, pattern := range patterns {
+		if err := c.Invalidate(ctx, pattern); err != nil {
+			slog.Warn("cache invalidation failed", "pattern", pattern, "error", err)
+		}
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// cacheEntry is a successful response as stored in Redis: a line holding
+// its validators and expiry, then the body.
+type cacheEntry struct {
+	ETag         string
+	LastModified time.Time
+	Expires      time.Time
+	Body         []byte
+}
+
+func newCacheEntry(body []byte, expires time.Time) cacheEntry {
+	return cacheEntry{
+		ETag:         strongETag(body),
+		LastModified: lastUpdated(body),
+		Expires:      expires,
+		Body:         body,
+	}
+}
+
+// strongETag returns a strong ETag for body: a truncated SHA-256.
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func (e cacheEntry) encode() string {
+	var lastModified int64
+	if !e.LastModified.IsZero() {
+		lastModified = e.LastModified.Unix()
+	}
+	return fmt.Sprintf("%s %d %d\n%s", e.ETag, lastModified, e.Expires.Unix(), e.Body)
+}
+
+// decodeCacheEntry parses an encoded cacheEntry. Entries stored before
+// validators were (bare bodies) don't parse, and are treated as misses.
+func decodeCacheEntry(s string) (cacheEntry, bool) {
+	line, body, ok := strings.Cut(s, "\n")
+	fields := strings.Fields(line)
+	if !ok || len(fields) != 3 || !strings.HasPrefix(fields[0], `"`) {
+		return cacheEntry{}, false
+	}
+	lastModified, err1 := strconv.ParseInt(fields[1], 10, 64)
+	expires, err2 := strconv.ParseInt(fields[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return cacheEntry{}, false
+	}
+
+	e := cacheEntry{ETag: fields[0], Expires: time.Unix(expires, 0), Body: []byte(body)}
+	if lastModified > 0 {
+		e.LastModified = time.Unix(lastModified, 0)
+	}
+	return e, true
+}
+
+// serveCacheEntry writes e with its validators, or a 304 when the request's
+// If-None-Match already names it. Last-Modified is informational only: a
+// deletion doesn't move it, so it never decides a 304.
+func serveCacheEntry(w http.ResponseWriter, r *http.Request, key string, e cacheEntry) {
+	h := w.Header()
+	h.Set("ETag", e.ETag)
+	if !e.LastModified.IsZero() {
+		h.Set("Last-Modified", e.LastModified.UTC().Format(http.TimeFormat))
+	}
+	h.Set("Cache-Control", cacheControl(key, time.Until(e.Expires)))
+
+	if etagMatches(r.Header.Get("If-None-Match"), e.ETag) {
+		h.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(e.Body)
+}
+
+// cacheControl returns the Cache-Control for key, never outliving the
+// server's copy by more than its remaining ttl.
+func cacheControl(key string, ttl time.Duration) string {
+	maxAge := defaultMaxAge
+	for _, cc := range cacheControls {
+		if strings.HasPrefix(key, cc.prefix) {
+			maxAge = cc.maxAge
+			break
+		}
+	}
+	maxAge = min(maxAge, ttl)
+	if maxAge <= 0 {
+		return "no-cache"
+	}
+	return fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+}
+
+// etagMatches reports whether an If-None-Match header names etag. The
+// comparison is weak, as RFC 9110 requires: nginx marks ETags weak when it
+// gzips a response.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// lastUpdated returns the newest "updated_at" anywhere in a JSON body, or
+// zero for bodies that aren't JSON or have none.
+func lastUpdated(body []byte) time.Time {
+	var v any
+	if json.Unmarshal(body, &v) != nil {
+		return time.Time{}
+	}
+
+	var latest time.Time
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, field := range v {
+				if s, ok := field.(string); ok && k == "updated_at" {
+					if t, err := time.Parse(time.RFC3339Nano, s); err == nil && t.After(latest) {
+						latest = t
+					}
+					continue
+				}
+				walk(field)
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
 		}
 	}
+	walk(v)
+	return latest
 }
 
+// responseRecorder captures a handler's status and body. Headers go
+// straight to the underlying ResponseWriter.
 type responseRecorder struct {
 	http.ResponseWriter
 	body   *bytes.Buffer
@@ -41,11 +251,9 @@ type responseRecorder struct {
 }
 
 func (rr *responseRecorder) Write(b []byte) (int, error) {
-	rr.body.Write(b)
-	return rr.ResponseWriter.Write(b)
+	return rr.body.Write(b)
 }
 
 func (rr *responseRecorder) WriteHeader(code int) {
 	rr.status = code
-	rr.ResponseWriter.WriteHeader(code)
 }
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// cameraRequest is the camera metadata CreateVideo and UpdateVideo accept.
+// On update an omitted field keeps its current value and an empty one ("" or
+// 0) clears it. camera_model, firmware, owner_contact, install_date and notes
+// are admin-only: public listings never include them.
+type cameraRequest struct {
+	// Timezone is an IANA zone name, e.g. "America/Chicago".
+	Timezone *string `json:"timezone"`
+	// FieldOfView is the horizontal field of view in degrees.
+	FieldOfView  *float64 `json:"field_of_view"`
+	CameraModel  *string  `json:"camera_model"`
+	Firmware     *string  `json:"firmware"`
+	OwnerContact *string  `json:"owner_contact"`
+	// InstallDate is a YYYY-MM-DD date.
+	InstallDate *string `json:"install_date"`
+	Notes       *string `json:"notes"`
+}
+
+// cameraMetadata is a video's stored camera metadata.
+type cameraMetadata struct {
+	Timezone     *string
+	FieldOfView  *float64
+	CameraModel  *string
+	Firmware     *string
+	OwnerContact *string
+	InstallDate  pgtype.Date
+	Notes        *string
+}
+
+// VideoLocalTime handles GET /videos/{id}/localtime — the current time where
+// a listed camera is, from its timezone.
+func VideoLocalTime(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || id <= 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid video id"})
+			return
+		}
+
+		tz, err := db.New(pool).GetListedVideoTimezone(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "video not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if tz == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "video has no timezone"})
+			return
+		}
+		loc, err := time.LoadLocation(*tz)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		now := time.Now().In(loc)
+		abbr, _ := now.Zone()
+		writeJSON(w, http.StatusOK, map[string]any{
+			"video_id":     id,
+			"timezone":     *tz,
+			"local_time":   now.Format(time.RFC3339),
+			"utc_offset":   now.Format("-07:00"),
+			"abbreviation": abbr,
+		})
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// merge applies req over current, or returns an error message.
+func (req cameraRequest) merge(current cameraMetadata) (cameraMetadata, string) {
+	m := current
+	if req.Timezone != nil {
+		m.Timezone = emptyToNil(*req.Timezone)
+		if m.Timezone != nil {
+			if *m.Timezone == "Local" {
+				return m, "unknown timezone Local"
+			}
+			if _, err := time.LoadLocation(*m.Timezone); err != nil {
+				return m, "unknown timezone " + *m.Timezone
+			}
+		}
+	}
+	if req.FieldOfView != nil {
+		m.FieldOfView = req.FieldOfView
+		if *m.FieldOfView == 0 {
+			m.FieldOfView = nil
+		} else if *m.FieldOfView < 0 || *m.FieldOfView > 360 {
+			return m, "field_of_view must be between 0 and 360"
+		}
+	}
+	if req.CameraModel != nil {
+		m.CameraModel = emptyToNil(*req.CameraModel)
+	}
+	if req.Firmware != nil {
+		m.Firmware = emptyToNil(*req.Firmware)
+	}
+	if req.OwnerContact != nil {
+		m.OwnerContact = emptyToNil(*req.OwnerContact)
+	}
+	if req.InstallDate != nil {
+		m.InstallDate = pgtype.Date{}
+		if s := strings.TrimSpace(*req.InstallDate); s != "" {
+			d, err := time.Parse(time.DateOnly, s)
+			if err != nil {
+				return m, "install_date must be a YYYY-MM-DD date"
+			}
+			m.InstallDate = pgtype.Date{Time: d, Valid: true}
+		}
+	}
+	if req.Notes != nil {
+		m.Notes = emptyToNil(*req.Notes)
+	}
+	return m, ""
+}
+
+// videoCameraMetadata returns the camera metadata of row.
+func videoCameraMetadata(row db.GetVideoByIDRow) cameraMetadata {
+	return cameraMetadata{
+		Timezone:     row.Timezone,
+		FieldOfView:  row.FieldOfView,
+		CameraModel:  row.CameraModel,
+		Firmware:     row.Firmware,
+		OwnerContact: row.OwnerContact,
+		InstallDate:  row.InstallDate,
+		Notes:        row.Notes,
+	}
+}
+
+// emptyToNil trims s and returns nil if nothing is left.
+func emptyToNil(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// usCountryCode scopes the /states compatibility routes.
+const usCountryCode = "US"
+
+// Region types. A country's region_type is the default for its new regions.
+const (
+	regionState     = "state"
+	regionProvince  = "province"
+	regionTerritory = "territory"
+	regionDistrict  = "district"
+)
+
+var regionTypes = []string{regionState, regionProvince, regionTerritory, regionDistrict}
+
+// countryCodePattern is an ISO 3166-1 alpha-2 code.
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Country data is keyed under states: so the region and video changes that
+// alter its counts invalidate it too.
+const countriesAllKey = "states:countries"
+
+// ListCountries handles GET /countries — every country with region and video
+// counts (cached).
+func ListCountries(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return cachedHandler(c, countriesAllKey, func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListCountries(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, rows)
+	})
+}
+
+// GetCountry handles GET /countries/{code} (cached).
+func GetCountry(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		code := strings.ToUpper(chi.URLParam(r, "code"))
+		key := "states:country:" + code
+
+		cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
+			row, err := db.New(pool).GetCountry(r.Context(), code)
+			if err != nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "country not found"})
+				return
+			}
+			writeJSON(w, http.StatusOK, row)
+		})(w, r)
+	}
+}
+
+// ListRegions handles GET /countries/{code}/regions — a country's states,
+// provinces and territories with video counts (cached).
+func ListRegions(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		code := strings.ToUpper(chi.URLParam(r, "code"))
+		key := "states:country-regions:" + code
+
+		cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
+			q := db.New(pool)
+			if _, err := q.GetCountry(r.Context(), code); err != nil {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "country not found"})
+				return
+			}
+			rows, err := q.ListStates(r.Context(), code)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, rows)
+		})(w, r)
+	}
+}
+
+type countryRequest struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	RegionType string `json:"region_type"`
+}
+
+// CreateCountry handles POST /countries — adds a country by ISO 3166-1
+// alpha-2 code. region_type defaults to state (admin only).
+func CreateCountry(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req countryRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+		if !countryCodePattern.MatchString(req.Code) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "code must be an ISO 3166-1 alpha-2 code"})
+			return
+		}
+		if req.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return
+		}
+		if req.RegionType == "" {
+			req.RegionType = regionState
+		}
+		if msg := validateRegionType(req.RegionType); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		q := db.New(pool)
+		created, err := q.CreateCountry(r.Context(), db.CreateCountryParams{
+			Code:       req.Code,
+			Name:       req.Name,
+			RegionType: req.RegionType,
+		})
+		if isUniqueViolation(err) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "country already exists"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionCreate, entityCountry, created.Code, nil, created)
+
+		invalidate(r.Context(), c, "states:*")
+		writeJSON(w, http.StatusCreated, created)
+	}
+}
+
+// UpdateCountry handles PUT /countries/{code} — renames a country or changes
+// its default region type. Existing regions keep their type (admin only).
+func UpdateCountry(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		code := strings.ToUpper(chi.URLParam(r, "code"))
+
+		var req countryRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		if req.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return
+		}
+
+		q := db.New(pool)
+		before, err := q.GetCountry(r.Context(), code)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "country not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if req.RegionType == "" {
+			req.RegionType = before.RegionType
+		}
+		if msg := validateRegionType(req.RegionType); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		if _, err := q.UpdateCountry(r.Context(), db.UpdateCountryParams{
+			Code:       code,
+			Name:       req.Name,
+			RegionType: req.RegionType,
+		}); err != nil {
+			if isUniqueViolation(err) {
+				writeJSON(w, http.StatusConflict, map[string]string{"error": "another country has that name"})
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		row, err := q.GetCountry(r.Context(), code)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionUpdate, entityCountry, code, before, row)
+
+		invalidate(r.Context(), c, "states:*")
+		writeJSON(w, http.StatusOK, row)
+	}
+}
+
+type createRegionRequest struct {
+	createStateRequest
+	// RegionType defaults to the country's region_type.
+	RegionType string `json:"region_type"`
+}
+
+// CreateRegion handles POST /countries/{code}/regions — creates a state,
+// province or territory in a country (admin only).
+func CreateRegion(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		code := strings.ToUpper(chi.URLParam(r, "code"))
+
+		var req createRegionRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		if req.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return
+		}
+
+		country, err := db.New(pool).GetCountry(r.Context(), code)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "country not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if req.RegionType == "" {
+			req.RegionType = country.RegionType
+		}
+		if msg := validateRegionType(req.RegionType); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		createRegion(w, r, pool, c, req.createStateRequest, code, req.RegionType)
+	}
+}
+
+// validateRegionType returns an error message if t isn't a region type, or "".
+func validateRegionType(t string) string {
+	if !slices.Contains(regionTypes, t) {
+		return "region_type must be one of " + strings.Join(regionTypes, ", ")
+	}
+	return ""
+}
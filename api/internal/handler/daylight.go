@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/sun"
+)
+
+// daylight is whether it is light where a camera is. Both fields are null
+// for cameras without coordinates. Listings are cached, so they can lag
+// dawn and dusk by up to cache.DefaultTTL.
+type daylight struct {
+	// IsDaylight is true from civil dawn to civil dusk.
+	IsDaylight *bool `json:"is_daylight"`
+	// NextSunrise is in the camera's timezone, or UTC without one. Null
+	// during polar night.
+	NextSunrise *time.Time `json:"next_sunrise"`
+}
+
+// listedVideo is a public video listing row with its daylight.
+type listedVideo struct {
+	db.ListVideosRow
+	daylight
+}
+
+// nearbyVideo is listedVideo for distance-ordered listings.
+type nearbyVideo struct {
+	db.ListVideosNearbyRow
+	daylight
+}
+
+// parseDaylightFilter reads ?daylight=true|false: only cameras where it is
+// (or isn't) currently light. Cameras without coordinates match neither.
+func parseDaylightFilter(q url.Values) (*bool, string) {
+	s := q.Get("daylight")
+	if s == "" {
+		return nil, ""
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, "daylight must be true or false"
+	}
+	return &v, ""
+}
+
+// daylightKey returns the cache key suffix for a daylight filter.
+func daylightKey(only *bool) string {
+	switch {
+	case only == nil:
+		return ""
+	case *only:
+		return ":daylight"
+	default:
+		return ":dark"
+	}
+}
+
+// videoDaylight computes the daylight of a camera at now.
+func videoDaylight(lat, lon *float64, timezone *string, now time.Time) daylight {
+	if lat == nil || lon == nil {
+		return daylight{}
+	}
+	loc := time.UTC
+	if timezone != nil {
+		if l, err := time.LoadLocation(*timezone); err == nil {
+			loc = l
+		}
+	}
+	now = now.In(loc)
+
+	d := daylight{IsDaylight: new(bool)}
+	*d.IsDaylight = sun.IsDaylight(now, *lat, *lon)
+	if rise, ok := sun.NextSunrise(now, *lat, *lon); ok {
+		d.NextSunrise = &rise
+	}
+	return d
+}
+
+// matches reports whether d passes a daylight filter.
+func (d daylight) matches(only *bool) bool {
+	return only == nil || (d.IsDaylight != nil && *d.IsDaylight == *only)
+}
+
+// listedVideos adds daylight to rows, keeping those that match only.
+func listedVideos(rows []db.ListVideosRow, only *bool) []listedVideo {
+	now := time.Now()
+	out := make([]listedVideo, 0, len(rows))
+	for _, row := range rows {
+		d := videoDaylight(row.Latitude, row.Longitude, row.Timezone, now)
+		if d.matches(only) {
+			out = append(out, listedVideo{row, d})
+		}
+	}
+	return out
+}
+
+// nearbyVideos is listedVideos for distance-ordered rows.
+func nearbyVideos(rows []db.ListVideosNearbyRow, only *bool) []nearbyVideo {
+	now := time.Now()
+	out := make([]nearbyVideo, 0, len(rows))
+	for _, row := range rows {
+		d := videoDaylight(row.Latitude, row.Longitude, row.Timezone, now)
+		if d.matches(only) {
+			out = append(out, nearbyVideo{row, d})
+		}
+	}
+	return out
+}
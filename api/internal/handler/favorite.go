@@ -0,0 +1,539 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	maxUserLists     = 50
+	maxListVideos    = 200
+	maxListNameRunes = 100
+)
+
+// userListRequest is the body of CreateUserList and UpdateUserList.
+type userListRequest struct {
+	Name string `json:"name"`
+	// VideoIDs is the list's cameras in order. On update, omitted keeps the
+	// current ones.
+	VideoIDs *[]int32 `json:"video_ids"`
+}
+
+// userListDetail is a list with its listed videos in order.
+type userListDetail struct {
+	db.GetUserListRow
+	Videos []db.ListUserListVideosRow `json:"videos"`
+}
+
+// ListFavorites handles GET /me/favorites — the signed-in user's favorite
+// cameras, most recently added first. Unlisted cameras are left out until
+// they return.
+func ListFavorites(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListFavoriteVideos(r.Context(), middleware.UserID(r.Context()))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, rows)
+	}
+}
+
+// GetFavorite handles GET /me/favorites/{video_id} — 404 unless the user
+// has favorited the video.
+func GetFavorite(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		videoID, ok := readVideoIDParam(w, r)
+		if !ok {
+			return
+		}
+		fav, err := db.New(pool).GetFavorite(r.Context(), db.GetFavoriteParams{
+			UserID:  middleware.UserID(r.Context()),
+			VideoID: videoID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "not a favorite"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, fav)
+	}
+}
+
+// AddFavorite handles PUT /me/favorites/{video_id} — favorites a listed
+// video. Idempotent: a repeat keeps the original time.
+func AddFavorite(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		videoID, ok := readVideoIDParam(w, r)
+		if !ok {
+			return
+		}
+		fav, err := db.New(pool).AddFavorite(r.Context(), db.AddFavoriteParams{
+			UserID:  middleware.UserID(r.Context()),
+			VideoID: videoID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "video not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, fav)
+	}
+}
+
+// RemoveFavorite handles DELETE /me/favorites/{video_id}.
+func RemoveFavorite(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		videoID, ok := readVideoIDParam(w, r)
+		if !ok {
+			return
+		}
+		n, err := db.New(pool).DeleteFavorite(r.Context(), db.DeleteFavoriteParams{
+			UserID:  middleware.UserID(r.Context()),
+			VideoID: videoID,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if n == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "not a favorite"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ListUserLists handles GET /me/lists — the user's named camera lists in
+// their order, with video counts.
+func ListUserLists(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListUserLists(r.Context(), middleware.UserID(r.Context()))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, rows)
+	}
+}
+
+// GetUserList handles GET /me/lists/{id} — a list with its videos in order.
+func GetUserList(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readUserListID(w, r)
+		if !ok {
+			return
+		}
+		detail, err := loadUserList(r, db.New(pool), id)
+		if err != nil {
+			writeUserListError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, detail)
+	}
+}
+
+// CreateUserList handles POST /me/lists — adds a named list, last in the
+// user's order, optionally with its videos.
+func CreateUserList(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req userListRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		if msg := req.validate(); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+		userID := middleware.UserID(r.Context())
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
+
+		count, err := qtx.CountUserLists(r.Context(), userID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if count >= maxUserLists {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": fmt.Sprintf("at most %d lists per user", maxUserLists)})
+			return
+		}
+
+		id, err := qtx.CreateUserList(r.Context(), db.CreateUserListParams{UserID: userID, Name: req.Name})
+		if isUniqueViolation(err) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "you already have a list named " + req.Name})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if req.VideoIDs != nil {
+			if msg, err := setUserListVideos(r, qtx, id, *req.VideoIDs); msg != "" || err != nil {
+				writeSetVideosError(w, msg, err)
+				return
+			}
+		}
+
+		detail, err := loadUserList(r, qtx, id)
+		if err != nil {
+			writeUserListError(w, err)
+			return
+		}
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusCreated, detail)
+	}
+}
+
+// UpdateUserList handles PUT /me/lists/{id} — renames a list and, with
+// video_ids, replaces its videos in the order given.
+func UpdateUserList(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readUserListID(w, r)
+		if !ok {
+			return
+		}
+		var req userListRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		if msg := req.validate(); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
+
+		// Ownership check: another user's list is as good as missing.
+		if _, err := qtx.GetUserList(r.Context(), db.GetUserListParams{
+			ListID: id,
+			UserID: middleware.UserID(r.Context()),
+		}); err != nil {
+			writeUserListError(w, err)
+			return
+		}
+
+		err = qtx.RenameUserList(r.Context(), db.RenameUserListParams{ListID: id, Name: req.Name})
+		if isUniqueViolation(err) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "you already have a list named " + req.Name})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if req.VideoIDs != nil {
+			if err := qtx.ClearUserListVideos(r.Context(), id); err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			if msg, err := setUserListVideos(r, qtx, id, *req.VideoIDs); msg != "" || err != nil {
+				writeSetVideosError(w, msg, err)
+				return
+			}
+		}
+
+		detail, err := loadUserList(r, qtx, id)
+		if err != nil {
+			writeUserListError(w, err)
+			return
+		}
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, detail)
+	}
+}
+
+// DeleteUserList handles DELETE /me/lists/{id}.
+func DeleteUserList(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readUserListID(w, r)
+		if !ok {
+			return
+		}
+		n, err := db.New(pool).DeleteUserList(r.Context(), db.DeleteUserListParams{
+			ListID: id,
+			UserID: middleware.UserID(r.Context()),
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if n == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "list not found"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ReorderUserLists handles PUT /me/lists/order — sets the order of the
+// user's lists from a list of their IDs. Lists left out keep their position.
+func ReorderUserLists(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req reorderRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		if len(req.IDs) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ids is required"})
+			return
+		}
+		if msg := duplicateID(req.IDs, "list"); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+
+		n, err := db.New(pool).WithTx(tx).ReorderUserLists(r.Context(), db.ReorderUserListsParams{
+			Ids:    req.IDs,
+			UserID: middleware.UserID(r.Context()),
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if n != int64(len(req.IDs)) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("%d of the ids are not your lists", int64(len(req.IDs))-n)})
+			return
+		}
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// markFavorites is middleware that adds "is_favorite" to every video in a
+// signed-in user's JSON responses. It works on the finished body, so the
+// shared Redis entry underneath stays the same for everyone; the marked
+// response gets its own ETag and is private to the user's browser. Both
+// vary on Authorization, so no cache serves the anonymous copy to a user.
+func markFavorites(pool *pgxpool.Pool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Authorization")
+			userID := middleware.UserID(r.Context())
+			if userID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ids, err := db.New(pool).ListFavoriteVideoIDs(r.Context(), userID)
+			if err != nil {
+				slog.Warn("favorites lookup failed", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			favorites := make(map[string]bool, len(ids))
+			for _, id := range ids {
+				favorites[strconv.Itoa(int(id))] = true
+			}
+
+			// The shared response's ETag is not this user's: revalidate
+			// against the marked body instead.
+			ifNoneMatch := r.Header.Get("If-None-Match")
+			r = r.Clone(r.Context())
+			r.Header.Del("If-None-Match")
+
+			rec := &responseRecorder{ResponseWriter: w, body: &bytes.Buffer{}}
+			next.ServeHTTP(rec, r)
+			body := rec.body.Bytes()
+			if rec.status != http.StatusOK && rec.status != 0 {
+				w.WriteHeader(rec.status)
+				_, _ = w.Write(body)
+				return
+			}
+			if marked, err := markFavoriteVideos(body, favorites); err == nil {
+				body = marked
+			}
+
+			h := w.Header()
+			etag := strongETag(body)
+			h.Set("ETag", etag)
+			h.Set("Cache-Control", "private, no-cache")
+			if etagMatches(ifNoneMatch, etag) {
+				h.Del("Content-Type")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(body)
+		})
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// validate trims and checks req, returning an error message.
+func (req *userListRequest) validate() string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return "name is required"
+	}
+	if len([]rune(req.Name)) > maxListNameRunes {
+		return fmt.Sprintf("name must be at most %d characters", maxListNameRunes)
+	}
+	if req.VideoIDs != nil {
+		if len(*req.VideoIDs) > maxListVideos {
+			return fmt.Sprintf("at most %d videos per list", maxListVideos)
+		}
+		return duplicateID(*req.VideoIDs, "video")
+	}
+	return ""
+}
+
+// setUserListVideos adds ids to an empty list in order. It returns an
+// error message if any isn't a listed video.
+func setUserListVideos(r *http.Request, q *db.Queries, listID int32, ids []int32) (string, error) {
+	if len(ids) == 0 {
+		return "", nil
+	}
+	n, err := q.AddUserListVideos(r.Context(), db.AddUserListVideosParams{ListID: listID, Ids: ids})
+	if err != nil {
+		return "", err
+	}
+	if n != int64(len(ids)) {
+		return fmt.Sprintf("%d of the video_ids are not listed videos", int64(len(ids))-n), nil
+	}
+	return "", nil
+}
+
+func writeSetVideosError(w http.ResponseWriter, msg string, err error) {
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+}
+
+// loadUserList reads one of the requesting user's lists with its videos.
+func loadUserList(r *http.Request, q *db.Queries, id int32) (userListDetail, error) {
+	list, err := q.GetUserList(r.Context(), db.GetUserListParams{
+		ListID: id,
+		UserID: middleware.UserID(r.Context()),
+	})
+	if err != nil {
+		return userListDetail{}, err
+	}
+	videos, err := q.ListUserListVideos(r.Context(), id)
+	if err != nil {
+		return userListDetail{}, err
+	}
+	return userListDetail{GetUserListRow: list, Videos: videos}, nil
+}
+
+func writeUserListError(w http.ResponseWriter, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "list not found"})
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
+
+// duplicateID returns an error message naming the first ID listed twice.
+func duplicateID(ids []int32, kind string) string {
+	seen := make(map[int32]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return fmt.Sprintf("%s %d is listed twice", kind, id)
+		}
+		seen[id] = true
+	}
+	return ""
+}
+
+func readVideoIDParam(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "video_id"))
+	if err != nil || id <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid video id"})
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func readUserListID(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid list id"})
+		return 0, false
+	}
+	return int32(id), true
+}
+
+// markFavoriteVideos sets "is_favorite" on every object in a JSON body
+// with a "video_id", from favorites keyed by ID.
+func markFavoriteVideos(body []byte, favorites map[string]bool) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if id, ok := v["video_id"].(json.Number); ok {
+				v["is_favorite"] = favorites[id.String()]
+			}
+			for _, field := range v {
+				walk(field)
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(v)
+
+	var buf bytes.Buffer
+	err := json.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
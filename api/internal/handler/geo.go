@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// earthRadiusKm matches the radius used by the haversine queries in videos.sql.
+	earthRadiusKm = 6371.0
+
+	defaultNearbyRadiusKm = 50.0
+	maxNearbyRadiusKm     = 500.0
+)
+
+// NearbyVideos handles GET /videos/nearby?lat=&lon=&radius_km= — listed videos
+// within radius_km of a point, nearest first, optionally filtered by
+// daylight (cached).
+func NearbyVideos(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		lat, err := parseFinite(q.Get("lat"))
+		if err != nil || lat < -90 || lat > 90 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid lat"})
+			return
+		}
+		lon, err := parseFinite(q.Get("lon"))
+		if err != nil || lon < -180 || lon > 180 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid lon"})
+			return
+		}
+		radius := defaultNearbyRadiusKm
+		if rs := q.Get("radius_km"); rs != "" {
+			radius, err = parseFinite(rs)
+			if err != nil || radius <= 0 || radius > maxNearbyRadiusKm {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "radius_km must be between 0 and 500"})
+				return
+			}
+		}
+
+		only, msg := parseDaylightFilter(q)
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		// Round the point to ~11 m so nearby requests share cache entries.
+		lat, lon = roundCoord(lat), roundCoord(lon)
+		key := fmt.Sprintf("videos:nearby:%s:%s:%s", formatCoord(lat), formatCoord(lon), formatCoord(radius)) + daylightKey(only)
+
+		cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
+			// One degree of latitude is ~111 km everywhere, so this band
+			// is a cheap superset of the search circle.
+			dLat := radius / (earthRadiusKm * math.Pi / 180)
+			rows, err := db.New(pool).ListVideosNearby(r.Context(), db.ListVideosNearbyParams{
+				Lat:      lat,
+				Lon:      lon,
+				MinLat:   lat - dLat,
+				MaxLat:   lat + dLat,
+				RadiusKm: radius,
+			})
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, nearbyVideos(rows, only))
+		})(w, r)
+	}
+}
+
+// listVideosInBBox serves GET /videos?bbox=minLon,minLat,maxLon,maxLat — listed
+// videos inside the box, ordered by distance from its centre (cached).
+func listVideosInBBox(pool *pgxpool.Pool, c *cache.Cache, w http.ResponseWriter, r *http.Request, bbox string, only *bool) {
+	minLon, minLat, maxLon, maxLat, err := parseBBox(bbox)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	key := fmt.Sprintf("videos:bbox:%s,%s,%s,%s",
+		formatCoord(minLon), formatCoord(minLat), formatCoord(maxLon), formatCoord(maxLat)) + daylightKey(only)
+
+	cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListVideosInBBox(r.Context(), db.ListVideosInBBoxParams{
+			CenterLat: (minLat + maxLat) / 2,
+			CenterLon: (minLon + maxLon) / 2,
+			MinLat:    minLat,
+			MaxLat:    maxLat,
+			MinLon:    minLon,
+			MaxLon:    maxLon,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		out := make([]db.ListVideosNearbyRow, len(rows))
+		for i, row := range rows {
+			out[i] = db.ListVideosNearbyRow(row)
+		}
+		writeJSON(w, http.StatusOK, nearbyVideos(out, only))
+	})(w, r)
+}
+
+// parseBBox parses "minLon,minLat,maxLon,maxLat" (GeoJSON order), rounding
+// each value so equivalent boxes map to the same cache key.
+func parseBBox(s string) (minLon, minLat, maxLon, maxLat float64, err error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return 0, 0, 0, 0, fmt.Errorf("bbox must be minLon,minLat,maxLon,maxLat")
+	}
+
+	var v [4]float64
+	for i, p := range parts {
+		v[i], err = parseFinite(strings.TrimSpace(p))
+		if err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("invalid bbox value %q", p)
+		}
+		v[i] = roundCoord(v[i])
+	}
+	minLon, minLat, maxLon, maxLat = v[0], v[1], v[2], v[3]
+
+	if minLon < -180 || maxLon > 180 || minLat < -90 || maxLat > 90 {
+		return 0, 0, 0, 0, fmt.Errorf("bbox out of range")
+	}
+	if minLon > maxLon || minLat > maxLat {
+		return 0, 0, 0, 0, fmt.Errorf("bbox min values must not exceed max values")
+	}
+	return minLon, minLat, maxLon, maxLat, nil
+}
+
+// validateGeo checks optional camera coordinates from a create/update request.
+// Returns an error message, or "" if valid.
+func validateGeo(lat, lon, heading *float64) string {
+	if (lat == nil) != (lon == nil) {
+		return "latitude and longitude must be set together"
+	}
+	if lat != nil && (*lat < -90 || *lat > 90) {
+		return "latitude must be between -90 and 90"
+	}
+	if lon != nil && (*lon < -180 || *lon > 180) {
+		return "longitude must be between -180 and 180"
+	}
+	if heading != nil && (*heading < 0 || *heading >= 360) {
+		return "heading must be between 0 and 360"
+	}
+	return ""
+}
+
+// parseFinite parses s as a float64, rejecting NaN and infinities, which
+// ParseFloat accepts and which slip through range checks.
+func parseFinite(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%q is not a finite number", s)
+	}
+	return v, nil
+}
+
+func roundCoord(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
+
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	geoJSONContentType = "application/geo+json"
+	kmlContentType     = "application/vnd.google-earth.kml+xml"
+)
+
+// ── GeoJSON types ─────────────────────────────────────────────────────
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string            `json:"type"`
+	ID         int32             `json:"id"`
+	Geometry   pointGeometry     `json:"geometry"`
+	Properties featureProperties `json:"properties"`
+}
+
+type pointGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+type featureProperties struct {
+	Title           string   `json:"title"`
+	StateName       string   `json:"state_name"`
+	SublocationName string   `json:"sublocation_name"`
+	Src             string   `json:"src"`
+	Status          string   `json:"status"`
+	Heading         *float64 `json:"heading,omitempty"`
+}
+
+// ── KML types ─────────────────────────────────────────────────────────
+
+type kmlRoot struct {
+	XMLName  xml.Name    `xml:"kml"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	ID           string    `xml:"id,attr"`
+	Name         string    `xml:"name"`
+	Description  string    `xml:"description,omitempty"`
+	ExtendedData []kmlData `xml:"ExtendedData>Data"`
+	Point        kmlPoint  `xml:"Point"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+// ── Handlers ──────────────────────────────────────────────────────────
+
+// VideosGeoJSON handles GET /videos.geojson — listed videos with coordinates as
+// a GeoJSON FeatureCollection. Accepts the same state_id/sublocation_id filters
+// as ListVideos (cached).
+func VideosGeoJSON(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, msg := parseVideoFilter(r.URL.Query())
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		cachedHandlerWithType(c, "videos:geojson:"+filter.key(), geoJSONContentType, func(w http.ResponseWriter, r *http.Request) {
+			rows, err := filter.list(r.Context(), db.New(pool))
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+
+			fc := featureCollection{Type: "FeatureCollection", Features: []feature{}}
+			for _, v := range rows {
+				if v.Latitude == nil || v.Longitude == nil {
+					continue
+				}
+				fc.Features = append(fc.Features, feature{
+					Type:     "Feature",
+					ID:       v.VideoID,
+					Geometry: pointGeometry{Type: "Point", Coordinates: positionOf(v)},
+					Properties: featureProperties{
+						Title:           v.Title,
+						StateName:       v.StateName,
+						SublocationName: v.SublocationName,
+						Src:             v.Src,
+						Status:          v.Status,
+						Heading:         v.Heading,
+					},
+				})
+			}
+
+			w.Header().Set("Content-Type", geoJSONContentType)
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(fc)
+		})(w, r)
+	}
+}
+
+// VideosKML handles GET /videos.kml — the same catalog as VideosGeoJSON as a
+// KML document for Google Earth (cached).
+func VideosKML(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, msg := parseVideoFilter(r.URL.Query())
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		cachedHandlerWithType(c, "videos:kml:"+filter.key(), kmlContentType, func(w http.ResponseWriter, r *http.Request) {
+			rows, err := filter.list(r.Context(), db.New(pool))
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+
+			doc := kmlRoot{
+				Xmlns:    "http://www.opengis.net/kml/2.2",
+				Document: kmlDocument{Name: "NationCam cameras"},
+			}
+			for _, v := range rows {
+				if v.Latitude == nil || v.Longitude == nil {
+					continue
+				}
+				doc.Document.Placemarks = append(doc.Document.Placemarks, kmlPlacemark{
+					ID:          "video-" + strconv.Itoa(int(v.VideoID)),
+					Name:        v.Title,
+					Description: v.StateName,
+					ExtendedData: []kmlData{
+						{Name: "title", Value: v.Title},
+						{Name: "state_name", Value: v.StateName},
+						{Name: "sublocation_name", Value: v.SublocationName},
+						{Name: "src", Value: v.Src},
+						{Name: "status", Value: v.Status},
+					},
+					Point: kmlPoint{Coordinates: kmlCoordinates(positionOf(v))},
+				})
+			}
+
+			w.Header().Set("Content-Type", kmlContentType)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(xml.Header))
+			_ = xml.NewEncoder(w).Encode(doc)
+		})(w, r)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// positionOf returns a GeoJSON position: [lon, lat] or [lon, lat, elevation].
+func positionOf(v db.ListVideosRow) []float64 {
+	pos := []float64{*v.Longitude, *v.Latitude}
+	if v.Elevation != nil {
+		pos = append(pos, *v.Elevation)
+	}
+	return pos
+}
+
+// kmlCoordinates formats a position as KML "lon,lat[,alt]".
+func kmlCoordinates(pos []float64) string {
+	parts := make([]string, len(pos))
+	for i, p := range pos {
+		parts[i] = formatCoord(p)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,397 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/middleware"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	maxImportRows  = 1000
+	maxImportBytes = 10 << 20
+)
+
+// importRow is one camera in an import file. State and Sublocation are
+// matched by slug or, failing that, case-insensitive name.
+type importRow struct {
+	Title       string   `json:"title"`
+	Src         string   `json:"src"`
+	Type        string   `json:"type"`
+	Status      string   `json:"status"`
+	State       string   `json:"state"`
+	Sublocation string   `json:"sublocation"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+	Heading     *float64 `json:"heading"`
+	Elevation   *float64 `json:"elevation"`
+	Tags        []string `json:"tags"`
+}
+
+type importRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type importResult struct {
+	DryRun              bool             `json:"dry_run"`
+	Rows                int              `json:"rows"`
+	Imported            int              `json:"imported"`
+	VideoIDs            []int32          `json:"video_ids,omitempty"`
+	CreatedStates       []string         `json:"created_states"`
+	CreatedSublocations []string         `json:"created_sublocations"`
+	Errors              []importRowError `json:"errors"`
+}
+
+// ImportVideos handles POST /import/videos — creates many videos at once from
+// a JSON array or, with Content-Type text/csv, a CSV file with a header row.
+// All rows are imported in one transaction: if any row fails nothing is
+// committed and every row's error is reported. ?create_missing=true creates
+// unknown states and sublocations; ?dry_run=true validates without
+// committing (admin only).
+func ImportVideos(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		dryRun := r.URL.Query().Get("dry_run") == "true"
+		createMissing := r.URL.Query().Get("create_missing") == "true"
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+		rows, rowErrs, err := parseImport(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if len(rows) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "no rows to import"})
+			return
+		}
+		if len(rows) > maxImportRows {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("at most %d rows per import", maxImportRows)})
+			return
+		}
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+
+		imp := &importer{
+			tx:            tx,
+			q:             db.New(pool),
+			createMissing: createMissing,
+			createdBy:     middleware.UserID(r.Context()),
+			result: importResult{
+				DryRun:              dryRun,
+				Rows:                len(rows),
+				CreatedStates:       []string{},
+				CreatedSublocations: []string{},
+				Errors:              []importRowError{},
+			},
+		}
+		for i, row := range rows {
+			n := i + 1
+			if msg, bad := rowErrs[n]; bad {
+				imp.fail(n, msg)
+				continue
+			}
+			if err := imp.importRow(r.Context(), row); err != nil {
+				imp.fail(n, err.Error())
+			}
+		}
+
+		res := imp.result
+		if dryRun {
+			res.VideoIDs = nil
+			writeJSON(w, http.StatusOK, res)
+			return
+		}
+		if len(res.Errors) > 0 {
+			res.Imported = 0
+			res.VideoIDs = nil
+			res.CreatedStates = []string{}
+			res.CreatedSublocations = []string{}
+			writeJSON(w, http.StatusUnprocessableEntity, res)
+			return
+		}
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		invalidate(r.Context(), c, "states:*", "sublocations:*", "videos:*", "tags:*", "search:*")
+		writeJSON(w, http.StatusCreated, res)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// importer runs each row in its own savepoint so a failing row doesn't abort
+// the transaction and later rows can still be validated.
+type importer struct {
+	tx            pgx.Tx
+	q             *db.Queries
+	createMissing bool
+	createdBy     string
+	result        importResult
+}
+
+func (imp *importer) fail(row int, msg string) {
+	imp.result.Errors = append(imp.result.Errors, importRowError{Row: row, Error: msg})
+}
+
+func (imp *importer) importRow(ctx context.Context, row importRow) error {
+	row.Title = strings.TrimSpace(row.Title)
+	row.Src = strings.TrimSpace(row.Src)
+	row.State = strings.TrimSpace(row.State)
+	row.Sublocation = strings.TrimSpace(row.Sublocation)
+	if row.Title == "" || row.Src == "" || row.State == "" {
+		return errors.New("title, src, and state are required")
+	}
+	if msg := validateGeo(row.Latitude, row.Longitude, row.Heading); msg != "" {
+		return errors.New(msg)
+	}
+	if row.Type == "" {
+		row.Type = "application/x-mpegURL"
+	}
+	row.Status = normalizeVideoStatus(row.Status)
+	if row.Status == "" {
+		row.Status = statusPublished
+	}
+	if msg := validateInitialStatus(row.Status); msg != "" {
+		return errors.New(msg)
+	}
+
+	sp, err := imp.tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer sp.Rollback(ctx)
+	q := imp.q.WithTx(sp)
+
+	// Record what this row created only once its savepoint is released, so a
+	// failed row doesn't report states it rolled back.
+	var newStates, newSublocations []string
+
+	stateID, created, err := imp.resolveState(ctx, sp, row.State)
+	if err != nil {
+		return err
+	}
+	if created != "" {
+		newStates = append(newStates, created)
+	}
+
+	var sublocationID *int32
+	if row.Sublocation != "" {
+		id, created, err := imp.resolveSublocation(ctx, sp, stateID, row.Sublocation)
+		if err != nil {
+			return err
+		}
+		if created != "" {
+			newSublocations = append(newSublocations, created)
+		}
+		sublocationID = &id
+	}
+
+	video, err := q.CreateVideo(ctx, db.CreateVideoParams{
+		Title:         row.Title,
+		Src:           row.Src,
+		Type:          row.Type,
+		StateID:       stateID,
+		SublocationID: sublocationID,
+		Status:        row.Status,
+		CreatedBy:     imp.createdBy,
+		Latitude:      row.Latitude,
+		Longitude:     row.Longitude,
+		Heading:       row.Heading,
+		Elevation:     row.Elevation,
+	})
+	if err != nil {
+		return err
+	}
+	if len(row.Tags) > 0 {
+		if err := setVideoTags(ctx, q, video.VideoID, row.Tags); err != nil {
+			if errors.Is(err, errUnknownTag) {
+				return errors.New("unknown tag in " + strings.Join(row.Tags, ", "))
+			}
+			return err
+		}
+	}
+	recordAuditTx(ctx, sp, actionCreate, entityVideo, video.VideoID, nil, video)
+
+	if err := sp.Commit(ctx); err != nil {
+		return err
+	}
+
+	imp.result.Imported++
+	imp.result.VideoIDs = append(imp.result.VideoIDs, video.VideoID)
+	imp.result.CreatedStates = append(imp.result.CreatedStates, newStates...)
+	imp.result.CreatedSublocations = append(imp.result.CreatedSublocations, newSublocations...)
+	return nil
+}
+
+// resolveState finds the state for ref, creating it if allowed. created is
+// the new state's slug, or "" if it already existed.
+func (imp *importer) resolveState(ctx context.Context, tx pgx.Tx, ref string) (id int32, created string, err error) {
+	q := imp.q.WithTx(tx)
+	found, err := q.GetStateByRef(ctx, ref)
+	if err == nil {
+		return found.StateID, "", nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", err
+	}
+	if !imp.createMissing {
+		return 0, "", fmt.Errorf("state %q not found (use create_missing=true to create it)", ref)
+	}
+
+	slug, err := uniqueStateSlug(ctx, q, ref, 0)
+	if err != nil {
+		return 0, "", err
+	}
+	row, err := q.CreateState(ctx, db.CreateStateParams{
+		Name:        ref,
+		Slug:        slug,
+		CountryCode: usCountryCode,
+		RegionType:  regionState,
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	recordAuditTx(ctx, tx, actionCreate, entityState, row.StateID, nil, row)
+	return row.StateID, row.Slug, nil
+}
+
+// resolveSublocation is resolveState for a sublocation within stateID.
+func (imp *importer) resolveSublocation(ctx context.Context, tx pgx.Tx, stateID int32, ref string) (id int32, created string, err error) {
+	q := imp.q.WithTx(tx)
+	found, err := q.GetSublocationByRef(ctx, db.GetSublocationByRefParams{StateID: stateID, Ref: ref})
+	if err == nil {
+		return found.SublocationID, "", nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", err
+	}
+	if !imp.createMissing {
+		return 0, "", fmt.Errorf("sublocation %q not found (use create_missing=true to create it)", ref)
+	}
+
+	slug, err := uniqueSublocationSlug(ctx, q, stateID, ref, 0)
+	if err != nil {
+		return 0, "", err
+	}
+	row, err := q.CreateSublocation(ctx, db.CreateSublocationParams{Name: ref, StateID: stateID, Slug: slug})
+	if err != nil {
+		return 0, "", err
+	}
+	recordAuditTx(ctx, tx, actionCreate, entitySublocation, row.SublocationID, nil, row)
+	return row.SublocationID, row.Slug, nil
+}
+
+// parseImport decodes the request body as CSV or JSON. Values that fail to
+// parse in an otherwise well-formed CSV are returned per row (1-based) so
+// they're reported alongside validation errors.
+func parseImport(r *http.Request) ([]importRow, map[int]string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		return parseImportCSV(r.Body)
+	}
+
+	var rows []importRow
+	if err := readJSON(r, &rows); err != nil {
+		return nil, nil, errors.New("invalid JSON: expected an array of videos")
+	}
+	return rows, nil, nil
+}
+
+// importColumns are the CSV header names. tags holds ";"-separated slugs.
+var importColumns = []string{
+	"title", "src", "type", "status", "state", "sublocation",
+	"latitude", "longitude", "heading", "elevation", "tags",
+}
+
+func parseImportCSV(body io.Reader) ([]importRow, map[int]string, error) {
+	cr := csv.NewReader(body)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	col := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if !slices.Contains(importColumns, h) {
+			return nil, nil, fmt.Errorf("unknown CSV column %q", h)
+		}
+		col[h] = i
+	}
+	for _, required := range []string{"title", "src", "state"} {
+		if _, ok := col[required]; !ok {
+			return nil, nil, fmt.Errorf("CSV is missing the %q column", required)
+		}
+	}
+
+	var rows []importRow
+	rowErrs := map[int]string{}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		get := func(name string) string {
+			if i, ok := col[name]; ok {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		row := importRow{
+			Title:       get("title"),
+			Src:         get("src"),
+			Type:        get("type"),
+			Status:      get("status"),
+			State:       get("state"),
+			Sublocation: get("sublocation"),
+		}
+		if tags := get("tags"); tags != "" {
+			row.Tags = strings.Split(tags, ";")
+		}
+		for name, dst := range map[string]**float64{
+			"latitude":  &row.Latitude,
+			"longitude": &row.Longitude,
+			"heading":   &row.Heading,
+			"elevation": &row.Elevation,
+		} {
+			s := get(name)
+			if s == "" {
+				continue
+			}
+			v, err := parseFinite(s)
+			if err != nil {
+				rowErrs[len(rows)+1] = name + " must be a number"
+				continue
+			}
+			*dst = &v
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrs, nil
+}
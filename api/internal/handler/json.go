@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
+	"slices"
 	"strconv"
 )
 
@@ -17,35 +19,174 @@ func readJSON(r *http.Request, v any) error {
 	return json.NewDecoder(r.Body).Decode(v)
 }
 
-// paginatedResponse wraps data with pagination metadata.
+// nullable is an optional request field that tells "left out" from null:
+// left out keeps the current value, null clears it.
+type nullable[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (n *nullable[T]) UnmarshalJSON(b []byte) error {
+	n.Set = true
+	if string(b) == "null" {
+		n.Value = nil
+		return nil
+	}
+	n.Value = new(T)
+	return json.Unmarshal(b, n.Value)
+}
+
+// or returns the field's value if it was sent, current otherwise.
+func (n nullable[T]) or(current *T) *T {
+	if !n.Set {
+		return current
+	}
+	return n.Value
+}
+
+// paginatedResponse wraps data with pagination metadata. Total is set for
+// numbered pages and with ?total=true; Page is omitted when paging by
+// cursor. A missing NextCursor or PrevCursor means that end of the list.
 type paginatedResponse struct {
-	Data    any   `json:"data"`
-	Total   int32 `json:"total"`
-	Page    int32 `json:"page"`
-	PerPage int32 `json:"per_page"`
+	Data       any    `json:"data"`
+	Total      *int32 `json:"total,omitempty"`
+	Page       int32  `json:"page,omitempty"`
+	PerPage    int32  `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
-// parsePagination extracts page and per_page from query params.
-// Defaults: page=1, per_page=100. Max per_page=500.
-func parsePagination(r *http.Request) (limit, offset, page, perPage int32) {
-	page = 1
-	perPage = 100
+// pagination is a parsed page request: a numbered page, or the page after
+// (or before) a cursor.
+type pagination struct {
+	Page      int32
+	PerPage   int32
+	Cursor    *pageCursor
+	WithTotal bool
+}
+
+// pageCursor is the sort key of the row a page starts after, or when
+// Backward ends before. Admin lists key by the Values of their Sort (see
+// listquery); the audit log by ID alone. Clients see it as an opaque token.
+type pageCursor struct {
+	Backward bool   `json:"b,omitempty"`
+	Sort     string `json:"s,omitempty"`
+	Values   []any  `json:"v,omitempty"`
+	ID       int64  `json:"i,omitempty"`
+}
 
-	if p := r.URL.Query().Get("page"); p != "" {
-		if v, err := strconv.Atoi(p); err == nil && v > 0 {
-			page = int32(v)
+// parsePagination extracts page, per_page, cursor and total from query
+// params. Defaults: page=1, per_page=100. Max per_page=500. A cursor
+// overrides page. total defaults to true for numbered pages only. Returns an
+// error message for an invalid cursor or total.
+func parsePagination(r *http.Request) (pagination, string) {
+	q := r.URL.Query()
+	p := pagination{Page: 1, PerPage: 100}
+
+	if s := q.Get("page"); s != "" {
+		if v, err := strconv.Atoi(s); err == nil && v > 0 {
+			p.Page = int32(v)
+		}
+	}
+	if s := q.Get("per_page"); s != "" {
+		if v, err := strconv.Atoi(s); err == nil && v > 0 {
+			p.PerPage = int32(min(v, 500))
+		}
+	}
+	if s := q.Get("cursor"); s != "" {
+		c, err := decodeCursor(s)
+		if err != nil {
+			return p, "invalid cursor"
 		}
+		p.Cursor = &c
 	}
-	if pp := r.URL.Query().Get("per_page"); pp != "" {
-		if v, err := strconv.Atoi(pp); err == nil && v > 0 {
-			perPage = int32(v)
-			if perPage > 500 {
-				perPage = 500
-			}
+
+	p.WithTotal = p.Cursor == nil
+	if s := q.Get("total"); s != "" {
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return p, "total must be true or false"
 		}
+		p.WithTotal = v
 	}
+	return p, ""
+}
 
-	limit = perPage
-	offset = (page - 1) * perPage
-	return
+// limit is the query LIMIT: one row more than a page shows whether the list
+// goes on.
+func (p pagination) limit() int32 {
+	return p.PerPage + 1
+}
+
+// offset is the query OFFSET: 0 with a cursor.
+func (p pagination) offset() int32 {
+	if p.Cursor != nil {
+		return 0
+	}
+	return (p.Page - 1) * p.PerPage
+}
+
+// backward reports whether p pages back from its cursor.
+func (p pagination) backward() bool {
+	return p.Cursor != nil && p.Cursor.Backward
+}
+
+// cursorID returns the cursor's row ID for the query, or nil without one.
+func cursorID[T int32 | int64](p pagination) *T {
+	if p.Cursor == nil {
+		return nil
+	}
+	id := T(p.Cursor.ID)
+	return &id
+}
+
+// paginate builds the response for rows fetched with p.limit(), using key
+// to make cursors from the first and last rows.
+func paginate[T any](p pagination, rows []T, key func(T) pageCursor) paginatedResponse {
+	backward := p.backward()
+	more := len(rows) > int(p.PerPage)
+	if more {
+		rows = rows[:p.PerPage]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	resp := paginatedResponse{Data: rows, PerPage: p.PerPage}
+	if p.Cursor == nil {
+		resp.Page = p.Page
+	}
+	if len(rows) == 0 {
+		return resp
+	}
+
+	// Paging backward, the rows after this page are where the client came
+	// from; paging forward, so are the rows before it.
+	hasNext := more || backward
+	hasPrev := (more && backward) || (!backward && (p.Cursor != nil || p.Page > 1))
+	if hasNext {
+		last := key(rows[len(rows)-1])
+		resp.NextCursor = last.encode()
+	}
+	if hasPrev {
+		first := key(rows[0])
+		first.Backward = true
+		resp.PrevCursor = first.encode()
+	}
+	return resp
+}
+
+func (c pageCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	var c pageCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(b, &c)
+	return c, err
 }
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Video lifecycle statuses. Listings show published, maintenance and offline
+// videos (the latter two with a badge); draft and archived are admin-only.
+const (
+	statusDraft       = "draft"
+	statusPublished   = "published"
+	statusMaintenance = "maintenance"
+	statusOffline     = "offline"
+	statusArchived    = "archived"
+)
+
+// videoTransitions lists the statuses an admin may move a video to from each
+// status. offline is never a target: only the stream health check sets it.
+var videoTransitions = map[string][]string{
+	statusDraft:       {statusPublished, statusArchived},
+	statusPublished:   {statusDraft, statusMaintenance, statusArchived},
+	statusMaintenance: {statusPublished, statusArchived},
+	statusOffline:     {statusPublished, statusMaintenance, statusArchived},
+	statusArchived:    {statusDraft, statusPublished},
+}
+
+// initialVideoStatuses are the statuses a video may be created with.
+var initialVideoStatuses = []string{statusDraft, statusPublished, statusMaintenance}
+
+// normalizeVideoStatus lowercases s and maps the pre-lifecycle values
+// "active" and "inactive" onto published and archived.
+func normalizeVideoStatus(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "active":
+		return statusPublished
+	case "inactive":
+		return statusArchived
+	}
+	return s
+}
+
+// defaultVideoStatus is the status for a new video that didn't specify one:
+// draft when it is scheduled to publish later, published otherwise.
+func defaultVideoStatus(publishAt *time.Time) string {
+	if publishAt != nil && publishAt.After(time.Now()) {
+		return statusDraft
+	}
+	return statusPublished
+}
+
+// validateInitialStatus returns an error message if a video can't be created
+// with status, or "" if it can.
+func validateInitialStatus(status string) string {
+	if !slices.Contains(initialVideoStatuses, status) {
+		return "status must be one of " + strings.Join(initialVideoStatuses, ", ")
+	}
+	return ""
+}
+
+// validateVideoTransition returns an error message if an admin may not move
+// a video from one status to another, or "" if they may. Keeping the same
+// status is always allowed.
+func validateVideoTransition(from, to string) string {
+	if from == to {
+		return ""
+	}
+	if _, known := videoTransitions[to]; !known {
+		return "unknown status " + to
+	}
+	if !slices.Contains(videoTransitions[from], to) {
+		return "cannot change status from " + from + " to " + to
+	}
+	return ""
+}
+
+// validateSchedule returns an error message if unpublishAt isn't after publishAt.
+func validateSchedule(publishAt, unpublishAt *time.Time) string {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return "unpublish_at must be after publish_at"
+	}
+	return ""
+}
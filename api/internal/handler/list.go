@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/listquery"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The admin lists' filters and sort fields. Each takes created_since,
+// created_until, updated_since and updated_until (RFC 3339 or YYYY-MM-DD;
+// until is exclusive) and sorts by position (the manual sort order, unset
+// last), created_at and updated_at besides the fields listed.
+
+// videoListSpec serves ListVideosPaginated: every lifecycle status, trash
+// excluded. Filters: status (comma-separated), state_id, sublocation_id,
+// created_by and title (substring). Sorts: title, status.
+var videoListSpec = &listquery.Spec[db.GetVideoByIDRow]{
+	Select: `SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone, v.stream_id,
+       v.camera_model, v.firmware, v.owner_contact, v.install_date, v.notes,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL`,
+	From:  "FROM videos v",
+	Where: "v.deleted_at IS NULL",
+	Filters: append([]listquery.Filter{
+		{Param: "status", Column: "v.status", Op: listquery.OneOf, Kind: listquery.Text},
+		{Param: "state_id", Column: "v.state_id", Op: listquery.Equal, Kind: listquery.Int},
+		{Param: "sublocation_id", Column: "v.sublocation_id", Op: listquery.Equal, Kind: listquery.Int},
+		{Param: "created_by", Column: "v.created_by", Op: listquery.Equal, Kind: listquery.Text},
+		{Param: "title", Column: "v.title", Op: listquery.Contains, Kind: listquery.Text},
+	}, timestampFilters("v")...),
+	Sorts: []listquery.Sort[db.GetVideoByIDRow]{
+		positionSort("v", func(row db.GetVideoByIDRow) *int32 { return row.SortOrder }),
+		textSort("title", "v.title", func(row db.GetVideoByIDRow) string { return row.Title }),
+		textSort("status", "v.status", func(row db.GetVideoByIDRow) string { return row.Status }),
+		timeSort("created_at", "v.created_at", func(row db.GetVideoByIDRow) time.Time { return row.CreatedAt }),
+		timeSort("updated_at", "v.updated_at", func(row db.GetVideoByIDRow) time.Time { return row.UpdatedAt }),
+	},
+	DefaultSort: "position,title",
+	ID:          listquery.Key{Expr: "v.video_id", Kind: listquery.Int},
+	IDValue:     func(row db.GetVideoByIDRow) any { return row.VideoID },
+}
+
+// stateListSpec serves ListStatesPaginated. Filters: country (set by the
+// handler), name (substring) and region_type (comma-separated). Sorts: name.
+var stateListSpec = &listquery.Spec[db.GetStateByIDRow]{
+	Select: `SELECT s.state_id, s.name, s.description, s.slug, s.country_code, s.region_type,
+       s.sort_order, s.created_at, s.updated_at,
+       COUNT(v.video_id)::int AS video_count
+FROM states s
+LEFT JOIN videos v ON v.state_id = s.state_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL`,
+	From:    "FROM states s",
+	Where:   "s.deleted_at IS NULL",
+	GroupBy: "s.state_id",
+	Filters: append([]listquery.Filter{
+		{Param: "country", Column: "s.country_code", Op: listquery.Equal, Kind: listquery.Text},
+		{Param: "name", Column: "s.name", Op: listquery.Contains, Kind: listquery.Text},
+		{Param: "region_type", Column: "s.region_type", Op: listquery.OneOf, Kind: listquery.Text},
+	}, timestampFilters("s")...),
+	Sorts: []listquery.Sort[db.GetStateByIDRow]{
+		positionSort("s", func(row db.GetStateByIDRow) *int32 { return row.SortOrder }),
+		textSort("name", "s.name", func(row db.GetStateByIDRow) string { return row.Name }),
+		timeSort("created_at", "s.created_at", func(row db.GetStateByIDRow) time.Time { return row.CreatedAt }),
+		timeSort("updated_at", "s.updated_at", func(row db.GetStateByIDRow) time.Time { return row.UpdatedAt }),
+	},
+	DefaultSort: "position,name",
+	ID:          listquery.Key{Expr: "s.state_id", Kind: listquery.Int},
+	IDValue:     func(row db.GetStateByIDRow) any { return row.StateID },
+}
+
+// sublocationListSpec serves ListSublocationsPaginated. Filters: state_id,
+// parent_id and name (substring). Sorts: name, state (the state's name).
+var sublocationListSpec = &listquery.Spec[db.GetSublocationByIDRow]{
+	Select: `SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.parent_id, sub.slug,
+       sub.sort_order, sub.created_at, sub.updated_at,
+       s.name AS state_name,
+       COUNT(v.video_id)::int AS video_count
+FROM sublocations sub
+JOIN states s ON s.state_id = sub.state_id
+LEFT JOIN videos v ON v.sublocation_id IN (SELECT sublocation_subtree(sub.sublocation_id)) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL`,
+	From:    "FROM sublocations sub",
+	Where:   "sub.deleted_at IS NULL",
+	GroupBy: "sub.sublocation_id, s.name",
+	Filters: append([]listquery.Filter{
+		{Param: "state_id", Column: "sub.state_id", Op: listquery.Equal, Kind: listquery.Int},
+		{Param: "parent_id", Column: "sub.parent_id", Op: listquery.Equal, Kind: listquery.Int},
+		{Param: "name", Column: "sub.name", Op: listquery.Contains, Kind: listquery.Text},
+	}, timestampFilters("sub")...),
+	Sorts: []listquery.Sort[db.GetSublocationByIDRow]{
+		positionSort("sub", func(row db.GetSublocationByIDRow) *int32 { return row.SortOrder }),
+		textSort("name", "sub.name", func(row db.GetSublocationByIDRow) string { return row.Name }),
+		textSort("state", "s.name", func(row db.GetSublocationByIDRow) string { return row.StateName }),
+		timeSort("created_at", "sub.created_at", func(row db.GetSublocationByIDRow) time.Time { return row.CreatedAt }),
+		timeSort("updated_at", "sub.updated_at", func(row db.GetSublocationByIDRow) time.Time { return row.UpdatedAt }),
+	},
+	DefaultSort: "position,name",
+	ID:          listquery.Key{Expr: "sub.sublocation_id", Kind: listquery.Int},
+	IDValue:     func(row db.GetSublocationByIDRow) any { return row.SublocationID },
+}
+
+// listPage writes one page of the admin list spec describes, filtered and
+// sorted by params, by page number or cursor, with its total on request.
+func listPage[T any](w http.ResponseWriter, r *http.Request, pool *pgxpool.Pool, spec *listquery.Spec[T], params url.Values) {
+	p, msg := parsePagination(r)
+	if msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+	q, err := spec.Parse(params)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	var after []any
+	if p.Cursor != nil {
+		if p.Cursor.Sort != q.Sort() || len(p.Cursor.Values) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": listquery.ErrCursorMismatch.Error()})
+			return
+		}
+		after = p.Cursor.Values
+	}
+	sql, args, err := q.SQL(after, p.backward(), p.limit(), p.offset())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	rows, err := pool.Query(r.Context(), sql, args...)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[T])
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := paginate(p, items, func(row T) pageCursor {
+		return pageCursor{Sort: q.Sort(), Values: q.Values(row)}
+	})
+	if p.WithTotal {
+		var total int32
+		sql, args := q.CountSQL()
+		if err := pool.QueryRow(r.Context(), sql, args...).Scan(&total); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		resp.Total = &total
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// timestampFilters returns the created/updated range filters on alias.
+func timestampFilters(alias string) []listquery.Filter {
+	return []listquery.Filter{
+		{Param: "created_since", Column: alias + ".created_at", Op: listquery.Since, Kind: listquery.Time},
+		{Param: "created_until", Column: alias + ".created_at", Op: listquery.Until, Kind: listquery.Time},
+		{Param: "updated_since", Column: alias + ".updated_at", Op: listquery.Since, Kind: listquery.Time},
+		{Param: "updated_until", Column: alias + ".updated_at", Op: listquery.Until, Kind: listquery.Time},
+	}
+}
+
+// positionSort sorts by alias.sort_order, unset last.
+func positionSort[T any](alias string, sortOrder func(T) *int32) listquery.Sort[T] {
+	col := alias + ".sort_order"
+	return listquery.Sort[T]{
+		Name: "position",
+		Keys: []listquery.Key{
+			{Expr: "(" + col + " IS NULL)", Kind: listquery.Bool},
+			{Expr: "COALESCE(" + col + ", 0)", Kind: listquery.Int},
+		},
+		Values: func(row T) []any {
+			v := sortOrder(row)
+			if v == nil {
+				return []any{true, 0}
+			}
+			return []any{false, *v}
+		},
+	}
+}
+
+func textSort[T any](name, expr string, value func(T) string) listquery.Sort[T] {
+	return listquery.Sort[T]{
+		Name:   name,
+		Keys:   []listquery.Key{{Expr: expr, Kind: listquery.Text}},
+		Values: func(row T) []any { return []any{value(row)} },
+	}
+}
+
+func timeSort[T any](name, expr string, value func(T) time.Time) listquery.Sort[T] {
+	return listquery.Sort[T]{
+		Name:   name,
+		Keys:   []listquery.Key{{Expr: expr, Kind: listquery.Time}},
+		Values: func(row T) []any { return []any{value(row)} },
+	}
+}
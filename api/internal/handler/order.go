@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	maxReorderIDs = 1000
+
+	actionReorder = "reorder"
+)
+
+// reorderRequest lists IDs in their new display order. The IDs move among
+// the positions they already hold, so rows left out keep their place: a
+// subset, such as the featured videos, can be reordered on its own.
+type reorderRequest struct {
+	IDs []int32 `json:"ids"`
+}
+
+// ReorderStates handles PUT /states/order — sets the display order of states
+// from a drag-and-drop list of IDs (admin only).
+func ReorderStates(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return reorder(pool, c, entityState, (*db.Queries).ReorderStates, "states:*", "search:*")
+}
+
+// ReorderSublocations handles PUT /sublocations/order — ReorderStates for
+// sublocations. Order is only meaningful among siblings, so send one state's
+// (or one parent's) sublocations at a time (admin only).
+func ReorderSublocations(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return reorder(pool, c, entitySublocation, (*db.Queries).ReorderSublocations, "sublocations:*", "videos:*", "search:*")
+}
+
+// ReorderVideos handles PUT /videos/order — ReorderStates for videos. The
+// order applies to every listing that includes them, featured included
+// (admin only).
+func ReorderVideos(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return reorder(pool, c, entityVideo, (*db.Queries).ReorderVideos, "videos:*", "search:*")
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// reorder builds a handler that applies a reorderRequest with exec, which
+// must report how many of the IDs it found. Unknown or trashed IDs fail the
+// whole request.
+func reorder(
+	pool *pgxpool.Pool, c *cache.Cache, kind string,
+	exec func(*db.Queries, context.Context, []int32) (int64, error),
+	patterns ...string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req reorderRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		if len(req.IDs) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ids is required"})
+			return
+		}
+		if len(req.IDs) > maxReorderIDs {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("at most %d ids per reorder", maxReorderIDs)})
+			return
+		}
+		if msg := duplicateID(req.IDs, kind); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
+
+		n, err := exec(qtx, r.Context(), req.IDs)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if n != int64(len(req.IDs)) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("%d of the ids are not live %ss", int64(len(req.IDs))-n, kind)})
+			return
+		}
+		recordAuditTx(r.Context(), tx, actionReorder, kind, "order", nil, req)
+
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		invalidate(r.Context(), c, patterns...)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// slotPattern matches homepage slot names such as "hero" and
+// "camera-of-the-day".
+var slotPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// promotionMaxTTL caps how long a current promotion is cached when nothing
+// in its slot is scheduled to change sooner. Every promotion and video
+// change invalidates it anyway.
+const promotionMaxTTL = time.Hour
+
+// localTimeLayouts are the accepted forms of a start or end time without a
+// UTC offset; they are read in the promotion's timezone. A bare date means
+// midnight.
+var localTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// GetCurrentPromotion handles GET /promotions/current?slot=hero — the
+// promotion live in a slot now, or the slot's default when nothing scheduled
+// is. Cached until the next scheduled promotion in the slot starts or ends.
+func GetCurrentPromotion(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slot := r.URL.Query().Get("slot")
+		if !slotPattern.MatchString(slot) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "slot is required"})
+			return
+		}
+		// Keyed under videos: so video status changes invalidate it too.
+		key := "videos:promotion:" + slot
+
+		cachedHandlerTTL(c, key, func(w http.ResponseWriter, r *http.Request) time.Duration {
+			q := db.New(pool)
+			now := time.Now()
+
+			current, err := q.GetCurrentPromotion(r.Context(), db.GetCurrentPromotionParams{Slot: slot, Now: now})
+			if errors.Is(err, pgx.ErrNoRows) {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "no promotion for this slot"})
+				return 0
+			}
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return 0
+			}
+
+			ttl := promotionMaxTTL
+			next, err := q.GetNextPromotionChange(r.Context(), db.GetNextPromotionChangeParams{Slot: slot, Now: now})
+			switch {
+			case err == nil:
+				ttl = min(ttl, next.Sub(now))
+			case !errors.Is(err, pgx.ErrNoRows):
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return 0
+			}
+
+			writeJSON(w, http.StatusOK, current)
+			return ttl
+		})(w, r)
+	}
+}
+
+// promotionDetail is a promotion with its window in its own timezone, as
+// editors scheduled it.
+type promotionDetail struct {
+	db.GetPromotionRow
+	StartsAtLocal *string `json:"starts_at_local"`
+	EndsAtLocal   *string `json:"ends_at_local"`
+}
+
+// ListPromotions handles GET /promotions — every promotion, optionally
+// filtered by ?slot= (admin only).
+func ListPromotions(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListPromotions(r.Context(), optionalParam(r.URL.Query().Get("slot")))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		out := make([]promotionDetail, len(rows))
+		for i, row := range rows {
+			out[i] = newPromotionDetail(db.GetPromotionRow(row))
+		}
+		writeJSON(w, http.StatusOK, out)
+	}
+}
+
+// GetPromotion handles GET /promotions/{id} (admin only).
+func GetPromotion(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readPromotionID(w, r)
+		if !ok {
+			return
+		}
+
+		row, err := db.New(pool).GetPromotion(r.Context(), id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "promotion not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, newPromotionDetail(row))
+	}
+}
+
+// promotionRequest schedules a video into a slot. starts_at and ends_at are
+// RFC 3339 timestamps, or local times ("2026-07-04T09:00", "2026-07-04") in
+// timezone, an IANA zone name defaulting to UTC. A default promotion has no
+// window; any other needs a start, and an open end runs until removed.
+type promotionRequest struct {
+	Slot      string  `json:"slot"`
+	VideoID   int32   `json:"video_id"`
+	Title     *string `json:"title"`
+	StartsAt  string  `json:"starts_at"`
+	EndsAt    string  `json:"ends_at"`
+	Timezone  string  `json:"timezone"`
+	Priority  int32   `json:"priority"`
+	IsDefault bool    `json:"is_default"`
+}
+
+// CreatePromotion handles POST /promotions (admin only).
+func CreatePromotion(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req promotionRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		params, msg := req.params()
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		q := db.New(pool)
+		if _, err := q.GetVideoByID(r.Context(), req.VideoID); err != nil {
+			writePromotionVideoError(w, err)
+			return
+		}
+
+		id, err := q.CreatePromotion(r.Context(), db.CreatePromotionParams{
+			Slot:      params.Slot,
+			VideoID:   params.VideoID,
+			Title:     params.Title,
+			StartsAt:  params.StartsAt,
+			EndsAt:    params.EndsAt,
+			Timezone:  params.Timezone,
+			Priority:  params.Priority,
+			IsDefault: params.IsDefault,
+			CreatedBy: optionalParam(middleware.UserID(r.Context())),
+		})
+		if isUniqueViolation(err) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "slot " + params.Slot + " already has a default promotion"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		created, err := q.GetPromotion(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionCreate, entityPromotion, id, nil, created)
+
+		invalidate(r.Context(), c, "videos:promotion:*")
+		writeJSON(w, http.StatusCreated, newPromotionDetail(created))
+	}
+}
+
+// UpdatePromotion handles PUT /promotions/{id} — replaces a promotion's slot,
+// video and window (admin only).
+func UpdatePromotion(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readPromotionID(w, r)
+		if !ok {
+			return
+		}
+
+		var req promotionRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		params, msg := req.params()
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+		params.PromotionID = id
+
+		q := db.New(pool)
+		before, err := q.GetPromotion(r.Context(), id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "promotion not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if params.VideoID != before.VideoID {
+			if _, err := q.GetVideoByID(r.Context(), params.VideoID); err != nil {
+				writePromotionVideoError(w, err)
+				return
+			}
+		}
+
+		if _, err := q.UpdatePromotion(r.Context(), params); err != nil {
+			if isUniqueViolation(err) {
+				writeJSON(w, http.StatusConflict, map[string]string{"error": "slot " + params.Slot + " already has a default promotion"})
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		row, err := q.GetPromotion(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionUpdate, entityPromotion, id, before, row)
+
+		invalidate(r.Context(), c, "videos:promotion:*")
+		writeJSON(w, http.StatusOK, newPromotionDetail(row))
+	}
+}
+
+// DeletePromotion handles DELETE /promotions/{id} (admin only).
+func DeletePromotion(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readPromotionID(w, r)
+		if !ok {
+			return
+		}
+
+		q := db.New(pool)
+		before, err := q.GetPromotion(r.Context(), id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "promotion not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		if _, err := q.DeletePromotion(r.Context(), id); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionDelete, entityPromotion, id, before, nil)
+
+		invalidate(r.Context(), c, "videos:promotion:*")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// params validates req and converts it to update params (PromotionID
+// unset), or returns an error message.
+func (req promotionRequest) params() (db.UpdatePromotionParams, string) {
+	p := db.UpdatePromotionParams{
+		Slot:      req.Slot,
+		VideoID:   req.VideoID,
+		Title:     req.Title,
+		Timezone:  req.Timezone,
+		Priority:  req.Priority,
+		IsDefault: req.IsDefault,
+	}
+	if !slotPattern.MatchString(p.Slot) {
+		return p, "slot must be lowercase letters, digits and hyphens"
+	}
+	if p.VideoID <= 0 {
+		return p, "video_id is required"
+	}
+	if p.Timezone == "" {
+		p.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return p, "unknown timezone " + p.Timezone
+	}
+
+	if p.IsDefault {
+		if req.StartsAt != "" || req.EndsAt != "" {
+			return p, "a default promotion has no starts_at or ends_at"
+		}
+		return p, ""
+	}
+	if req.StartsAt == "" {
+		return p, "starts_at is required unless is_default is set"
+	}
+	startsAt, ok := parsePromotionTime(req.StartsAt, loc)
+	if !ok {
+		return p, "starts_at must be an RFC 3339 timestamp or a local date and time"
+	}
+	p.StartsAt = &startsAt
+	if req.EndsAt == "" {
+		return p, ""
+	}
+	endsAt, ok := parsePromotionTime(req.EndsAt, loc)
+	if !ok {
+		return p, "ends_at must be an RFC 3339 timestamp or a local date and time"
+	}
+	p.EndsAt = &endsAt
+	if !endsAt.After(startsAt) {
+		return p, "ends_at must be after starts_at"
+	}
+	return p, ""
+}
+
+// parsePromotionTime parses s as an RFC 3339 timestamp, or failing that as a
+// local time in loc.
+func parsePromotionTime(s string, loc *time.Location) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// newPromotionDetail renders row's window in its timezone.
+func newPromotionDetail(row db.GetPromotionRow) promotionDetail {
+	loc, err := time.LoadLocation(row.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return promotionDetail{
+		GetPromotionRow: row,
+		StartsAtLocal:   formatLocal(row.StartsAt, loc),
+		EndsAtLocal:     formatLocal(row.EndsAt, loc),
+	}
+}
+
+// formatLocal formats t as a local time in loc, or returns nil for nil.
+func formatLocal(t *time.Time, loc *time.Location) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.In(loc).Format(localTimeLayouts[0])
+	return &s
+}
+
+// readPromotionID parses the {id} param, writing a 400 if it is invalid.
+func readPromotionID(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid promotion id"})
+		return 0, false
+	}
+	return int32(id), true
+}
+
+// writePromotionVideoError maps a failed lookup of a promotion's video.
+func writePromotionVideoError(w http.ResponseWriter, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "video not found"})
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// maxReportBody caps a viewer report's request body.
+const maxReportBody = 4 << 10
+
+// reportReasons are the reasons a viewer may report a camera for.
+var reportReasons = []string{"offline", "wrong_location", "inappropriate"}
+
+type reportRequest struct {
+	Reason string `json:"reason"`
+	Note   string `json:"note"`
+}
+
+// CreateReport handles POST /videos/{id}/reports — a viewer reports a broken
+// camera. Unauthenticated; a client's repeat reports for the same reason
+// add to its open report rather than opening another. With a threshold, a
+// published camera that many distinct clients report goes into maintenance.
+func CreateReport(pool *pgxpool.Pool, c *cache.Cache, clients *middleware.ClientHasher, threshold int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || id <= 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid video id"})
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxReportBody)
+		var req reportRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		req.Note = strings.TrimSpace(req.Note)
+		if !slices.Contains(reportReasons, req.Reason) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "reason must be one of " + strings.Join(reportReasons, ", ")})
+			return
+		}
+		if len([]rune(req.Note)) > 500 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "note must be at most 500 characters"})
+			return
+		}
+
+		q := db.New(pool)
+		status, err := q.GetListedVideoStatus(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "video not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		inserted, err := q.CreateVideoReport(r.Context(), db.CreateVideoReportParams{
+			VideoID:    int32(id),
+			Reason:     req.Reason,
+			Note:       emptyToNil(req.Note),
+			ClientHash: clients.Hash(r),
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if inserted && threshold > 0 && status == statusPublished {
+			flagReportedVideo(r, q, c, int32(id), threshold)
+		}
+		writeJSON(w, http.StatusAccepted, map[string]string{"status": "received"})
+	}
+}
+
+// ListReports handles GET /admin/reports — cameras with open reports, most
+// reported first, with counts by reason. Reports count repeats; reporters
+// count distinct addresses, which viewers on a shared network have in
+// common (admin only).
+func ListReports(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListReportedVideos(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, rows)
+	}
+}
+
+// ListVideoReports handles GET /admin/reports/{video_id} — a camera's open
+// reports, most recently reported first (admin only).
+func ListVideoReports(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readVideoIDParam(w, r)
+		if !ok {
+			return
+		}
+		rows, err := db.New(pool).ListVideoReports(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, rows)
+	}
+}
+
+// ResolveReports handles POST /admin/reports/{video_id}/resolve — closes a
+// camera's open reports once it has been dealt with. Its status is left for
+// the admin to set (admin only).
+func ResolveReports(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readVideoIDParam(w, r)
+		if !ok {
+			return
+		}
+		resolver := middleware.UserID(r.Context())
+		n, err := db.New(pool).ResolveVideoReports(r.Context(), db.ResolveVideoReportsParams{
+			VideoID:    id,
+			ResolvedBy: &resolver,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int64{"resolved": n})
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// flagReportedVideo puts video id into maintenance once threshold distinct
+// clients have open reports on it. It runs after the report is stored, so
+// failures are logged rather than returned to the reporter.
+func flagReportedVideo(r *http.Request, q *db.Queries, c *cache.Cache, id int32, threshold int) {
+	ctx := r.Context()
+	reporters, err := q.CountVideoReporters(ctx, id)
+	if err != nil {
+		slog.Warn("reports: count failed", "video_id", id, "error", err)
+		return
+	}
+	if int(reporters) < threshold {
+		return
+	}
+
+	before, err := q.GetVideoByID(ctx, id)
+	if err != nil {
+		slog.Warn("reports: flag failed", "video_id", id, "error", err)
+		return
+	}
+	// The status guard skips a video an admin or the health check changed
+	// meanwhile.
+	n, err := q.FlagReportedVideo(ctx, id)
+	if err != nil {
+		slog.Warn("reports: flag failed", "video_id", id, "error", err)
+		return
+	}
+	if n == 0 {
+		return
+	}
+	after, err := q.GetVideoByID(ctx, id)
+	if err != nil {
+		slog.Warn("reports: flag failed", "video_id", id, "error", err)
+		return
+	}
+	slog.Info("video flagged for maintenance by reports", "video_id", id, "reporters", reporters)
+	recordAudit(ctx, q, actionUpdate, entityVideo, id, before, after)
+	invalidate(ctx, c, "videos:*", "states:*", "sublocations:*", "search:*")
+}
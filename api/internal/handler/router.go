@@ -12,7 +12,7 @@ import (
 
 // NewRouter builds the Chi router with all routes and middleware.
 // rc may be nil if Restreamer is not configured (stream routes are not mounted).
-func NewRouter(pool *pgxpool.Pool, c *cache.Cache, auth *mw.Auth, corsOrigins []string, rc *restreamer.Client, streamerAPIKey string) *chi.Mux {
+func NewRouter(pool *pgxpool.Pool, c *cache.Cache, auth *mw.Auth, clients *mw.ClientHasher, reportThreshold int, corsOrigins []string, rc *restreamer.Client, streamerAPIKey string) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Global middleware.
@@ -20,31 +20,126 @@ func NewRouter(pool *pgxpool.Pool, c *cache.Cache, auth *mw.Auth, corsOrigins []
 	r.Use(mw.CORS(corsOrigins))
 	r.Use(auth.Authenticate)
 
+	// Marks the signed-in user's favorites in video responses.
+	favorites := markFavorites(pool)
+
 	// Health.
 	r.Get("/health", Health(pool, c))
 
-	// States.
+	// States — the US regions, kept for the web app.
 	r.Get("/states", ListStates(pool, c))
 	r.Get("/states/{slug}", GetState(pool, c))
 	r.With(mw.RequireAdmin).Post("/states", CreateState(pool, c))
 	r.With(mw.RequireAdmin).Put("/states/{id}", UpdateState(pool, c))
 	r.With(mw.RequireAdmin).Delete("/states/{slug}", DeleteState(pool, c))
 	r.With(mw.RequireAdmin).Get("/states/paginated", ListStatesPaginated(pool, c))
+	r.With(mw.RequireAdmin).Post("/states/{id}/restore", RestoreState(pool, c))
+	r.With(mw.RequireAdmin).Put("/states/{id}/slug", ChangeStateSlug(pool, c))
+	r.With(mw.RequireAdmin).Put("/states/order", ReorderStates(pool, c))
+
+	// Countries and their regions. Regions are rows of the states table, so
+	// /states/{id} updates, deletes and restores work for any country.
+	r.Get("/countries", ListCountries(pool, c))
+	r.Get("/countries/{code}", GetCountry(pool, c))
+	r.Get("/countries/{code}/regions", ListRegions(pool, c))
+	r.Get("/regions/{slug}", GetRegion(pool, c))
+	r.Get("/regions/{slug}/sublocations", ListSublocationsByState(pool, c))
+	r.With(mw.RequireAdmin).Post("/countries", CreateCountry(pool, c))
+	r.With(mw.RequireAdmin).Put("/countries/{code}", UpdateCountry(pool, c))
+	r.With(mw.RequireAdmin).Post("/countries/{code}/regions", CreateRegion(pool, c))
 
 	// Sublocations.
 	r.Get("/states/{slug}/sublocations", ListSublocationsByState(pool, c))
 	r.Get("/sublocations/{slug}", GetSublocation(pool, c))
+	r.With(favorites).Get("/sublocations/{slug}/videos", ListSublocationVideos(pool, c))
 	r.With(mw.RequireAdmin).Post("/sublocations", CreateSublocation(pool, c))
 	r.With(mw.RequireAdmin).Put("/sublocations/{id}", UpdateSublocation(pool, c))
 	r.With(mw.RequireAdmin).Delete("/sublocations/{id}", DeleteSublocation(pool, c))
 	r.With(mw.RequireAdmin).Get("/sublocations/paginated", ListSublocationsPaginated(pool, c))
+	r.With(mw.RequireAdmin).Post("/sublocations/{id}/restore", RestoreSublocation(pool, c))
+	r.With(mw.RequireAdmin).Put("/sublocations/{id}/slug", ChangeSublocationSlug(pool, c))
+	r.With(mw.RequireAdmin).Put("/sublocations/order", ReorderSublocations(pool, c))
 
-	// Videos.
-	r.Get("/videos", ListVideos(pool, c))
+	// Videos. Viewer reports share a per-client limit; duplicates are
+	// deduped in the database.
+	reports := mw.NewClientRateLimiter(20, time.Hour)
+	r.With(favorites).Get("/videos", ListVideos(pool, c))
+	r.With(favorites).Get("/videos/featured", ListFeaturedVideos(pool, c))
+	r.With(favorites).Get("/videos/nearby", NearbyVideos(pool, c))
+	r.Get("/videos.geojson", VideosGeoJSON(pool, c))
+	r.Get("/videos.kml", VideosKML(pool, c))
+	r.Get("/videos/{id}/localtime", VideoLocalTime(pool))
+	r.With(mw.RateLimitByClient(reports, clients)).Post("/videos/{id}/reports", CreateReport(pool, c, clients, reportThreshold))
 	r.With(mw.RequireAdmin).Post("/videos", CreateVideo(pool, c))
 	r.With(mw.RequireAdmin).Put("/videos/{id}", UpdateVideo(pool, c))
 	r.With(mw.RequireAdmin).Delete("/videos/{id}", DeleteVideo(pool, c))
 	r.With(mw.RequireAdmin).Get("/videos/paginated", ListVideosPaginated(pool, c))
+	r.With(mw.RequireAdmin).Post("/videos/{id}/restore", RestoreVideo(pool, c))
+	r.With(mw.RequireAdmin).Put("/videos/order", ReorderVideos(pool, c))
+
+	// Promotions — editorially scheduled homepage slots.
+	r.With(favorites).Get("/promotions/current", GetCurrentPromotion(pool, c))
+	r.With(mw.RequireAdmin).Get("/promotions", ListPromotions(pool))
+	r.With(mw.RequireAdmin).Get("/promotions/{id}", GetPromotion(pool))
+	r.With(mw.RequireAdmin).Post("/promotions", CreatePromotion(pool, c))
+	r.With(mw.RequireAdmin).Put("/promotions/{id}", UpdatePromotion(pool, c))
+	r.With(mw.RequireAdmin).Delete("/promotions/{id}", DeletePromotion(pool, c))
+
+	// The signed-in viewer's own favorites and camera lists.
+	r.Route("/me", func(r chi.Router) {
+		r.Use(mw.RequireUser)
+		r.Get("/favorites", ListFavorites(pool))
+		r.Get("/favorites/{video_id}", GetFavorite(pool))
+		r.Put("/favorites/{video_id}", AddFavorite(pool))
+		r.Delete("/favorites/{video_id}", RemoveFavorite(pool))
+		r.Get("/lists", ListUserLists(pool))
+		r.Post("/lists", CreateUserList(pool))
+		r.Put("/lists/order", ReorderUserLists(pool))
+		r.Get("/lists/{id}", GetUserList(pool))
+		r.Put("/lists/{id}", UpdateUserList(pool))
+		r.Delete("/lists/{id}", DeleteUserList(pool))
+	})
+
+	// Camera submissions from visitors, moderated under /admin.
+	submissions := mw.NewClientRateLimiter(5, 10*time.Minute)
+	r.With(mw.RateLimitByClient(submissions, clients)).Post("/submissions", CreateSubmission(pool, clients))
+
+	// Catalog backup — slug-keyed JSON export and restore — the
+	// submission moderation queue and viewer reports.
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(mw.RequireAdmin)
+		r.Get("/export", ExportCatalog(pool))
+		r.Post("/restore", RestoreCatalog(pool, c))
+		r.Get("/submissions", ListSubmissions(pool))
+		r.Get("/submissions/{id}", GetSubmission(pool))
+		r.Post("/submissions/{id}/approve", ApproveSubmission(pool, c))
+		r.Post("/submissions/{id}/reject", RejectSubmission(pool))
+		r.Get("/reports", ListReports(pool))
+		r.Get("/reports/{video_id}", ListVideoReports(pool))
+		r.Post("/reports/{video_id}/resolve", ResolveReports(pool))
+	})
+
+	// Bulk import.
+	r.With(mw.RequireAdmin).Post("/import/videos", ImportVideos(pool, c))
+
+	// Trash (soft-deleted rows awaiting purge).
+	r.With(mw.RequireAdmin).Get("/trash", ListTrash(pool))
+
+	// Audit log.
+	r.With(mw.RequireAdmin).Get("/audit", ListAudit(pool))
+	r.With(mw.RequireAdmin).Post("/audit/{id}/revert", RevertAudit(pool, c))
+
+	// Tags.
+	r.Get("/tags", ListTags(pool, c))
+	r.With(mw.RequireAdmin).Post("/tags", CreateTag(pool, c))
+	r.With(mw.RequireAdmin).Put("/tags/{id}", UpdateTag(pool, c))
+	r.With(mw.RequireAdmin).Delete("/tags/{id}", DeleteTag(pool, c))
+
+	// Slugs — what a name would become, before saving it.
+	r.With(mw.RequireAdmin).Get("/slugs/preview", PreviewSlug(pool))
+
+	// Search.
+	r.Get("/search", Search(pool, c))
 
 	// Stream proxy — proxies external HLS manifests/segments to bypass CORS.
 	r.Get("/stream-proxy", StreamProxy())
@@ -56,10 +151,10 @@ func NewRouter(pool *pgxpool.Pool, c *cache.Cache, auth *mw.Auth, corsOrigins []
 		r.Route("/streams", func(r chi.Router) {
 			r.Use(mw.RequireAPIKeyOrAdmin(streamerAPIKey))
 			r.Get("/", ListStreams(rc))
-			r.With(mw.RateLimit(rl)).Post("/", CreateStream(rc))
+			r.With(mw.RateLimit(rl)).Post("/", CreateStream(pool, c, rc))
 			r.Get("/{id}", GetStream(rc))
-			r.Delete("/{id}", DeleteStream(rc))
-			r.Post("/{id}/restart", RestartStream(rc))
+			r.Delete("/{id}", DeleteStream(pool, c, rc))
+			r.Post("/{id}/restart", RestartStream(pool, rc))
 		})
 	}
 
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	maxSearchTerms   = 8
+	maxSearchTermLen = 64
+	defaultSearchMax = 20
+	maxSearchResults = 50
+)
+
+// Search handles GET /search?q=&limit= — ranked full-text search across states,
+// sublocations and videos. Every term is prefix-matched so partial input works
+// for type-ahead. Results are cached per normalized query.
+func Search(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		terms := searchTerms(r.URL.Query().Get("q"))
+		if len(terms) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "q is required"})
+			return
+		}
+
+		limit := int32(defaultSearchMax)
+		if ls := r.URL.Query().Get("limit"); ls != "" {
+			if v, err := strconv.Atoi(ls); err == nil && v > 0 {
+				limit = int32(min(v, maxSearchResults))
+			}
+		}
+
+		key := "search:" + strconv.Itoa(int(limit)) + ":" + strings.Join(terms, " ")
+		cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
+			rows, err := db.New(pool).Search(r.Context(), db.SearchParams{
+				Query:      prefixTSQuery(terms),
+				MaxResults: limit,
+			})
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, rows)
+		})(w, r)
+	}
+}
+
+// searchTerms normalizes a raw query into lowercase words made only of letters
+// and digits. Everything else is a separator, so the terms can never carry
+// tsquery operators.
+func searchTerms(q string) []string {
+	words := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		if len(terms) == maxSearchTerms {
+			break
+		}
+		if runes := []rune(w); len(runes) > maxSearchTermLen {
+			w = string(runes[:maxSearchTermLen])
+		}
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// prefixTSQuery builds a to_tsquery expression requiring every term as a
+// prefix, e.g. ["venice", "mar"] → "venice:* & mar:*".
+func prefixTSQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		parts[i] = t + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
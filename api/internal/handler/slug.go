@@ -0,0 +1,344 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/slug"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// slugPattern is lowercase ASCII words joined by single hyphens.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// slugAttempts bounds retries when a concurrent insert takes the slug picked
+// for a new row between choosing it and inserting.
+const slugAttempts = 3
+
+type changeSlugRequest struct {
+	// Slug is the new slug; empty regenerates it from the current name.
+	Slug string `json:"slug"`
+}
+
+type changeSlugResponse struct {
+	Slug          string   `json:"slug"`
+	PreviousSlugs []string `json:"previous_slugs"`
+}
+
+type slugPreviewResponse struct {
+	// Base is the slug generated from the name alone; Slug is what saving
+	// now would use, with a "-N" suffix if Base is taken.
+	Base string `json:"base"`
+	Slug string `json:"slug"`
+}
+
+// PreviewSlug handles GET /slugs/preview?kind=state|sublocation|tag&name= —
+// the slug a state, sublocation (which also needs state_id) or tag with that
+// name would get. Pass id when renaming an existing row so its own slug
+// doesn't count as taken (admin only).
+func PreviewSlug(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		name := strings.TrimSpace(params.Get("name"))
+		if name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return
+		}
+		var exceptID int32
+		if s := params.Get("id"); s != "" {
+			id, err := strconv.Atoi(s)
+			if err != nil || id <= 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid id"})
+				return
+			}
+			exceptID = int32(id)
+		}
+
+		q := db.New(pool)
+		kind := params.Get("kind")
+		var (
+			unique string
+			err    error
+		)
+		switch kind {
+		case entityState:
+			unique, err = uniqueStateSlug(r.Context(), q, name, exceptID)
+		case entitySublocation:
+			stateID, convErr := strconv.Atoi(params.Get("state_id"))
+			if convErr != nil || stateID <= 0 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "state_id is required for sublocations"})
+				return
+			}
+			unique, err = uniqueSublocationSlug(r.Context(), q, int32(stateID), name, exceptID)
+		case entityTag:
+			unique, err = uniqueTagSlug(r.Context(), q, name, exceptID)
+		default:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "kind must be state, sublocation or tag"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, slugPreviewResponse{Base: baseSlug(name, kind), Slug: unique})
+	}
+}
+
+// ChangeStateSlug handles PUT /states/{id}/slug — changes a state's slug. The
+// old slug keeps working: GetState redirects it to the new one (admin only).
+func ChangeStateSlug(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, req, ok := readChangeSlug(w, r, "state")
+		if !ok {
+			return
+		}
+
+		q := db.New(pool)
+		before, err := q.GetStateByID(r.Context(), id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "state not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		if req.Slug == "" {
+			if req.Slug, err = uniqueStateSlug(r.Context(), q, before.Name, id); err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+		}
+		changed, err := q.ChangeStateSlug(r.Context(), db.ChangeStateSlugParams{StateID: id, Slug: req.Slug})
+		if err != nil {
+			writeChangeSlugError(w, "state", err)
+			return
+		}
+
+		after, err := q.GetStateByID(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionUpdate, entityState, id, before, after)
+
+		// Sublocation and video rows don't embed the state slug.
+		invalidate(r.Context(), c, "states:*", "search:*")
+		writeSlugChanged(w, r.Context(), q, entityState, id, changed)
+	}
+}
+
+// ChangeSublocationSlug handles PUT /sublocations/{id}/slug — changes a
+// sublocation's slug. The old slug keeps working: GetSublocation redirects it
+// to the new one (admin only).
+func ChangeSublocationSlug(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, req, ok := readChangeSlug(w, r, "sublocation")
+		if !ok {
+			return
+		}
+
+		q := db.New(pool)
+		before, err := q.GetSublocationByID(r.Context(), id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "sublocation not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		if req.Slug == "" {
+			if req.Slug, err = uniqueSublocationSlug(r.Context(), q, before.StateID, before.Name, id); err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+		}
+		changed, err := q.ChangeSublocationSlug(r.Context(), db.ChangeSublocationSlugParams{SublocationID: id, Slug: req.Slug})
+		if err != nil {
+			writeChangeSlugError(w, "sublocation", err)
+			return
+		}
+
+		after, err := q.GetSublocationByID(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionUpdate, entitySublocation, id, before, after)
+
+		invalidate(r.Context(), c, "sublocations:*", "videos:sublocation-tree:*", "search:*")
+		writeSlugChanged(w, r.Context(), q, entitySublocation, id, changed)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// baseSlug is the slug for name, falling back to kind for names with nothing
+// transliterable (e.g. written only in CJK characters).
+func baseSlug(name, kind string) string {
+	if s := slug.Make(name); s != "" {
+		return s
+	}
+	return kind
+}
+
+// uniqueStateSlug returns a free slug for a state named name. exceptID is a
+// state whose own slug doesn't count as taken, or 0.
+func uniqueStateSlug(ctx context.Context, q *db.Queries, name string, exceptID int32) (string, error) {
+	base := baseSlug(name, entityState)
+	taken, err := q.ListStateSlugs(ctx, db.ListStateSlugsParams{Base: base, ExceptID: exceptID})
+	if err != nil {
+		return "", err
+	}
+	return slug.Unique(base, taken), nil
+}
+
+// uniqueSublocationSlug is uniqueStateSlug for a sublocation of stateID.
+func uniqueSublocationSlug(ctx context.Context, q *db.Queries, stateID int32, name string, exceptID int32) (string, error) {
+	base := baseSlug(name, entitySublocation)
+	taken, err := q.ListSublocationSlugs(ctx, db.ListSublocationSlugsParams{StateID: stateID, Base: base, ExceptID: exceptID})
+	if err != nil {
+		return "", err
+	}
+	return slug.Unique(base, taken), nil
+}
+
+// uniqueTagSlug is uniqueStateSlug for tags.
+func uniqueTagSlug(ctx context.Context, q *db.Queries, name string, exceptID int32) (string, error) {
+	base := baseSlug(name, entityTag)
+	taken, err := q.ListTagSlugs(ctx, db.ListTagSlugsParams{Base: base, ExceptID: exceptID})
+	if err != nil {
+		return "", err
+	}
+	return slug.Unique(base, taken), nil
+}
+
+// movedSublocationSlug returns the slug sub needs to move to stateID: nil if
+// its current slug is free there, else the next free suffix, since slugs are
+// only unique within a state.
+func movedSublocationSlug(ctx context.Context, q *db.Queries, sub db.GetSublocationByIDRow, stateID int32) (*string, error) {
+	if stateID == sub.StateID {
+		return nil, nil
+	}
+	taken, err := q.ListSublocationSlugs(ctx, db.ListSublocationSlugsParams{
+		StateID:  stateID,
+		Base:     sub.Slug,
+		ExceptID: sub.SublocationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if free := slug.Unique(sub.Slug, taken); free != sub.Slug {
+		return &free, nil
+	}
+	return nil, nil
+}
+
+// retrySlugConflict runs insert, which should pick a fresh unique slug each
+// time, until it doesn't hit a slug unique violation or attempts run out.
+// Outside a transaction this covers two admins racing for the same slug.
+func retrySlugConflict(insert func() error) error {
+	var err error
+	for range slugAttempts {
+		if err = insert(); !isSlugConflict(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// isSlugConflict reports whether err is a unique violation on a slug index.
+func isSlugConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && strings.Contains(pgErr.ConstraintName, "slug")
+}
+
+// isUniqueViolation reports whether err is any unique violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isForeignKeyViolation reports whether err is any foreign key violation.
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// readChangeSlug parses the {id} param and body of a change-slug request,
+// writing a 400 if either is invalid.
+func readChangeSlug(w http.ResponseWriter, r *http.Request, kind string) (int32, changeSlugRequest, bool) {
+	var req changeSlugRequest
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid " + kind + " id"})
+		return 0, req, false
+	}
+	if err := readJSON(r, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return 0, req, false
+	}
+	if req.Slug != "" && !slugPattern.MatchString(req.Slug) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "slug must be lowercase letters and digits separated by single hyphens"})
+		return 0, req, false
+	}
+	return int32(id), req, true
+}
+
+// writeChangeSlugError maps a Change*Slug error to a response.
+func writeChangeSlugError(w http.ResponseWriter, kind string, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// The row exists (the caller fetched it) but is in the trash.
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "restore the " + kind + " before changing its slug"})
+	case isUniqueViolation(err):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "slug is already in use"})
+	default:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// writeSlugChanged responds with the new slug and the ones that now redirect to it.
+func writeSlugChanged(w http.ResponseWriter, ctx context.Context, q *db.Queries, entityType string, id int32, current string) {
+	history, err := q.ListSlugHistory(ctx, db.ListSlugHistoryParams{EntityType: entityType, EntityID: id})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	resp := changeSlugResponse{Slug: current, PreviousSlugs: make([]string, len(history))}
+	for i, h := range history {
+		resp.PreviousSlugs[i] = h.Slug
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// writeSlugRedirect answers a request for a retired slug with a 301 naming
+// the canonical one. Location is relative so it resolves correctly behind a
+// path-prefixing proxy, and keeps r's query.
+func writeSlugRedirect(w http.ResponseWriter, r *http.Request, kind, canonical string) {
+	loc := url.PathEscape(canonical)
+	if r.URL.RawQuery != "" {
+		loc += "?" + r.URL.RawQuery
+	}
+	w.Header().Set("Location", loc)
+	writeJSON(w, http.StatusMovedPermanently, map[string]string{
+		"error": kind + " has moved",
+		"slug":  canonical,
+	})
+}
@@ -1,22 +1,26 @@
 package handler
 
 import (
-	"log/slog"
+	"cmp"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/brandon-relentnet/nationcam/api/internal/cache"
 	"github.com/brandon-relentnet/nationcam/api/internal/db"
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const statesAllKey = "states:all"
 
-// ListStates handles GET /states — returns all states with video counts (cached).
+// ListStates handles GET /states — returns all US states with video counts
+// (cached). Other countries' regions are under /countries/{code}/regions.
 func ListStates(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return cachedHandler(c, statesAllKey, func(w http.ResponseWriter, r *http.Request) {
-		rows, err := db.New(pool).ListStates(r.Context())
+		rows, err := db.New(pool).ListStates(r.Context(), usCountryCode)
 		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
@@ -25,15 +29,34 @@ func ListStates(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	})
 }
 
-// GetState handles GET /states/{slug} — returns a single state by slug (cached).
+// GetState handles GET /states/{slug} — returns a single US state by slug
+// (cached). A retired slug gets a 301 naming the current one.
 func GetState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return getRegion(pool, c, usCountryCode)
+}
+
+// GetRegion handles GET /regions/{slug} — GetState for a region of any country.
+func GetRegion(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return getRegion(pool, c, "")
+}
+
+// getRegion serves a region by slug, limited to country unless it is "".
+func getRegion(pool *pgxpool.Pool, c *cache.Cache, country string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		slug := chi.URLParam(r, "slug")
-		key := "states:slug:" + slug
+		key := "states:slug:" + country + ":" + slug
 
 		cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
-			row, err := db.New(pool).GetStateBySlug(r.Context(), slug)
+			q := db.New(pool)
+			row, err := q.GetStateBySlug(r.Context(), slug)
+			if err == nil && country != "" && row.CountryCode != country {
+				err = pgx.ErrNoRows
+			}
 			if err != nil {
+				if canonical, err := q.GetStateSlugRedirect(r.Context(), slug); err == nil {
+					writeSlugRedirect(w, r, "state", canonical)
+					return
+				}
 				writeJSON(w, http.StatusNotFound, map[string]string{"error": "state not found"})
 				return
 			}
@@ -42,7 +65,8 @@ func GetState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	}
 }
 
-// DeleteState handles DELETE /states/{slug} — deletes a state and cascades (admin only).
+// DeleteState handles DELETE /states/{slug} — moves a state and all of its
+// sublocations and videos to the trash (admin only).
 func DeleteState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		slug := chi.URLParam(r, "slug")
@@ -51,58 +75,59 @@ func DeleteState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			return
 		}
 
-		if err := db.New(pool).DeleteState(r.Context(), slug); err != nil {
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
 
-		if err := c.Invalidate(r.Context(), "states:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "states:*", "error", err)
+		before, err := qtx.GetStateBySlug(r.Context(), slug)
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "state not found"})
+			return
 		}
-		if err := c.Invalidate(r.Context(), "sublocations:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "sublocations:*", "error", err)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
 		}
-		if err := c.Invalidate(r.Context(), "videos:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "videos:*", "error", err)
+		if _, err := trashState(r.Context(), qtx, slug); err != nil {
+			writeTrashError(w, "state", err)
+			return
 		}
-		w.WriteHeader(http.StatusNoContent)
-	}
-}
+		recordAuditTx(r.Context(), tx, actionDelete, entityState, before.StateID, before, nil)
 
-// ListStatesPaginated handles GET /states?page=1&per_page=20 — paginated list.
-func ListStatesPaginated(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		limit, offset, page, perPage := parsePagination(r)
-
-		rows, err := db.New(pool).ListStatesPaginated(r.Context(), db.ListStatesPaginatedParams{
-			Limit:  limit,
-			Offset: offset,
-		})
-		if err != nil {
+		if err := tx.Commit(r.Context()); err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
 
-		var total int32
-		if len(rows) > 0 {
-			total = rows[0].TotalCount
-		}
+		invalidate(r.Context(), c, "states:*", "sublocations:*", "videos:*", "tags:*", "search:*")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
 
-		writeJSON(w, http.StatusOK, paginatedResponse{
-			Data:    rows,
-			Total:   total,
-			Page:    page,
-			PerPage: perPage,
-		})
+// ListStatesPaginated handles GET /states/paginated?page=1&per_page=20 (or
+// ?cursor=) — one country's regions, the US by default, filtered and sorted
+// per stateListSpec, e.g. ?name=new&sort=-updated_at (admin only).
+func ListStatesPaginated(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		params.Set("country", strings.ToUpper(cmp.Or(params.Get("country"), usCountryCode)))
+		listPage(w, r, pool, stateListSpec, params)
 	}
 }
 
 type updateStateRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// RegionType is optional; empty keeps the current type.
+	RegionType string `json:"region_type"`
 }
 
-// UpdateState handles PUT /states/{id} — updates a state (admin only).
+// UpdateState handles PUT /states/{id} — updates a state, or any country's
+// region (admin only).
 func UpdateState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		idStr := chi.URLParam(r, "id")
@@ -121,12 +146,35 @@ func UpdateState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
 			return
 		}
+		if msg := validateRegionType(req.RegionType); req.RegionType != "" && msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		before, err := db.New(pool).GetStateByID(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "state not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		if req.RegionType == "" {
+			req.RegionType = before.RegionType
+		}
 
 		if err := db.New(pool).UpdateState(r.Context(), db.UpdateStateParams{
 			StateID:     int32(id),
 			Name:        req.Name,
 			Description: req.Description,
+			RegionType:  req.RegionType,
 		}); err != nil {
+			if isUniqueViolation(err) {
+				writeJSON(w, http.StatusConflict, map[string]string{"error": "a region with that name already exists in this country"})
+				return
+			}
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
@@ -137,10 +185,9 @@ func UpdateState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
+		recordAudit(r.Context(), db.New(pool), actionUpdate, entityState, id, before, row)
 
-		if err := c.Invalidate(r.Context(), "states:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "states:*", "error", err)
-		}
+		invalidate(r.Context(), c, "states:*", "search:*")
 		writeJSON(w, http.StatusOK, row)
 	}
 }
@@ -150,7 +197,7 @@ type createStateRequest struct {
 	Description string `json:"description"`
 }
 
-// CreateState handles POST /states — creates a new state (admin only).
+// CreateState handles POST /states — creates a new US state (admin only).
 func CreateState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req createStateRequest
@@ -162,26 +209,44 @@ func CreateState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
 			return
 		}
+		createRegion(w, r, pool, c, req, usCountryCode, regionState)
+	}
+}
 
-		created, err := db.New(pool).CreateState(r.Context(), db.CreateStateParams{
+// createRegion creates a region of country from req and writes the response.
+func createRegion(w http.ResponseWriter, r *http.Request, pool *pgxpool.Pool, c *cache.Cache, req createStateRequest, country, regionType string) {
+	var created db.CreateStateRow
+	err := retrySlugConflict(func() error {
+		slug, err := uniqueStateSlug(r.Context(), db.New(pool), req.Name, 0)
+		if err != nil {
+			return err
+		}
+		created, err = db.New(pool).CreateState(r.Context(), db.CreateStateParams{
 			Name:        req.Name,
 			Description: req.Description,
+			Slug:        slug,
+			CountryCode: country,
+			RegionType:  regionType,
 		})
-		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			return
-		}
-
-		// Re-fetch with JOINs to return the rich type (includes video_count).
-		row, err := db.New(pool).GetStateByID(r.Context(), created.StateID)
-		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			return
-		}
+		return err
+	})
+	if isUniqueViolation(err) {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "a region with that name already exists in this country"})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
 
-		if err := c.Invalidate(r.Context(), "states:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "states:*", "error", err)
-		}
-		writeJSON(w, http.StatusCreated, row)
+	// Re-fetch with JOINs to return the rich type (includes video_count).
+	row, err := db.New(pool).GetStateByID(r.Context(), created.StateID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
 	}
+	recordAudit(r.Context(), db.New(pool), actionCreate, entityState, row.StateID, nil, row)
+
+	invalidate(r.Context(), c, "states:*", "search:*")
+	writeJSON(w, http.StatusCreated, row)
 }
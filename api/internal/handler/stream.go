@@ -1,14 +1,19 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/middleware"
 	"github.com/brandon-relentnet/nationcam/api/internal/restreamer"
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // ── Request types ─────────────────────────────────────────────────────
@@ -16,6 +21,18 @@ import (
 type createStreamRequest struct {
 	Name    string `json:"name"`
 	RTSPURL string `json:"rtspUrl"`
+
+	// Optional catalog link: when StateID is set, a video playing the
+	// stream's HLS URL is created alongside it. Title defaults to Name.
+	StateID       int32  `json:"state_id"`
+	SublocationID *int32 `json:"sublocation_id"`
+	Title         string `json:"title"`
+}
+
+// createStreamResponse adds the linked catalog video, if one was created.
+type createStreamResponse struct {
+	restreamer.StreamResponse
+	Video *db.GetVideoByIDRow `json:"video,omitempty"`
 }
 
 // ── Handlers ──────────────────────────────────────────────────────────
@@ -23,7 +40,8 @@ type createStreamRequest struct {
 // CreateStream handles POST /streams — creates a new RTSP-to-HLS stream.
 // The process is created with the Restreamer UI naming convention so it
 // appears in the Restreamer dashboard and supports UI-based egress setup.
-func CreateStream(rc *restreamer.Client) http.HandlerFunc {
+// With state_id it also creates the catalog video for the stream.
+func CreateStream(pool *pgxpool.Pool, c *cache.Cache, rc *restreamer.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req createStreamRequest
 		if err := readJSON(r, &req); err != nil {
@@ -44,6 +62,30 @@ func CreateStream(rc *restreamer.Client) http.HandlerFunc {
 			return
 		}
 
+		if req.StateID == 0 && (req.SublocationID != nil || req.Title != "") {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "state_id is required to create a video"})
+			return
+		}
+		// Check where the video goes before creating a process for it.
+		if req.StateID != 0 {
+			place, err := db.New(pool).CheckVideoPlace(r.Context(), db.CheckVideoPlaceParams{
+				StateID:       req.StateID,
+				SublocationID: req.SublocationID,
+			})
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			if !place.StateOk {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "state not found"})
+				return
+			}
+			if !place.SublocationOk {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "sublocation not found in that state"})
+				return
+			}
+		}
+
 		// Generate a UUID for the process (matches Restreamer UI convention).
 		uuid, err := restreamer.NewUUID()
 		if err != nil {
@@ -74,12 +116,39 @@ func CreateStream(rc *restreamer.Client) http.HandlerFunc {
 				"processId", processID, "error", err)
 		}
 
-		writeJSON(w, http.StatusCreated, restreamer.StreamResponse{
+		resp := createStreamResponse{StreamResponse: restreamer.StreamResponse{
 			StreamID: uuid,
 			Name:     name,
 			HlsURL:   rc.HLSURL(uuid),
 			Status:   "created",
-		})
+		}}
+		if req.StateID != 0 {
+			title := strings.TrimSpace(req.Title)
+			if title == "" {
+				title = name
+			}
+			video, err := createStreamVideo(r.Context(), db.New(pool), uuid, resp.HlsURL, title, req.StateID, req.SublocationID)
+			if err != nil {
+				// Don't leave an orphaned stream behind for a video that failed.
+				slog.Error("create stream video failed", "processId", processID, "error", err)
+				if derr := rc.DeleteProcess(r.Context(), processID); derr != nil {
+					slog.Error("cleanup after failed stream video", "processId", processID, "error", derr)
+				}
+				status := http.StatusInternalServerError
+				if isForeignKeyViolation(err) {
+					// The state or sublocation was deleted since the check.
+					status = http.StatusBadRequest
+				}
+				writeJSON(w, status, map[string]string{"error": "could not create video: " + err.Error()})
+				return
+			}
+			resp.Video = &video
+			invalidate(r.Context(), c, "videos:*", "states:*", "sublocations:*", "search:*")
+		}
+
+		// The snapshot deliberately omits the RTSP URL, which may carry credentials.
+		recordAudit(r.Context(), db.New(pool), actionCreate, entityStream, uuid, nil, resp.StreamResponse)
+		writeJSON(w, http.StatusCreated, resp)
 	}
 }
 
@@ -149,12 +218,23 @@ func GetStream(rc *restreamer.Client) http.HandlerFunc {
 	}
 }
 
-// DeleteStream handles DELETE /streams/{id} — removes a stream.
-func DeleteStream(rc *restreamer.Client) http.HandlerFunc {
+// DeleteStream handles DELETE /streams/{id}?video=deactivate|delete — removes
+// a stream. Videos linked to it are archived (default) or moved to the trash.
+func DeleteStream(pool *pgxpool.Pool, c *cache.Cache, rc *restreamer.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		uuid := chi.URLParam(r, "id")
 		processID := restreamer.IngestProcessID(uuid)
 
+		videoMode := r.URL.Query().Get("video")
+		switch videoMode {
+		case "":
+			videoMode = "deactivate"
+		case "deactivate", "delete":
+		default:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "video must be deactivate or delete"})
+			return
+		}
+
 		if err := rc.DeleteProcess(r.Context(), processID); err != nil {
 			status, msg := mapRestreamerError(err)
 			writeJSON(w, status, map[string]string{"error": msg})
@@ -163,13 +243,21 @@ func DeleteStream(rc *restreamer.Client) http.HandlerFunc {
 
 		// Also try to delete the snapshot process (best-effort).
 		_ = rc.DeleteProcess(r.Context(), processID+"_snapshot")
+		recordAudit(r.Context(), db.New(pool), actionDelete, entityStream, uuid, nil, nil)
+
+		if err := unlinkStreamVideos(r.Context(), db.New(pool), uuid, videoMode); err != nil {
+			// The stream is gone either way; report the drift rather than fail.
+			slog.Error("update videos for deleted stream failed", "processId", processID, "error", err)
+		} else {
+			invalidate(r.Context(), c, "videos:*", "states:*", "sublocations:*", "tags:*", "search:*")
+		}
 
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
 
 // RestartStream handles POST /streams/{id}/restart — stops then starts a stream.
-func RestartStream(rc *restreamer.Client) http.HandlerFunc {
+func RestartStream(pool *pgxpool.Pool, rc *restreamer.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		uuid := chi.URLParam(r, "id")
 		processID := restreamer.IngestProcessID(uuid)
@@ -191,6 +279,7 @@ func RestartStream(rc *restreamer.Client) http.HandlerFunc {
 			writeJSON(w, status, map[string]string{"error": msg})
 			return
 		}
+		recordAudit(r.Context(), db.New(pool), actionRestart, entityStream, uuid, nil, nil)
 
 		writeJSON(w, http.StatusOK, restreamer.StreamResponse{
 			StreamID: uuid,
@@ -255,3 +344,58 @@ func mapRestreamerError(err error) (int, string) {
 	// Connection errors (not a restreamer.Error) indicate the service is down.
 	return http.StatusBadGateway, "Restreamer service unavailable"
 }
+
+// createStreamVideo creates the catalog video for a new stream and returns
+// it in its rich form.
+func createStreamVideo(ctx context.Context, q *db.Queries, uuid, hlsURL, title string, stateID int32, sublocationID *int32) (db.GetVideoByIDRow, error) {
+	created, err := q.CreateVideo(ctx, db.CreateVideoParams{
+		Title:         title,
+		Src:           hlsURL,
+		Type:          "application/x-mpegURL",
+		StateID:       stateID,
+		SublocationID: sublocationID,
+		Status:        statusPublished,
+		CreatedBy:     middleware.UserID(ctx),
+		StreamID:      &uuid,
+	})
+	if err != nil {
+		return db.GetVideoByIDRow{}, err
+	}
+
+	row, err := q.GetVideoByID(ctx, created.VideoID)
+	if err != nil {
+		return db.GetVideoByIDRow{}, err
+	}
+	recordAudit(ctx, q, actionCreate, entityVideo, row.VideoID, nil, row)
+	return row, nil
+}
+
+// unlinkStreamVideos archives (mode "deactivate") or trashes (mode
+// "delete") the videos fed by a deleted stream.
+func unlinkStreamVideos(ctx context.Context, q *db.Queries, uuid, mode string) error {
+	if mode == "delete" {
+		ids, err := q.SoftDeleteVideosByStream(ctx, &uuid)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			recordAudit(ctx, q, actionDelete, entityVideo, id, nil, nil)
+		}
+		return nil
+	}
+
+	rows, err := q.DeactivateVideosByStream(ctx, &uuid)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		after, err := q.GetVideoByID(ctx, row.VideoID)
+		if err != nil {
+			return err
+		}
+		before := after
+		before.Status = row.PreviousStatus
+		recordAudit(ctx, q, actionUpdate, entityVideo, row.VideoID, before, after)
+	}
+	return nil
+}
@@ -1,13 +1,15 @@
 package handler
 
 import (
-	"log/slog"
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/brandon-relentnet/nationcam/api/internal/cache"
 	"github.com/brandon-relentnet/nationcam/api/internal/db"
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -35,24 +37,84 @@ func ListSublocationsByState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFun
 	}
 }
 
-// GetSublocation handles GET /sublocations/{slug}.
+// sublocationDetail is a sublocation with its breadcrumb trail of ancestors,
+// root first and not including itself.
+type sublocationDetail struct {
+	db.GetSublocationBySlugRow
+	Breadcrumbs []db.ListSublocationAncestorsRow `json:"breadcrumbs"`
+}
+
+// GetSublocation handles GET /sublocations/{slug}?state= — slugs are unique
+// per state, so state (a state slug) picks which. A retired slug gets a 301
+// naming the current one.
 func GetSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		slug := chi.URLParam(r, "slug")
-		key := "sublocations:slug:" + slug
+		state := r.URL.Query().Get("state")
+		key := "sublocations:slug:" + slug + ":" + state
 
 		cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
-			row, err := db.New(pool).GetSublocationBySlug(r.Context(), slug)
+			q := db.New(pool)
+			ref := db.GetSublocationBySlugParams{Slug: slug, State: optionalParam(state)}
+			row, err := q.GetSublocationBySlug(r.Context(), ref)
+			if err != nil {
+				if canonical, err := q.GetSublocationSlugRedirect(r.Context(), db.GetSublocationSlugRedirectParams(ref)); err == nil {
+					writeSlugRedirect(w, r, "sublocation", canonical)
+					return
+				}
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "sublocation not found"})
+				return
+			}
+			crumbs, err := q.ListSublocationAncestors(r.Context(), row.SublocationID)
 			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, sublocationDetail{GetSublocationBySlugRow: row, Breadcrumbs: crumbs})
+		})(w, r)
+	}
+}
+
+// ListSublocationVideos handles GET /sublocations/{slug}/videos — listed
+// videos in the sublocation and every sublocation nested below it. state
+// picks the sublocation as for GetSublocation.
+func ListSublocationVideos(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slug := chi.URLParam(r, "slug")
+		state := r.URL.Query().Get("state")
+
+		// Keyed under videos: so video changes invalidate it; slug changes
+		// invalidate it too.
+		key := "videos:sublocation-tree:" + slug + ":" + state
+		cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
+			q := db.New(pool)
+			sub, err := q.GetSublocationBySlug(r.Context(), db.GetSublocationBySlugParams{
+				Slug:  slug,
+				State: optionalParam(state),
+			})
+			if errors.Is(err, pgx.ErrNoRows) {
 				writeJSON(w, http.StatusNotFound, map[string]string{"error": "sublocation not found"})
 				return
 			}
-			writeJSON(w, http.StatusOK, row)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			rows, err := q.ListVideosInSublocationTree(r.Context(), sub.SublocationID)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			out := make([]db.ListVideosRow, len(rows))
+			for i, row := range rows {
+				out[i] = db.ListVideosRow(row)
+			}
+			writeJSON(w, http.StatusOK, listedVideos(out, nil))
 		})(w, r)
 	}
 }
 
-// DeleteSublocation handles DELETE /sublocations/{id} — deletes a sublocation by ID (admin only).
+// DeleteSublocation handles DELETE /sublocations/{id} — moves a sublocation to the trash (admin only).
 func DeleteSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		idStr := chi.URLParam(r, "id")
@@ -62,49 +124,45 @@ func DeleteSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			return
 		}
 
-		if err := db.New(pool).DeleteSublocation(r.Context(), int32(id)); err != nil {
+		q := db.New(pool)
+		before, err := q.GetSublocationByID(r.Context(), int32(id))
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
 
-		if err := c.Invalidate(r.Context(), "sublocations:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "sublocations:*", "error", err)
-		}
-		if err := c.Invalidate(r.Context(), "videos:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "videos:*", "error", err)
+		children, err := q.CountSublocationChildren(r.Context(), int32(id))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
 		}
-		if err := c.Invalidate(r.Context(), "states:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "states:*", "error", err)
+		if children > 0 {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "move or delete the sublocations nested under it first"})
+			return
 		}
-		w.WriteHeader(http.StatusNoContent)
-	}
-}
-
-// ListSublocationsPaginated handles GET /sublocations?page=1&per_page=20 — paginated list.
-func ListSublocationsPaginated(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		limit, offset, page, perPage := parsePagination(r)
 
-		rows, err := db.New(pool).ListSublocationsPaginated(r.Context(), db.ListSublocationsPaginatedParams{
-			Limit:  limit,
-			Offset: offset,
-		})
+		n, err := q.SoftDeleteSublocation(r.Context(), int32(id))
 		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
-
-		var total int32
-		if len(rows) > 0 {
-			total = rows[0].TotalCount
+		if n == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "sublocation not found"})
+			return
 		}
+		recordAudit(r.Context(), q, actionDelete, entitySublocation, id, before, nil)
 
-		writeJSON(w, http.StatusOK, paginatedResponse{
-			Data:    rows,
-			Total:   total,
-			Page:    page,
-			PerPage: perPage,
-		})
+		invalidate(r.Context(), c, "sublocations:*", "videos:*", "states:*", "tags:*", "search:*")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ListSublocationsPaginated handles GET /sublocations/paginated?page=1&per_page=20
+// (or ?cursor=) — filtered and sorted per sublocationListSpec, e.g.
+// ?state_id=5&sort=state,name (admin only).
+func ListSublocationsPaginated(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		listPage(w, r, pool, sublocationListSpec, r.URL.Query())
 	}
 }
 
@@ -112,9 +170,14 @@ type updateSublocationRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	StateID     int32  `json:"state_id"`
+	// ParentID 0 makes the sublocation top-level; omitted keeps the current
+	// parent unless the sublocation moves to another state.
+	ParentID *int32 `json:"parent_id"`
 }
 
-// UpdateSublocation handles PUT /sublocations/{id} — updates a sublocation (admin only).
+// UpdateSublocation handles PUT /sublocations/{id} — updates a sublocation.
+// The new parent must be in the same state and not the sublocation itself or
+// one of its descendants (admin only).
 func UpdateSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		idStr := chi.URLParam(r, "id")
@@ -134,11 +197,60 @@ func UpdateSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			return
 		}
 
+		before, err := db.New(pool).GetSublocationByID(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "sublocation not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		parentID := before.ParentID
+		switch {
+		case req.ParentID != nil:
+			parentID = req.ParentID
+		case req.StateID != before.StateID:
+			parentID = nil
+		}
+		if parentID != nil && *parentID == 0 {
+			parentID = nil
+		}
+		if req.StateID != before.StateID {
+			// Children can't follow into another state, and can't stay
+			// behind under a parent in a different state.
+			children, err := db.New(pool).CountSublocationChildren(r.Context(), int32(id))
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			if children > 0 {
+				writeJSON(w, http.StatusConflict, map[string]string{"error": "move the sublocations nested under it before changing its state"})
+				return
+			}
+		}
+		if msg, err := validateSublocationParent(r.Context(), db.New(pool), int32(id), req.StateID, parentID); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		} else if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		slug, err := movedSublocationSlug(r.Context(), db.New(pool), before, req.StateID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
 		if err := db.New(pool).UpdateSublocation(r.Context(), db.UpdateSublocationParams{
 			SublocationID: int32(id),
 			Name:          req.Name,
 			Description:   req.Description,
 			StateID:       req.StateID,
+			ParentID:      parentID,
+			Slug:          slug,
 		}); err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
@@ -150,13 +262,10 @@ func UpdateSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
+		recordAudit(r.Context(), db.New(pool), actionUpdate, entitySublocation, id, before, row)
 
-		if err := c.Invalidate(r.Context(), "sublocations:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "sublocations:*", "error", err)
-		}
-		if err := c.Invalidate(r.Context(), "states:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "states:*", "error", err)
-		}
+		// Reparenting changes which videos roll up into each subtree.
+		invalidate(r.Context(), c, "sublocations:*", "videos:*", "states:*", "search:*")
 		writeJSON(w, http.StatusOK, row)
 	}
 }
@@ -165,9 +274,11 @@ type createSublocationRequest struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	StateID     int32  `json:"state_id"`
+	ParentID    *int32 `json:"parent_id"`
 }
 
-// CreateSublocation handles POST /sublocations (admin only).
+// CreateSublocation handles POST /sublocations — parent_id, if given, must be
+// a sublocation in the same state (admin only).
 func CreateSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req createSublocationRequest
@@ -179,11 +290,31 @@ func CreateSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name and state_id are required"})
 			return
 		}
+		if req.ParentID != nil && *req.ParentID == 0 {
+			req.ParentID = nil
+		}
+		if msg, err := validateSublocationParent(r.Context(), db.New(pool), 0, req.StateID, req.ParentID); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		} else if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
 
-		created, err := db.New(pool).CreateSublocation(r.Context(), db.CreateSublocationParams{
-			Name:        req.Name,
-			Description: req.Description,
-			StateID:     req.StateID,
+		var created db.CreateSublocationRow
+		err := retrySlugConflict(func() error {
+			slug, err := uniqueSublocationSlug(r.Context(), db.New(pool), req.StateID, req.Name, 0)
+			if err != nil {
+				return err
+			}
+			created, err = db.New(pool).CreateSublocation(r.Context(), db.CreateSublocationParams{
+				Name:        req.Name,
+				Description: req.Description,
+				StateID:     req.StateID,
+				ParentID:    req.ParentID,
+				Slug:        slug,
+			})
+			return err
 		})
 		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
@@ -196,13 +327,46 @@ func CreateSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
+		recordAudit(r.Context(), db.New(pool), actionCreate, entitySublocation, row.SublocationID, nil, row)
+
+		invalidate(r.Context(), c, "sublocations:*", "states:*", "search:*")
+		writeJSON(w, http.StatusCreated, row)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// validateSublocationParent returns an error message if parentID can't be the
+// parent of sublocation id (0 for a new one) in stateID, or "" if it can.
+// nil parentID (top level) is always valid.
+func validateSublocationParent(ctx context.Context, q *db.Queries, id, stateID int32, parentID *int32) (string, error) {
+	if parentID == nil {
+		return "", nil
+	}
+
+	del, err := q.GetSublocationDeletedAt(ctx, *parentID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && del.DeletedAt != nil) {
+		return "parent sublocation not found", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	parent, err := q.GetSublocationByID(ctx, *parentID)
+	if err != nil {
+		return "", err
+	}
+	if parent.StateID != stateID {
+		return "parent sublocation must be in the same state", nil
+	}
 
-		if err := c.Invalidate(r.Context(), "sublocations:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "sublocations:*", "error", err)
+	if id != 0 {
+		cycle, err := q.IsSublocationInSubtree(ctx, db.IsSublocationInSubtreeParams{Root: id, Candidate: *parentID})
+		if err != nil {
+			return "", err
 		}
-		if err := c.Invalidate(r.Context(), "states:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "states:*", "error", err)
+		if cycle {
+			return "a sublocation can't be nested under itself or its descendants", nil
 		}
-		writeJSON(w, http.StatusCreated, row)
 	}
+	return "", nil
 }
@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/brandon-relentnet/nationcam/api/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// maxSubmissionBody caps a public submission's request body.
+	maxSubmissionBody = 16 << 10
+	// maxDailySubmissions is how many submissions one client may send per
+	// day. Unlike the in-memory rate limit it survives restarts.
+	maxDailySubmissions = 10
+
+	submissionPending  = "pending"
+	submissionApproved = "approved"
+	submissionRejected = "rejected"
+)
+
+// submissionRequest is a visitor's proposed camera.
+type submissionRequest struct {
+	Title        string `json:"title"`
+	Src          string `json:"src"`
+	StateID      *int32 `json:"state_id"`
+	ContactName  string `json:"contact_name"`
+	ContactEmail string `json:"contact_email"`
+	Notes        string `json:"notes"`
+	// Website is a honeypot: the form hides it from people, so only bots
+	// fill it in.
+	Website string `json:"website"`
+}
+
+type rejectSubmissionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CreateSubmission handles POST /submissions — a visitor proposes a camera
+// for the moderation queue. Unauthenticated and rate limited per client;
+// honeypot hits get the same 202 as real submissions, and are dropped.
+func CreateSubmission(pool *pgxpool.Pool, clients *middleware.ClientHasher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSubmissionBody)
+		var req submissionRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		accepted := map[string]string{"status": submissionPending}
+		if req.Website != "" {
+			slog.Info("submission honeypot triggered", "client", clients.Hash(r))
+			writeJSON(w, http.StatusAccepted, accepted)
+			return
+		}
+		if msg := req.validate(); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		q := db.New(pool)
+		client := clients.Hash(r)
+		recent, err := q.CountRecentSubmissions(r.Context(), db.CountRecentSubmissionsParams{
+			ClientHash: client,
+			CreatedAt:  time.Now().Add(-24 * time.Hour),
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if recent >= maxDailySubmissions {
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "too many submissions today, try again tomorrow"})
+			return
+		}
+
+		_, err = q.CreateSubmission(r.Context(), db.CreateSubmissionParams{
+			Title:        req.Title,
+			Src:          req.Src,
+			StateID:      req.StateID,
+			ContactName:  emptyToNil(req.ContactName),
+			ContactEmail: req.ContactEmail,
+			Notes:        emptyToNil(req.Notes),
+			ClientHash:   client,
+		})
+		if isForeignKeyViolation(err) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "state not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusAccepted, accepted)
+	}
+}
+
+// ListSubmissions handles GET /admin/submissions — the moderation queue,
+// oldest first. ?status= is pending by default, or approved, rejected or
+// all. Paged by number or cursor; see parsePagination (admin only).
+func ListSubmissions(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, msg := parsePagination(r)
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+		status := r.URL.Query().Get("status")
+		switch status {
+		case "":
+			status = submissionPending
+		case submissionPending, submissionApproved, submissionRejected, "all":
+		default:
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "status must be pending, approved, rejected or all"})
+			return
+		}
+		var statusFilter *string
+		if status != "all" {
+			statusFilter = &status
+		}
+
+		q := db.New(pool)
+		rows, err := q.ListSubmissions(r.Context(), db.ListSubmissionsParams{
+			Status:     statusFilter,
+			CursorID:   cursorID[int32](p),
+			Backward:   p.backward(),
+			PageLimit:  p.limit(),
+			PageOffset: p.offset(),
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		resp := paginate(p, rows, func(row db.ListSubmissionsRow) pageCursor {
+			return pageCursor{ID: int64(row.SubmissionID)}
+		})
+		if p.WithTotal {
+			total, err := q.CountSubmissions(r.Context(), statusFilter)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			resp.Total = &total
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// GetSubmission handles GET /admin/submissions/{id} (admin only).
+func GetSubmission(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readSubmissionID(w, r)
+		if !ok {
+			return
+		}
+		row, err := db.New(pool).GetSubmission(r.Context(), id)
+		if err != nil {
+			writeSubmissionError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, row)
+	}
+}
+
+// ApproveSubmission handles POST /admin/submissions/{id}/approve — creates
+// the submission's video exactly as POST /videos would. The optional body
+// is a POST /videos body; fields it leaves out come from the submission
+// (admin only).
+func ApproveSubmission(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readSubmissionID(w, r)
+		if !ok {
+			return
+		}
+		before, err := db.New(pool).GetSubmission(r.Context(), id)
+		if err != nil {
+			writeSubmissionError(w, err)
+			return
+		}
+		if before.Status != submissionPending {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "submission is already " + before.Status})
+			return
+		}
+
+		req := createVideoRequest{Title: before.Title, Src: before.Src}
+		if before.StateID != nil {
+			req.StateID = *before.StateID
+		}
+		if err := readJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		camera, msg := req.validate()
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+		reviewer := middleware.UserID(r.Context())
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
+
+		videoID, err := createVideo(r.Context(), qtx, req, camera, reviewer)
+		if err != nil {
+			writeTagError(w, err)
+			return
+		}
+		n, err := qtx.ReviewSubmission(r.Context(), db.ReviewSubmissionParams{
+			Status:       submissionApproved,
+			VideoID:      &videoID,
+			ReviewedBy:   &reviewer,
+			SubmissionID: id,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if n == 0 {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "submission was reviewed meanwhile"})
+			return
+		}
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		q := db.New(pool)
+		video, err := q.GetVideoByID(r.Context(), videoID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		after, err := q.GetSubmission(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionCreate, entityVideo, video.VideoID, nil, video)
+		recordAudit(r.Context(), q, actionApprove, entitySubmission, id, before, after)
+
+		invalidate(r.Context(), c, "videos:*", "states:*", "tags:*", "search:*")
+		writeJSON(w, http.StatusCreated, map[string]any{"submission": after, "video": video})
+	}
+}
+
+// RejectSubmission handles POST /admin/submissions/{id}/reject — closes a
+// pending submission with an optional reason (admin only).
+func RejectSubmission(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := readSubmissionID(w, r)
+		if !ok {
+			return
+		}
+		var req rejectSubmissionRequest
+		if err := readJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+
+		q := db.New(pool)
+		before, err := q.GetSubmission(r.Context(), id)
+		if err != nil {
+			writeSubmissionError(w, err)
+			return
+		}
+		reviewer := middleware.UserID(r.Context())
+		n, err := q.ReviewSubmission(r.Context(), db.ReviewSubmissionParams{
+			Status:       submissionRejected,
+			RejectReason: emptyToNil(req.Reason),
+			ReviewedBy:   &reviewer,
+			SubmissionID: id,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if n == 0 {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "submission is already " + before.Status})
+			return
+		}
+
+		after, err := q.GetSubmission(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionReject, entitySubmission, id, before, after)
+		writeJSON(w, http.StatusOK, after)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// validate trims req and returns an error message if it is unusable.
+func (req *submissionRequest) validate() string {
+	req.Title = strings.TrimSpace(req.Title)
+	req.Src = strings.TrimSpace(req.Src)
+	req.ContactEmail = strings.TrimSpace(req.ContactEmail)
+
+	if req.Title == "" || req.Src == "" || req.ContactEmail == "" {
+		return "title, src and contact_email are required"
+	}
+	if len([]rune(req.Title)) > 200 {
+		return "title must be at most 200 characters"
+	}
+	u, err := url.Parse(req.Src)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(req.Src) > 2048 {
+		return "src must be an http or https URL"
+	}
+	if addr, err := mail.ParseAddress(req.ContactEmail); err != nil || addr.Address != req.ContactEmail {
+		return "contact_email must be an email address"
+	}
+	if len([]rune(req.ContactName)) > 200 || len([]rune(req.Notes)) > 2000 {
+		return "contact_name or notes is too long"
+	}
+	return ""
+}
+
+func readSubmissionID(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid submission id"})
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func writeSubmissionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "submission not found"})
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const tagsAllKey = "tags:all"
+
+// ListTags handles GET /tags — returns all tags with listed video counts (cached).
+func ListTags(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return cachedHandler(c, tagsAllKey, func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListTags(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, rows)
+	})
+}
+
+type tagRequest struct {
+	Name string `json:"name"`
+}
+
+// CreateTag handles POST /tags — creates a new tag (admin only).
+func CreateTag(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req tagRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		if req.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return
+		}
+
+		var created db.Tag
+		err := retrySlugConflict(func() error {
+			slug, err := uniqueTagSlug(r.Context(), db.New(pool), req.Name, 0)
+			if err != nil {
+				return err
+			}
+			created, err = db.New(pool).CreateTag(r.Context(), db.CreateTagParams{Name: req.Name, Slug: slug})
+			return err
+		})
+		if isUniqueViolation(err) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "a tag with that name already exists"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		row, err := db.New(pool).GetTagByID(r.Context(), created.TagID)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), db.New(pool), actionCreate, entityTag, row.TagID, nil, row)
+
+		// A new tag has no videos yet, so video lists are unaffected.
+		invalidate(r.Context(), c, "tags:*")
+		writeJSON(w, http.StatusCreated, row)
+	}
+}
+
+// UpdateTag handles PUT /tags/{id} — renames a tag (admin only).
+func UpdateTag(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil || id <= 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid tag id"})
+			return
+		}
+
+		var req tagRequest
+		if err := readJSON(r, &req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+			return
+		}
+		if req.Name == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "name is required"})
+			return
+		}
+
+		before, err := db.New(pool).GetTagByID(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "tag not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		if err := db.New(pool).UpdateTag(r.Context(), db.UpdateTagParams{
+			TagID: int32(id),
+			Name:  req.Name,
+		}); err != nil {
+			if isUniqueViolation(err) {
+				writeJSON(w, http.StatusConflict, map[string]string{"error": "a tag with that name already exists"})
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		row, err := db.New(pool).GetTagByID(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "tag not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), db.New(pool), actionUpdate, entityTag, id, before, row)
+
+		// Video rows embed their tags, and tag-filtered lists are keyed by slug.
+		invalidate(r.Context(), c, "tags:*", "videos:*")
+		writeJSON(w, http.StatusOK, row)
+	}
+}
+
+// DeleteTag handles DELETE /tags/{id} — deletes a tag and its video assignments (admin only).
+func DeleteTag(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idStr := chi.URLParam(r, "id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil || id <= 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid tag id"})
+			return
+		}
+
+		before, err := db.New(pool).GetTagByID(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "tag not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		if err := db.New(pool).DeleteTag(r.Context(), int32(id)); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), db.New(pool), actionDelete, entityTag, id, before, nil)
+
+		invalidate(r.Context(), c, "tags:*", "videos:*")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// errUnknownTag is returned by setVideoTags when a slug matches no tag.
+var errUnknownTag = errors.New("unknown tag")
+
+// listVideosByTags serves GET /videos?tag=beach&tag=surf[&tag_mode=any] —
+// listed videos with all (default) or any of the tags, optionally narrowed by
+// state_id/sublocation_id and daylight (cached).
+func listVideosByTags(pool *pgxpool.Pool, c *cache.Cache, w http.ResponseWriter, r *http.Request, tags []string, only *bool) {
+	q := r.URL.Query()
+
+	slugs := normalizeTagSlugs(tags)
+	if len(slugs) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "tag must not be empty"})
+		return
+	}
+
+	mode := q.Get("tag_mode")
+	minMatches := int32(len(slugs))
+	switch mode {
+	case "", "all":
+		mode = "all"
+	case "any":
+		minMatches = 1
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "tag_mode must be all or any"})
+		return
+	}
+
+	filter, msg := parseVideoFilter(q)
+	if msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+
+	// Sort a copy so ?tag=a&tag=b and ?tag=b&tag=a share a cache entry.
+	sorted := slices.Sorted(slices.Values(slugs))
+	key := "videos:tags:" + mode + ":" + strings.Join(sorted, ",") + ":" + filter.key() + daylightKey(only)
+
+	cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListVideosByTags(r.Context(), db.ListVideosByTagsParams{
+			StateID:       filter.stateID,
+			SublocationID: filter.sublocationID,
+			Tags:          slugs,
+			MinMatches:    minMatches,
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		out := make([]db.ListVideosRow, len(rows))
+		for i, row := range rows {
+			out[i] = db.ListVideosRow(row)
+		}
+		writeJSON(w, http.StatusOK, listedVideos(out, only))
+	})(w, r)
+}
+
+// setVideoTags replaces a video's tags with the given slugs using q, which
+// should be bound to the caller's transaction.
+func setVideoTags(ctx context.Context, q *db.Queries, videoID int32, tags []string) error {
+	slugs := normalizeTagSlugs(tags)
+
+	if len(slugs) > 0 {
+		found, err := q.CountTagsBySlug(ctx, slugs)
+		if err != nil {
+			return err
+		}
+		if int(found) != len(slugs) {
+			return errUnknownTag
+		}
+	}
+
+	if err := q.ClearVideoTags(ctx, videoID); err != nil {
+		return err
+	}
+	if len(slugs) == 0 {
+		return nil
+	}
+	return q.AddVideoTags(ctx, db.AddVideoTagsParams{VideoID: videoID, Slugs: slugs})
+}
+
+// writeTagError maps a setVideoTags error to a response.
+func writeTagError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errUnknownTag) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unknown tag (create it via POST /tags first)"})
+		return
+	}
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
+
+// normalizeTagSlugs lowercases and trims slugs, dropping blanks and duplicates.
+func normalizeTagSlugs(tags []string) []string {
+	slugs := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		slugs = append(slugs, t)
+	}
+	return slugs
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	errNotFound           = errors.New("not found")
+	errNotInTrash         = errors.New("not in the trash")
+	errStateInTrash       = errors.New("state is in the trash")
+	errSublocationInTrash = errors.New("sublocation is in the trash")
+	errParentInTrash      = errors.New("parent sublocation is in the trash")
+)
+
+// ListTrash handles GET /trash — soft-deleted states, sublocations and videos,
+// most recently deleted first (admin only).
+func ListTrash(pool *pgxpool.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListTrash(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, rows)
+	}
+}
+
+// RestoreState handles POST /states/{id}/restore — takes a state out of the
+// trash along with the sublocations and videos deleted with it (admin only).
+func RestoreState(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := restoreID(w, r, "state")
+		if !ok {
+			return
+		}
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
+
+		if err := restoreState(r.Context(), qtx, id); err != nil {
+			writeTrashError(w, "state", err)
+			return
+		}
+		row, err := qtx.GetStateByID(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAuditTx(r.Context(), tx, actionRestore, entityState, id, nil, row)
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		invalidate(r.Context(), c, "states:*", "sublocations:*", "videos:*", "tags:*", "search:*")
+		writeJSON(w, http.StatusOK, row)
+	}
+}
+
+// RestoreSublocation handles POST /sublocations/{id}/restore — takes a
+// sublocation out of the trash. Its state and parent must not be in the trash
+// (admin only).
+func RestoreSublocation(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := restoreID(w, r, "sublocation")
+		if !ok {
+			return
+		}
+
+		q := db.New(pool)
+		if err := restoreSublocation(r.Context(), q, id); err != nil {
+			writeTrashError(w, "sublocation", err)
+			return
+		}
+
+		row, err := q.GetSublocationByID(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionRestore, entitySublocation, id, nil, row)
+
+		invalidate(r.Context(), c, "sublocations:*", "videos:*", "states:*", "search:*")
+		writeJSON(w, http.StatusOK, row)
+	}
+}
+
+// RestoreVideo handles POST /videos/{id}/restore — takes a video out of the
+// trash. Its state and sublocation must not be in the trash (admin only).
+func RestoreVideo(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := restoreID(w, r, "video")
+		if !ok {
+			return
+		}
+
+		q := db.New(pool)
+		if err := restoreVideo(r.Context(), q, id); err != nil {
+			writeTrashError(w, "video", err)
+			return
+		}
+
+		row, err := q.GetVideoByID(r.Context(), id)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		recordAudit(r.Context(), q, actionRestore, entityVideo, id, nil, row)
+
+		invalidate(r.Context(), c, "videos:*", "states:*", "sublocations:*", "tags:*", "search:*")
+		writeJSON(w, http.StatusOK, row)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// trashState soft-deletes the state with the given slug together with its
+// sublocations and videos, and returns its ID. q should be bound to a
+// transaction so the three updates land together.
+func trashState(ctx context.Context, q *db.Queries, slug string) (int32, error) {
+	deleted, err := q.SoftDeleteState(ctx, slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, errNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Children share the state's deleted_at so restoreState can find them.
+	if err := q.SoftDeleteSublocationsByState(ctx, db.SoftDeleteSublocationsByStateParams{
+		StateID:   deleted.StateID,
+		DeletedAt: deleted.DeletedAt,
+	}); err != nil {
+		return 0, err
+	}
+	if err := q.SoftDeleteVideosByState(ctx, db.SoftDeleteVideosByStateParams{
+		StateID:   deleted.StateID,
+		DeletedAt: deleted.DeletedAt,
+	}); err != nil {
+		return 0, err
+	}
+	return deleted.StateID, nil
+}
+
+// restoreState undoes trashState. q should be bound to a transaction.
+func restoreState(ctx context.Context, q *db.Queries, id int32) error {
+	deletedAt, err := q.GetStateDeletedAt(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if deletedAt == nil {
+		return errNotInTrash
+	}
+
+	if err := q.RestoreState(ctx, id); err != nil {
+		return err
+	}
+	if err := q.RestoreSublocationsByState(ctx, db.RestoreSublocationsByStateParams{
+		StateID:   id,
+		DeletedAt: *deletedAt,
+	}); err != nil {
+		return err
+	}
+	return q.RestoreVideosByState(ctx, db.RestoreVideosByStateParams{
+		StateID:   id,
+		DeletedAt: *deletedAt,
+	})
+}
+
+// restoreSublocation takes a sublocation out of the trash if its state and
+// parent are live.
+func restoreSublocation(ctx context.Context, q *db.Queries, id int32) error {
+	del, err := q.GetSublocationDeletedAt(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if del.DeletedAt == nil {
+		return errNotInTrash
+	}
+	if del.StateDeletedAt != nil {
+		return errStateInTrash
+	}
+	if del.ParentDeletedAt != nil {
+		return errParentInTrash
+	}
+	return q.RestoreSublocation(ctx, id)
+}
+
+// restoreVideo takes a video out of the trash if its state and sublocation are live.
+func restoreVideo(ctx context.Context, q *db.Queries, id int32) error {
+	del, err := q.GetVideoDeletedAt(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if del.DeletedAt == nil {
+		return errNotInTrash
+	}
+	if del.StateDeletedAt != nil {
+		return errStateInTrash
+	}
+	if del.SublocationDeletedAt != nil {
+		return errSublocationInTrash
+	}
+	return q.RestoreVideo(ctx, id)
+}
+
+// writeTrashError maps trash/restore errors to responses.
+func writeTrashError(w http.ResponseWriter, kind string, err error) {
+	switch {
+	case errors.Is(err, errNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": kind + " not found"})
+	case errors.Is(err, errNotInTrash):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": kind + " is not in the trash"})
+	case errors.Is(err, errStateInTrash):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "restore the " + kind + "'s state first"})
+	case errors.Is(err, errSublocationInTrash):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "restore the " + kind + "'s sublocation first"})
+	case errors.Is(err, errParentInTrash):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "restore the " + kind + "'s parent first"})
+	case isUniqueViolation(err):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "another " + kind + " has taken its name; rename one of them first"})
+	default:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// restoreID parses the {id} URL param, writing a 400 if it is invalid.
+func restoreID(w http.ResponseWriter, r *http.Request, kind string) (int32, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid " + kind + " id"})
+		return 0, false
+	}
+	return int32(id), true
+}
@@ -1,72 +1,79 @@
 package handler
 
 import (
-	"log/slog"
+	"context"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/brandon-relentnet/nationcam/api/internal/cache"
 	"github.com/brandon-relentnet/nationcam/api/internal/db"
 	"github.com/brandon-relentnet/nationcam/api/internal/middleware"
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// ListVideos handles GET /videos with optional query params: state_id, sublocation_id.
+// ListVideos handles GET /videos with optional query params: bbox, tag,
+// state_id, sublocation_id and daylight.
 func ListVideos(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		q := r.URL.Query()
 
-		if subIDStr := q.Get("sublocation_id"); subIDStr != "" {
-			subID, err := strconv.Atoi(subIDStr)
-			if err != nil {
-				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid sublocation_id"})
-				return
-			}
-			key := "videos:sublocation:" + subIDStr
-			cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
-				subID32 := int32(subID)
-				rows, err := db.New(pool).ListVideosBySublocation(r.Context(), &subID32)
-				if err != nil {
-					writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-					return
-				}
-				writeJSON(w, http.StatusOK, rows)
-			})(w, r)
+		only, msg := parseDaylightFilter(q)
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
 			return
 		}
 
-		if stateIDStr := q.Get("state_id"); stateIDStr != "" {
-			stateID, err := strconv.Atoi(stateIDStr)
-			if err != nil {
-				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid state_id"})
-				return
-			}
-			key := "videos:state:" + stateIDStr
-			cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
-				rows, err := db.New(pool).ListVideosByState(r.Context(), int32(stateID))
-				if err != nil {
-					writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-					return
-				}
-				writeJSON(w, http.StatusOK, rows)
-			})(w, r)
+		if tags := q["tag"]; len(tags) > 0 {
+			listVideosByTags(pool, c, w, r, tags, only)
 			return
 		}
 
-		// No filter — return all active videos.
-		cachedHandler(c, "videos:all", func(w http.ResponseWriter, r *http.Request) {
-			rows, err := db.New(pool).ListVideos(r.Context())
+		if bbox := q.Get("bbox"); bbox != "" {
+			listVideosInBBox(pool, c, w, r, bbox, only)
+			return
+		}
+
+		filter, msg := parseVideoFilter(q)
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+
+		key := "videos:" + filter.key() + daylightKey(only)
+		cachedHandler(c, key, func(w http.ResponseWriter, r *http.Request) {
+			rows, err := filter.list(r.Context(), db.New(pool))
 			if err != nil {
 				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 				return
 			}
-			writeJSON(w, http.StatusOK, rows)
+			writeJSON(w, http.StatusOK, listedVideos(rows, only))
 		})(w, r)
 	}
 }
 
-// DeleteVideo handles DELETE /videos/{id} — deletes a video by ID (admin only).
+// ListFeaturedVideos handles GET /videos/featured — listed videos flagged
+// featured, in admin order, for the homepage hero (cached).
+func ListFeaturedVideos(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
+	return cachedHandler(c, "videos:featured", func(w http.ResponseWriter, r *http.Request) {
+		rows, err := db.New(pool).ListFeaturedVideos(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		out := make([]db.ListVideosRow, len(rows))
+		for i, row := range rows {
+			out[i] = db.ListVideosRow(row)
+		}
+		writeJSON(w, http.StatusOK, listedVideos(out, nil))
+	})
+}
+
+// DeleteVideo handles DELETE /videos/{id} — moves a video to the trash (admin only).
 func DeleteVideo(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		idStr := chi.URLParam(r, "id")
@@ -76,49 +83,35 @@ func DeleteVideo(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			return
 		}
 
-		if err := db.New(pool).DeleteVideo(r.Context(), int32(id)); err != nil {
+		q := db.New(pool)
+		before, err := q.GetVideoByID(r.Context(), int32(id))
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
 
-		if err := c.Invalidate(r.Context(), "videos:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "videos:*", "error", err)
-		}
-		if err := c.Invalidate(r.Context(), "states:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "states:*", "error", err)
+		n, err := q.SoftDeleteVideo(r.Context(), int32(id))
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
 		}
-		if err := c.Invalidate(r.Context(), "sublocations:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "sublocations:*", "error", err)
+		if n == 0 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "video not found"})
+			return
 		}
+		recordAudit(r.Context(), q, actionDelete, entityVideo, id, before, nil)
+
+		invalidate(r.Context(), c, "videos:*", "states:*", "sublocations:*", "tags:*", "search:*")
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
 
-// ListVideosPaginated handles GET /videos/paginated?page=1&per_page=20 — paginated list.
+// ListVideosPaginated handles GET /videos/paginated?page=1&per_page=20 (or
+// ?cursor=) — filtered and sorted per videoListSpec, e.g.
+// ?status=draft,maintenance&title=cam&sort=-updated_at,title (admin only).
 func ListVideosPaginated(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		limit, offset, page, perPage := parsePagination(r)
-
-		rows, err := db.New(pool).ListVideosPaginated(r.Context(), db.ListVideosPaginatedParams{
-			Limit:  limit,
-			Offset: offset,
-		})
-		if err != nil {
-			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
-			return
-		}
-
-		var total int32
-		if len(rows) > 0 {
-			total = rows[0].TotalCount
-		}
-
-		writeJSON(w, http.StatusOK, paginatedResponse{
-			Data:    rows,
-			Total:   total,
-			Page:    page,
-			PerPage: perPage,
-		})
+		listPage(w, r, pool, videoListSpec, r.URL.Query())
 	}
 }
 
@@ -129,6 +122,21 @@ type updateVideoRequest struct {
 	StateID       int32  `json:"state_id"`
 	SublocationID *int32 `json:"sublocation_id"`
 	Status        string `json:"status"`
+	// The position fields keep their current values when left out; null
+	// clears them.
+	Latitude  nullable[float64] `json:"latitude"`
+	Longitude nullable[float64] `json:"longitude"`
+	Heading   nullable[float64] `json:"heading"`
+	Elevation nullable[float64] `json:"elevation"`
+	// Status defaults to the current status; see videoTransitions.
+	// The schedule, like the position, is kept unless sent; null clears it.
+	PublishAt   nullable[time.Time] `json:"publish_at"`
+	UnpublishAt nullable[time.Time] `json:"unpublish_at"`
+	// Featured defaults to the current flag.
+	Featured *bool `json:"featured"`
+	cameraRequest
+	// Tags replaces the video's tag slugs when present; omit to leave them unchanged.
+	Tags *[]string `json:"tags"`
 }
 
 // UpdateVideo handles PUT /videos/{id} — updates a video (admin only).
@@ -153,11 +161,54 @@ func UpdateVideo(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 		if req.Type == "" {
 			req.Type = "application/x-mpegURL"
 		}
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
+
+		before, err := qtx.GetVideoByID(r.Context(), int32(id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "video not found"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		req.Status = normalizeVideoStatus(req.Status)
 		if req.Status == "" {
-			req.Status = "active"
+			req.Status = before.Status
+		}
+		if msg := validateVideoTransition(before.Status, req.Status); msg != "" {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": msg})
+			return
+		}
+		if req.Featured == nil {
+			req.Featured = &before.Featured
+		}
+		lat, lon := req.Latitude.or(before.Latitude), req.Longitude.or(before.Longitude)
+		heading, elevation := req.Heading.or(before.Heading), req.Elevation.or(before.Elevation)
+		if msg := validateGeo(lat, lon, heading); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+		publishAt, unpublishAt := req.PublishAt.or(before.PublishAt), req.UnpublishAt.or(before.UnpublishAt)
+		if msg := validateSchedule(publishAt, unpublishAt); msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
+		}
+		camera, msg := req.merge(videoCameraMetadata(before))
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+			return
 		}
 
-		if err := db.New(pool).UpdateVideo(r.Context(), db.UpdateVideoParams{
+		if err := qtx.UpdateVideo(r.Context(), db.UpdateVideoParams{
 			VideoID:       int32(id),
 			Title:         req.Title,
 			Src:           req.Src,
@@ -165,10 +216,34 @@ func UpdateVideo(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			StateID:       req.StateID,
 			SublocationID: req.SublocationID,
 			Status:        req.Status,
+			Latitude:      lat,
+			Longitude:     lon,
+			Heading:       heading,
+			Elevation:     elevation,
+			PublishAt:     publishAt,
+			UnpublishAt:   unpublishAt,
+			Featured:      *req.Featured,
+			Timezone:      camera.Timezone,
+			FieldOfView:   camera.FieldOfView,
+			CameraModel:   camera.CameraModel,
+			Firmware:      camera.Firmware,
+			OwnerContact:  camera.OwnerContact,
+			InstallDate:   camera.InstallDate,
+			Notes:         camera.Notes,
 		}); err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
+		if req.Tags != nil {
+			if err := setVideoTags(r.Context(), qtx, int32(id), *req.Tags); err != nil {
+				writeTagError(w, err)
+				return
+			}
+		}
+		if err := tx.Commit(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
 
 		// Re-fetch to return the updated rich type.
 		row, err := db.New(pool).GetVideoByID(r.Context(), int32(id))
@@ -176,24 +251,29 @@ func UpdateVideo(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
+		recordAudit(r.Context(), db.New(pool), actionUpdate, entityVideo, id, before, row)
 
-		if err := c.Invalidate(r.Context(), "videos:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "videos:*", "error", err)
-		}
-		if err := c.Invalidate(r.Context(), "states:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "states:*", "error", err)
-		}
+		invalidate(r.Context(), c, "videos:*", "states:*", "tags:*", "search:*")
 		writeJSON(w, http.StatusOK, row)
 	}
 }
 
 type createVideoRequest struct {
-	Title          string `json:"title"`
-	Src            string `json:"src"`
-	Type           string `json:"type"`
-	StateID        int32  `json:"state_id"`
-	SublocationID  *int32 `json:"sublocation_id"`
-	Status         string `json:"status"`
+	Title         string     `json:"title"`
+	Src           string     `json:"src"`
+	Type          string     `json:"type"`
+	StateID       int32      `json:"state_id"`
+	SublocationID *int32     `json:"sublocation_id"`
+	Status        string     `json:"status"`
+	Latitude      *float64   `json:"latitude"`
+	Longitude     *float64   `json:"longitude"`
+	Heading       *float64   `json:"heading"`
+	Elevation     *float64   `json:"elevation"`
+	PublishAt     *time.Time `json:"publish_at"`
+	UnpublishAt   *time.Time `json:"unpublish_at"`
+	Featured      bool       `json:"featured"`
+	cameraRequest
+	Tags []string `json:"tags"`
 }
 
 // CreateVideo handles POST /videos (admin only).
@@ -204,44 +284,175 @@ func CreateVideo(pool *pgxpool.Pool, c *cache.Cache) http.HandlerFunc {
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
 			return
 		}
-		if req.Title == "" || req.Src == "" || req.StateID == 0 {
-			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "title, src, and state_id are required"})
+		camera, msg := req.validate()
+		if msg != "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
 			return
 		}
-		if req.Type == "" {
-			req.Type = "application/x-mpegURL"
-		}
-		if req.Status == "" {
-			req.Status = "active"
+
+		tx, err := pool.Begin(r.Context())
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
 		}
+		defer tx.Rollback(r.Context())
+		qtx := db.New(pool).WithTx(tx)
 
-		created, err := db.New(pool).CreateVideo(r.Context(), db.CreateVideoParams{
-			Title:         req.Title,
-			Src:           req.Src,
-			Type:          req.Type,
-			StateID:       req.StateID,
-			SublocationID: req.SublocationID,
-			Status:        req.Status,
-			CreatedBy:     middleware.UserID(r.Context()),
-		})
+		videoID, err := createVideo(r.Context(), qtx, req, camera, middleware.UserID(r.Context()))
 		if err != nil {
+			writeTagError(w, err)
+			return
+		}
+		if err := tx.Commit(r.Context()); err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
 
 		// Re-fetch with JOINs to return the rich type (includes state_name, sublocation_name).
-		row, err := db.New(pool).GetVideoByID(r.Context(), created.VideoID)
+		row, err := db.New(pool).GetVideoByID(r.Context(), videoID)
 		if err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 			return
 		}
+		recordAudit(r.Context(), db.New(pool), actionCreate, entityVideo, row.VideoID, nil, row)
 
-		if err := c.Invalidate(r.Context(), "videos:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "videos:*", "error", err)
+		invalidate(r.Context(), c, "videos:*", "states:*", "tags:*", "search:*")
+		writeJSON(w, http.StatusCreated, row)
+	}
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// validate fills in req's defaults and checks it, returning its camera
+// metadata or an error message.
+func (req *createVideoRequest) validate() (cameraMetadata, string) {
+	if req.Title == "" || req.Src == "" || req.StateID == 0 {
+		return cameraMetadata{}, "title, src, and state_id are required"
+	}
+	if msg := validateGeo(req.Latitude, req.Longitude, req.Heading); msg != "" {
+		return cameraMetadata{}, msg
+	}
+	if msg := validateSchedule(req.PublishAt, req.UnpublishAt); msg != "" {
+		return cameraMetadata{}, msg
+	}
+	if req.Type == "" {
+		req.Type = "application/x-mpegURL"
+	}
+	req.Status = normalizeVideoStatus(req.Status)
+	if req.Status == "" {
+		req.Status = defaultVideoStatus(req.PublishAt)
+	}
+	if msg := validateInitialStatus(req.Status); msg != "" {
+		return cameraMetadata{}, msg
+	}
+	return req.merge(cameraMetadata{})
+}
+
+// createVideo inserts a validated req and its tags with q, returning the new
+// video's ID. Errors are for writeTagError.
+func createVideo(ctx context.Context, q *db.Queries, req createVideoRequest, camera cameraMetadata, createdBy string) (int32, error) {
+	created, err := q.CreateVideo(ctx, db.CreateVideoParams{
+		Title:         req.Title,
+		Src:           req.Src,
+		Type:          req.Type,
+		StateID:       req.StateID,
+		SublocationID: req.SublocationID,
+		Status:        req.Status,
+		CreatedBy:     createdBy,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		Heading:       req.Heading,
+		Elevation:     req.Elevation,
+		PublishAt:     req.PublishAt,
+		UnpublishAt:   req.UnpublishAt,
+		Featured:      req.Featured,
+		Timezone:      camera.Timezone,
+		FieldOfView:   camera.FieldOfView,
+		CameraModel:   camera.CameraModel,
+		Firmware:      camera.Firmware,
+		OwnerContact:  camera.OwnerContact,
+		InstallDate:   camera.InstallDate,
+		Notes:         camera.Notes,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(req.Tags) > 0 {
+		if err := setVideoTags(ctx, q, created.VideoID, req.Tags); err != nil {
+			return 0, err
 		}
-		if err := c.Invalidate(r.Context(), "states:*"); err != nil {
-			slog.Warn("cache invalidation failed", "pattern", "states:*", "error", err)
+	}
+	return created.VideoID, nil
+}
+
+// videoFilter is the optional state_id/sublocation_id filter shared with ListVideos.
+// sublocation_id takes precedence when both are given.
+type videoFilter struct {
+	stateID       *int32
+	sublocationID *int32
+}
+
+func parseVideoFilter(q url.Values) (videoFilter, string) {
+	var f videoFilter
+	if s := q.Get("sublocation_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return f, "invalid sublocation_id"
 		}
-		writeJSON(w, http.StatusCreated, row)
+		id32 := int32(id)
+		f.sublocationID = &id32
+		return f, ""
+	}
+	if s := q.Get("state_id"); s != "" {
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return f, "invalid state_id"
+		}
+		id32 := int32(id)
+		f.stateID = &id32
+	}
+	return f, ""
+}
+
+// key returns the cache key suffix for the filter, e.g. "all" or "state:3".
+func (f videoFilter) key() string {
+	switch {
+	case f.sublocationID != nil:
+		return "sublocation:" + strconv.Itoa(int(*f.sublocationID))
+	case f.stateID != nil:
+		return "state:" + strconv.Itoa(int(*f.stateID))
+	default:
+		return "all"
+	}
+}
+
+// list runs the ListVideos* query matching the filter. The row types are
+// identical, so they are converted to ListVideosRow.
+func (f videoFilter) list(ctx context.Context, q *db.Queries) ([]db.ListVideosRow, error) {
+	switch {
+	case f.sublocationID != nil:
+		rows, err := q.ListVideosBySublocation(ctx, f.sublocationID)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]db.ListVideosRow, len(rows))
+		for i, row := range rows {
+			out[i] = db.ListVideosRow(row)
+		}
+		return out, nil
+
+	case f.stateID != nil:
+		rows, err := q.ListVideosByState(ctx, *f.stateID)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]db.ListVideosRow, len(rows))
+		for i, row := range rows {
+			out[i] = db.ListVideosRow(row)
+		}
+		return out, nil
+
+	default:
+		return q.ListVideos(ctx)
 	}
 }
@@ -0,0 +1,58 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PurgeTrash hard-deletes states, sublocations and videos that have been in
+// the trash longer than retention, then repeats every interval until ctx is
+// cancelled. It is safe to run on every replica: the DELETEs are idempotent.
+func PurgeTrash(ctx context.Context, pool *pgxpool.Pool, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := purgeOnce(ctx, pool, time.Now().Add(-retention)); err != nil {
+			slog.Error("trash purge failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purgeOnce(ctx context.Context, pool *pgxpool.Pool, cutoff time.Time) error {
+	q := db.New(pool)
+
+	// Children first so the counts reflect what was actually purged rather
+	// than what ON DELETE CASCADE removed implicitly.
+	videos, err := q.PurgeVideos(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	sublocations, err := q.PurgeSublocations(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	states, err := q.PurgeStates(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	if videos+sublocations+states > 0 {
+		slog.Info("trash purged",
+			"videos", videos,
+			"sublocations", sublocations,
+			"states", states,
+		)
+	}
+	return nil
+}
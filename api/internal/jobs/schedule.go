@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PublishScheduled publishes drafts whose publish_at has passed and archives
+// listed videos whose unpublish_at has passed, then repeats every interval
+// until ctx is cancelled. Like PurgeTrash it is safe on every replica.
+func PublishScheduled(ctx context.Context, pool *pgxpool.Pool, c *cache.Cache, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := publishOnce(ctx, pool, c, time.Now()); err != nil {
+			slog.Error("scheduled publish failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func publishOnce(ctx context.Context, pool *pgxpool.Pool, c *cache.Cache, now time.Time) error {
+	q := db.New(pool)
+
+	published, err := q.PublishScheduledVideos(ctx, now)
+	if err != nil {
+		return err
+	}
+	unpublished, err := q.UnpublishScheduledVideos(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	if len(published)+len(unpublished) > 0 {
+		slog.Info("scheduled videos applied",
+			"published", published,
+			"unpublished", unpublished,
+		)
+		invalidateCatalog(ctx, c)
+	}
+	return nil
+}
+
+// invalidateCatalog drops every cached response a video status change can
+// affect: listings, counts and search.
+func invalidateCatalog(ctx context.Context, c *cache.Cache) {
+	for _, pattern := range []string{"videos:*", "states:*", "sublocations:*", "tags:*", "search:*"} {
+		if err := c.Invalidate(ctx, pattern); err != nil {
+			slog.Warn("cache invalidation failed", "pattern", pattern, "error", err)
+		}
+	}
+}
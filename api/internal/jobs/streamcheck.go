@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brandon-relentnet/nationcam/api/internal/cache"
+	"github.com/brandon-relentnet/nationcam/api/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// probeFailuresBeforeOffline debounces flaky streams: a published video
+	// goes offline only after this many consecutive failed probes.
+	probeFailuresBeforeOffline = 2
+	probeTimeout               = 10 * time.Second
+	probeConcurrency           = 8
+)
+
+// CheckStreams probes the src of every published and offline video every
+// interval until ctx is cancelled. Published videos whose stream stops
+// answering are marked offline; offline videos whose stream answers again
+// are published. Videos in maintenance are left alone.
+func CheckStreams(ctx context.Context, pool *pgxpool.Pool, c *cache.Cache, interval time.Duration) {
+	client := &http.Client{Timeout: probeTimeout}
+	failures := map[int32]int{}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := checkOnce(ctx, pool, c, client, failures); err != nil {
+			slog.Error("stream health check failed", "error", err)
+		}
+	}
+}
+
+func checkOnce(ctx context.Context, pool *pgxpool.Pool, c *cache.Cache, client *http.Client, failures map[int32]int) error {
+	q := db.New(pool)
+	videos, err := q.ListVideosForHealthCheck(ctx)
+	if err != nil {
+		return err
+	}
+
+	online := make([]bool, len(videos))
+	sem := make(chan struct{}, probeConcurrency)
+	var wg sync.WaitGroup
+	for i, v := range videos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			online[i] = probeStream(ctx, client, v.Src)
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int32]bool, len(videos))
+	changed := 0
+	for i, v := range videos {
+		seen[v.VideoID] = true
+		if online[i] {
+			delete(failures, v.VideoID)
+		} else {
+			failures[v.VideoID]++
+		}
+
+		var toOnline bool
+		switch {
+		case v.Status == "offline" && online[i]:
+			toOnline = true
+		case v.Status == "published" && failures[v.VideoID] >= probeFailuresBeforeOffline:
+			toOnline = false
+		default:
+			continue
+		}
+
+		// The status guard in SetVideoOnline skips videos an admin changed
+		// while the probes were running.
+		n, err := q.SetVideoOnline(ctx, db.SetVideoOnlineParams{VideoID: v.VideoID, Online: toOnline})
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			changed++
+			slog.Info("video stream status changed", "video_id", v.VideoID, "online", toOnline)
+		}
+	}
+
+	// Forget videos that are no longer checked (deleted, archived, ...).
+	for id := range failures {
+		if !seen[id] {
+			delete(failures, id)
+		}
+	}
+
+	if changed > 0 {
+		invalidateCatalog(ctx, c)
+	}
+	return nil
+}
+
+// probeStream reports whether src answers a GET with a non-error status.
+// Non-HTTP sources can't be probed and count as online.
+func probeStream(ctx context.Context, client *http.Client, src string) bool {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return true
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode < http.StatusBadRequest
+}
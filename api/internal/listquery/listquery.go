@@ -0,0 +1,358 @@
+// Package listquery builds the SQL behind admin list endpoints from their
+// query parameters: whitelisted filters, a ?sort= over whitelisted fields
+// (e.g. "-updated_at,title") and keyset cursors in that order.
+//
+// Parameter values only ever reach SQL as placeholders. Column expressions
+// come from the Spec, never from the request.
+package listquery
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the type of a filter value or sort key.
+type Kind int
+
+const (
+	Text Kind = iota
+	Int
+	Time
+	Bool
+)
+
+// Op is how a filter compares its column to the parameter.
+type Op int
+
+const (
+	// Equal matches the value exactly.
+	Equal Op = iota
+	// OneOf matches any of a comma-separated list of values.
+	OneOf
+	// Contains matches a case-insensitive substring.
+	Contains
+	// Since matches values at or after the parameter.
+	Since
+	// Until matches values before the parameter.
+	Until
+)
+
+// Filter is a query parameter that narrows the list.
+type Filter struct {
+	Param  string
+	Column string
+	Op     Op
+	Kind   Kind
+}
+
+// Key is a sort column. Expr must never be NULL: keyset comparisons can't
+// see past one, so wrap nullable columns (see Sort).
+type Key struct {
+	Expr string
+	Kind Kind
+}
+
+// Sort is a ?sort= field over rows of type T. A nullable column sorts as two
+// keys, e.g. "position" is (sort_order IS NULL, COALESCE(sort_order, 0)).
+type Sort[T any] struct {
+	Name string
+	Keys []Key
+	// Values returns row's value for each of Keys.
+	Values func(row T) []any
+}
+
+// Spec describes one list endpoint.
+type Spec[T any] struct {
+	// Select is the query up to its WHERE clause.
+	Select string
+	// From is the FROM clause totals are counted over. Where and every
+	// filter may only use its columns.
+	From string
+	// Where is always applied.
+	Where   string
+	GroupBy string
+	Filters []Filter
+	Sorts   []Sort[T]
+	// DefaultSort is used without ?sort=.
+	DefaultSort string
+	// ID breaks ties after every sort; IDValue returns it for a row.
+	ID      Key
+	IDValue func(row T) any
+}
+
+// Query is a request parsed against a Spec.
+type Query[T any] struct {
+	spec  *Spec[T]
+	sort  string
+	order []orderKey
+	sorts []Sort[T]
+	conds []string
+	args  []any
+}
+
+type orderKey struct {
+	Key
+	desc bool
+}
+
+// ErrCursorMismatch is returned for a cursor taken under a different sort.
+var ErrCursorMismatch = errors.New("cursor does not match sort")
+
+// Parse reads the filters and sort in v. Errors are fit to show the client.
+func (s *Spec[T]) Parse(v url.Values) (*Query[T], error) {
+	q := &Query[T]{spec: s}
+
+	for _, f := range s.Filters {
+		raw := strings.TrimSpace(v.Get(f.Param))
+		if raw == "" {
+			continue
+		}
+		if err := q.addFilter(f, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	sort := v.Get("sort")
+	if sort == "" {
+		sort = s.DefaultSort
+	}
+	var names []string
+	seen := map[string]bool{}
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		name, desc := strings.CutPrefix(field, "-")
+		if seen[name] {
+			return nil, fmt.Errorf("sort lists %s twice", name)
+		}
+		seen[name] = true
+
+		i := s.sortIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("cannot sort by %q; sort fields are %s", name, s.sortNames())
+		}
+		for _, k := range s.Sorts[i].Keys {
+			q.order = append(q.order, orderKey{k, desc})
+		}
+		q.sorts = append(q.sorts, s.Sorts[i])
+		names = append(names, field)
+	}
+	q.order = append(q.order, orderKey{Key: s.ID})
+	q.sort = strings.Join(names, ",")
+	return q, nil
+}
+
+// Sort returns the normalized ?sort= the query runs with. Cursors carry it
+// so they aren't replayed under another order.
+func (q *Query[T]) Sort() string {
+	return q.sort
+}
+
+// Values returns the sort key of row, for a cursor.
+func (q *Query[T]) Values(row T) []any {
+	var vals []any
+	for _, s := range q.sorts {
+		vals = append(vals, s.Values(row)...)
+	}
+	return append(vals, q.spec.IDValue(row))
+}
+
+// SQL returns the page query and its arguments: rows after the cursor
+// values (or before them, in reverse order, when backward), then offset and
+// limit. after is nil for the first page.
+func (q *Query[T]) SQL(after []any, backward bool, limit, offset int32) (string, []any, error) {
+	conds := append([]string(nil), q.conds...)
+	args := append([]any(nil), q.args...)
+
+	if after != nil {
+		if len(after) != len(q.order) {
+			return "", nil, ErrCursorMismatch
+		}
+		vals := make([]any, len(after))
+		for i, raw := range after {
+			v, err := decodeValue(raw, q.order[i].Kind)
+			if err != nil {
+				return "", nil, ErrCursorMismatch
+			}
+			vals[i] = v
+		}
+		cond, keyArgs := q.keyset(vals, backward, len(args))
+		conds = append(conds, cond)
+		args = append(args, keyArgs...)
+	}
+
+	var b strings.Builder
+	b.WriteString(q.spec.Select)
+	b.WriteString("\nWHERE ")
+	b.WriteString(q.where(conds))
+	if q.spec.GroupBy != "" {
+		b.WriteString("\nGROUP BY ")
+		b.WriteString(q.spec.GroupBy)
+	}
+	b.WriteString("\nORDER BY ")
+	for i, k := range q.order {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(k.Expr)
+		if k.desc != backward {
+			b.WriteString(" DESC")
+		}
+	}
+	args = append(args, limit, offset)
+	fmt.Fprintf(&b, "\nLIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	return b.String(), args, nil
+}
+
+// CountSQL returns the query counting every row that matches the filters.
+func (q *Query[T]) CountSQL() (string, []any) {
+	return "SELECT COUNT(*)::int " + q.spec.From + " WHERE " + q.where(q.conds), q.args
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+func (q *Query[T]) where(conds []string) string {
+	all := append([]string{q.spec.Where}, conds...)
+	return strings.Join(all, " AND ")
+}
+
+// keyset returns the condition selecting rows strictly after vals in the
+// query's order (before them when backward): with mixed directions, a row
+// comparison won't do, so it spells out each key's tie-break.
+func (q *Query[T]) keyset(vals []any, backward bool, argc int) (string, []any) {
+	var ors []string
+	var args []any
+	for i, k := range q.order {
+		var ands []string
+		for j := range i {
+			args = append(args, vals[j])
+			ands = append(ands, fmt.Sprintf("%s = $%d", q.order[j].Expr, argc+len(args)))
+		}
+		op := ">"
+		if k.desc != backward {
+			op = "<"
+		}
+		args = append(args, vals[i])
+		ands = append(ands, fmt.Sprintf("%s %s $%d", k.Expr, op, argc+len(args)))
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+func (q *Query[T]) addFilter(f Filter, raw string) error {
+	switch f.Op {
+	case OneOf:
+		// pgx needs a typed slice to encode an array.
+		var texts []string
+		var ints []int32
+		for _, part := range strings.Split(raw, ",") {
+			v, err := parseValue(f, strings.TrimSpace(part))
+			if err != nil {
+				return err
+			}
+			switch v := v.(type) {
+			case string:
+				texts = append(texts, v)
+			case int32:
+				ints = append(ints, v)
+			default:
+				return fmt.Errorf("%s takes a single value", f.Param)
+			}
+		}
+		if ints != nil {
+			q.args = append(q.args, ints)
+		} else {
+			q.args = append(q.args, texts)
+		}
+		q.conds = append(q.conds, fmt.Sprintf("%s = ANY($%d)", f.Column, len(q.args)))
+		return nil
+	case Contains:
+		q.args = append(q.args, "%"+escapeLike(raw)+"%")
+		q.conds = append(q.conds, fmt.Sprintf("%s ILIKE $%d", f.Column, len(q.args)))
+		return nil
+	}
+
+	v, err := parseValue(f, raw)
+	if err != nil {
+		return err
+	}
+	op := map[Op]string{Equal: "=", Since: ">=", Until: "<"}[f.Op]
+	q.args = append(q.args, v)
+	q.conds = append(q.conds, fmt.Sprintf("%s %s $%d", f.Column, op, len(q.args)))
+	return nil
+}
+
+// parseValue converts a filter parameter to f's kind.
+func parseValue(f Filter, s string) (any, error) {
+	switch f.Kind {
+	case Int:
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", f.Param)
+		}
+		return int32(v), nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse(time.DateOnly, s); err == nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", f.Param)
+	case Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", f.Param)
+		}
+		return v, nil
+	}
+	return s, nil
+}
+
+// decodeValue converts a cursor value, as decoded from JSON, to kind.
+func decodeValue(v any, kind Kind) (any, error) {
+	switch kind {
+	case Int:
+		if f, ok := v.(float64); ok {
+			return int64(f), nil
+		}
+	case Time:
+		if s, ok := v.(string); ok {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+	case Bool:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	default:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	}
+	return nil, ErrCursorMismatch
+}
+
+// escapeLike escapes LIKE wildcards in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (s *Spec[T]) sortIndex(name string) int {
+	for i, sort := range s.Sorts {
+		if sort.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Spec[T]) sortNames() string {
+	names := make([]string, len(s.Sorts))
+	for i, sort := range s.Sorts {
+		names[i] = sort.Name
+	}
+	return strings.Join(names, ", ")
+}
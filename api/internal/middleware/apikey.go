@@ -1,16 +1,35 @@
 package middleware
 
 import (
+	"context"
+	"crypto/sha256"
 	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 )
 
+// APIKeyKey is the context key holding the identity of an API key caller.
+const APIKeyKey contextKey = "api_key"
+
+// apiKeyIdentity is a stable, non-secret label for an API key: the first
+// 8 bytes of its SHA-256, so audit entries can tell rotated keys apart.
+func apiKeyIdentity(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "apikey:" + hex.EncodeToString(sum[:8])
+}
+
+// withAPIKey marks the request as authenticated by the given API key.
+func withAPIKey(r *http.Request, identity string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), APIKeyKey, identity))
+}
+
 // RequireAPIKey returns middleware that validates an API key from the
 // X-API-Key header or apikey query parameter. Uses constant-time
 // comparison to prevent timing attacks.
 func RequireAPIKey(key string) func(http.Handler) http.Handler {
 	keyBytes := []byte(key)
+	identity := apiKeyIdentity(key)
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -26,7 +45,7 @@ func RequireAPIKey(key string) func(http.Handler) http.Handler {
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, withAPIKey(r, identity))
 		})
 	}
 }
@@ -39,6 +58,7 @@ func RequireAPIKey(key string) func(http.Handler) http.Handler {
 // and the NationCam dashboard (Logto JWT).
 func RequireAPIKeyOrAdmin(key string) func(http.Handler) http.Handler {
 	keyBytes := []byte(key)
+	identity := apiKeyIdentity(key)
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -48,7 +68,7 @@ func RequireAPIKeyOrAdmin(key string) func(http.Handler) http.Handler {
 				provided = r.URL.Query().Get("apikey")
 			}
 			if provided != "" && subtle.ConstantTimeCompare([]byte(provided), keyBytes) == 1 {
-				next.ServeHTTP(w, r)
+				next.ServeHTTP(w, withAPIKey(r, identity))
 				return
 			}
 
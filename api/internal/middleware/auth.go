@@ -70,12 +70,37 @@ func RequireAdmin(next http.Handler) http.Handler {
 	})
 }
 
+// RequireUser is middleware that rejects anonymous requests, for endpoints
+// about the signed-in viewer's own data rather than the catalog.
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if UserID(r.Context()) == "" {
+			http.Error(w, `{"error":"sign-in required"}`, http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // UserID extracts the authenticated user ID from the request context.
 func UserID(ctx context.Context) string {
 	id, _ := ctx.Value(UserIDKey).(string)
 	return id
 }
 
+// Actor identifies who is making a request, for audit logging. It returns
+// the Logto subject with type "user", the API key identity with type
+// "api_key", or empty strings for anonymous requests.
+func Actor(ctx context.Context) (actor, actorType string) {
+	if id := UserID(ctx); id != "" {
+		return id, "user"
+	}
+	if key, _ := ctx.Value(APIKeyKey).(string); key != "" {
+		return key, "api_key"
+	}
+	return "", ""
+}
+
 type tokenClaims struct {
 	Subject string `json:"sub"`
 }
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientHasher identifies the clients of public endpoints: it finds a
+// request's real address and turns it into a stable pseudonym, so anonymous
+// visitors can be told apart (rate limits, duplicate reports) without
+// storing their IPs.
+type ClientHasher struct {
+	key     []byte
+	trusted []netip.Prefix
+}
+
+// NewClientHasher creates a ClientHasher keyed by secret. Without one, a
+// random key is used: hashes then change on every restart. trustedProxies
+// are the reverse proxies (nginx, the platform's load balancer) whose
+// X-Forwarded-For is believed.
+func NewClientHasher(secret string, trustedProxies []netip.Prefix) *ClientHasher {
+	key := []byte(secret)
+	if secret == "" {
+		slog.Warn("CLIENT_HASH_SECRET not set; client hashes will not survive restarts")
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	if len(trustedProxies) == 0 {
+		slog.Warn("TRUSTED_PROXIES not set; every proxied visitor shares the proxy's address")
+	}
+	return &ClientHasher{key: key, trusted: trustedProxies}
+}
+
+// IP returns the address a request came from. A request from a trusted
+// proxy is attributed to the rightmost X-Forwarded-For hop that isn't one:
+// hops to its left were written by the client and can't be believed. Other
+// requests use RemoteAddr, whatever their headers say.
+func (h *ClientHasher) IP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !h.isTrusted(host) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if _, err := netip.ParseAddr(hop); err != nil {
+			// A garbled hop ends the chain of trust.
+			return host
+		}
+		host = hop
+		if !h.isTrusted(hop) {
+			break
+		}
+	}
+	return host
+}
+
+// Hash returns the pseudonym of r's client (see IP).
+func (h *ClientHasher) Hash(r *http.Request) string {
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(h.IP(r)))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func (h *ClientHasher) isTrusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range h.trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
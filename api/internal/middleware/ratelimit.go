@@ -63,3 +63,68 @@ func RateLimit(rl *RateLimiter) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// ClientRateLimiter is RateLimiter per client: each key (see ClientHasher) gets
+// max requests per window. For public endpoints, where there is no API key.
+type ClientRateLimiter struct {
+	mu       sync.Mutex
+	max      int
+	window   time.Duration
+	requests map[string][]time.Time
+}
+
+// NewClientRateLimiter creates a limiter allowing each client max requests
+// per window.
+func NewClientRateLimiter(max int, window time.Duration) *ClientRateLimiter {
+	return &ClientRateLimiter{
+		max:      max,
+		window:   window,
+		requests: make(map[string][]time.Time),
+	}
+}
+
+// Allow checks if a request from key is allowed and records it if so.
+func (rl *ClientRateLimiter) Allow(key string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	cutoff := now.Add(-rl.window)
+
+	// Prune every client's expired entries, so idle clients don't pile up.
+	for k, times := range rl.requests {
+		valid := times[:0]
+		for _, t := range times {
+			if t.After(cutoff) {
+				valid = append(valid, t)
+			}
+		}
+		if len(valid) == 0 {
+			delete(rl.requests, k)
+		} else {
+			rl.requests[k] = valid
+		}
+	}
+
+	if len(rl.requests[key]) >= rl.max {
+		return false
+	}
+	rl.requests[key] = append(rl.requests[key], now)
+	return true
+}
+
+// RateLimitByClient returns middleware that enforces rl per client, as
+// clients identifies them.
+func RateLimitByClient(rl *ClientRateLimiter, clients *ClientHasher) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !rl.Allow(clients.Hash(r)) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				json.NewEncoder(w).Encode(map[string]string{"error": "rate limit exceeded, try again later"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package slug turns display names into URL slugs.
+//
+// Slugs are lowercase ASCII letters and digits joined by single hyphens.
+// Accented Latin letters lose their marks ("Île-de-France" → "ile-de-france"),
+// letters with no decomposition are spelled out ("Straße" → "strasse") and
+// Greek and Cyrillic are transliterated. Anything else is treated as a word
+// separator.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength caps generated slugs. Collision suffixes may add a few bytes.
+const MaxLength = 80
+
+// Make returns the slug for name, or "" if name has nothing transliterable.
+func Make(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		s, known := transliterations[r]
+		switch {
+		case known:
+		case r == '\'' || r == '’':
+			// Elide apostrophes rather than splitting: "O'Hare" → "ohare".
+			continue
+		default:
+			s = fold(r)
+		}
+		if s == "" {
+			if !known {
+				hyphen = b.Len() > 0
+			}
+			continue
+		}
+		if b.Len()+len(s)+1 > MaxLength {
+			break
+		}
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// fold returns the ASCII letters and digits left of r once its compatibility
+// decomposition has dropped any accents: "é" → "e", "ﬁ" → "fi", "²" → "2",
+// "ά" → "a".
+func fold(r rune) string {
+	if r < utf8.RuneSelf {
+		if unicode.IsLower(r) || unicode.IsDigit(r) {
+			return string(r)
+		}
+		return ""
+	}
+	var b strings.Builder
+	for _, d := range norm.NFKD.String(string(r)) {
+		d = unicode.ToLower(d)
+		if (d >= 'a' && d <= 'z') || (d >= '0' && d <= '9') {
+			b.WriteRune(d)
+		} else {
+			b.WriteString(transliterations[d])
+		}
+	}
+	return b.String()
+}
+
+// Unique returns base if it is not in taken, and otherwise base with the
+// lowest free "-2", "-3", … suffix.
+func Unique(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, t := range taken {
+		used[t] = true
+	}
+	if !used[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		candidate := base + "-" + strconv.Itoa(n)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// transliterations spells out lowercase letters that don't decompose to
+// ASCII. Empty values (Cyrillic hard and soft signs) are dropped without
+// splitting the word.
+var transliterations = map[rune]string{
+	// Latin
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'þ': "th",
+	'ł': "l", 'ı': "i", 'ħ': "h", 'ŋ': "ng", 'ŧ': "t", 'ĸ': "k", 'ſ': "s",
+	'&': "and",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u", 'ђ': "dj", 'ј': "j",
+	'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+}
@@ -0,0 +1,143 @@
+// Package sun computes sunrise, sunset and civil twilight offline.
+//
+// Positions use the low-precision solar coordinates from the Astronomical
+// Almanac, good to about a minute for event times between 1950 and 2050 —
+// plenty to tell whether a camera is looking at daylight.
+package sun
+
+import (
+	"math"
+	"time"
+)
+
+// Altitudes of the sun's centre, in degrees, that define each event.
+const (
+	// Horizon is sunrise and sunset: the upper limb on the horizon,
+	// allowing for refraction.
+	Horizon = -0.833
+	// CivilTwilight is civil dawn and dusk. Between them the sky is bright
+	// enough for a camera to show a usable picture.
+	CivilTwilight = -6.0
+)
+
+// j2000 is the Julian date of 2000-01-01 12:00 UTC.
+const j2000 = 2451545.0
+
+// maxSearchDays bounds NextSunrise: past the poles' longest night there is
+// nothing to find.
+const maxSearchDays = 190
+
+// Day holds the solar events of one date at a place. An event is zero when
+// the sun doesn't cross its altitude that day (polar day or night).
+type Day struct {
+	CivilDawn time.Time
+	Sunrise   time.Time
+	Sunset    time.Time
+	CivilDusk time.Time
+}
+
+// Events returns the solar events at lat, lon (degrees, east positive) on
+// the calendar date of date in its location. Times are in that location.
+func Events(date time.Time, lat, lon float64) Day {
+	loc := date.Location()
+	y, m, d := date.Date()
+	noon := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+	transit, decl := solarTransit(noon, lon)
+
+	var day Day
+	day.CivilDawn, day.CivilDusk = crossings(transit, decl, lat, CivilTwilight)
+	day.Sunrise, day.Sunset = crossings(transit, decl, lat, Horizon)
+	for _, t := range []*time.Time{&day.CivilDawn, &day.Sunrise, &day.Sunset, &day.CivilDusk} {
+		if !t.IsZero() {
+			*t = t.In(loc).Round(time.Second)
+		}
+	}
+	return day
+}
+
+// Elevation returns the altitude of the sun's centre above the horizon at
+// lat, lon at t, in degrees.
+func Elevation(t time.Time, lat, lon float64) float64 {
+	d := julian(t) - j2000
+	ra, decl := equatorial(d)
+
+	gmst := math.Mod(280.46061837+360.98564736629*d, 360)
+	hourAngle := gmst + lon - ra
+
+	sinAlt := sinDeg(lat)*sinDeg(decl) + cosDeg(lat)*cosDeg(decl)*cosDeg(hourAngle)
+	return degrees(math.Asin(sinAlt))
+}
+
+// IsDaylight reports whether the sun is above civil twilight at lat, lon at t.
+func IsDaylight(t time.Time, lat, lon float64) bool {
+	return Elevation(t, lat, lon) > CivilTwilight
+}
+
+// NextSunrise returns the first sunrise at lat, lon after t, in t's
+// location, or false if there is none within half a year (polar night).
+func NextSunrise(t time.Time, lat, lon float64) (time.Time, bool) {
+	// Start a day early: the UTC date of a sunrise can precede t's.
+	for i := -1; i <= maxSearchDays; i++ {
+		rise := Events(t.AddDate(0, 0, i), lat, lon).Sunrise
+		if !rise.IsZero() && rise.After(t) {
+			return rise, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ── Helpers ───────────────────────────────────────────────────────────
+
+// solarTransit returns solar noon nearest noon (a UTC noon) at longitude
+// lon, and the sun's declination then.
+func solarTransit(noon time.Time, lon float64) (time.Time, float64) {
+	// Days since J2000 at local mean solar noon.
+	n := math.Round(julian(noon) - j2000)
+	jStar := n - lon/360
+
+	m := math.Mod(357.5291+0.98560028*jStar, 360)
+	c := 1.9148*sinDeg(m) + 0.0200*sinDeg(2*m) + 0.0003*sinDeg(3*m)
+	lambda := math.Mod(m+c+180+102.9372, 360)
+
+	jTransit := j2000 + jStar + 0.0053*sinDeg(m) - 0.0069*sinDeg(2*lambda)
+	decl := degrees(math.Asin(sinDeg(lambda) * sinDeg(23.4397)))
+	return fromJulian(jTransit), decl
+}
+
+// crossings returns when the sun rises through and sets below altitude
+// around transit, or zero times if it stays above or below all day.
+func crossings(transit time.Time, decl, lat, altitude float64) (rise, set time.Time) {
+	cosH := (sinDeg(altitude) - sinDeg(lat)*sinDeg(decl)) / (cosDeg(lat) * cosDeg(decl))
+	if cosH < -1 || cosH > 1 {
+		return time.Time{}, time.Time{}
+	}
+	half := time.Duration(degrees(math.Acos(cosH)) / 360 * 24 * float64(time.Hour))
+	return transit.Add(-half), transit.Add(half)
+}
+
+// equatorial returns the sun's right ascension and declination in degrees,
+// d days after J2000.
+func equatorial(d float64) (ra, decl float64) {
+	g := math.Mod(357.529+0.98560028*d, 360)
+	q := math.Mod(280.459+0.98564736*d, 360)
+	lambda := q + 1.915*sinDeg(g) + 0.020*sinDeg(2*g)
+	eps := 23.439 - 0.00000036*d
+
+	ra = degrees(math.Atan2(cosDeg(eps)*sinDeg(lambda), cosDeg(lambda)))
+	decl = degrees(math.Asin(sinDeg(eps) * sinDeg(lambda)))
+	return ra, decl
+}
+
+// julian returns the Julian date of t.
+func julian(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5
+}
+
+// fromJulian is the inverse of julian, in UTC.
+func fromJulian(jd float64) time.Time {
+	return time.Unix(0, int64((jd-2440587.5)*float64(24*time.Hour))).UTC()
+}
+
+func sinDeg(x float64) float64  { return math.Sin(x * math.Pi / 180) }
+func cosDeg(x float64) float64  { return math.Cos(x * math.Pi / 180) }
+func degrees(x float64) float64 { return x * 180 / math.Pi }
@@ -1,9 +1,10 @@
 package sql
 
-import _ "embed"
+import "embed"
 
-// Schema contains the full database schema SQL, embedded at compile time.
-// All statements are idempotent (IF NOT EXISTS / OR REPLACE), safe to run on every startup.
+// Migrations contains the numbered up/down migration files, embedded at compile time.
+// Files are named NNNN_description.up.sql / NNNN_description.down.sql and are
+// applied in version order by the internal/migrate package.
 //
-//go:embed schema.sql
-var Schema string
+//go:embed migrations/*.sql
+var Migrations embed.FS
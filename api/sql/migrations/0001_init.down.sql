@@ -0,0 +1,9 @@
+-- Reverts 0001_init: drops the initial catalog schema.
+
+DROP TABLE IF EXISTS videos;
+DROP TABLE IF EXISTS sublocations;
+DROP TABLE IF EXISTS states;
+
+DROP FUNCTION IF EXISTS set_updated_at();
+DROP FUNCTION IF EXISTS set_slug_from_name();
+DROP FUNCTION IF EXISTS generate_slug(TEXT);
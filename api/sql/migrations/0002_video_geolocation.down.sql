@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_videos_lat_lon;
+
+ALTER TABLE videos
+  DROP CONSTRAINT IF EXISTS videos_coordinates_pair,
+  DROP CONSTRAINT IF EXISTS videos_heading_range,
+  DROP CONSTRAINT IF EXISTS videos_longitude_range,
+  DROP CONSTRAINT IF EXISTS videos_latitude_range;
+
+ALTER TABLE videos
+  DROP COLUMN IF EXISTS elevation,
+  DROP COLUMN IF EXISTS heading,
+  DROP COLUMN IF EXISTS longitude,
+  DROP COLUMN IF EXISTS latitude;
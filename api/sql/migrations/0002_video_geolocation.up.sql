@@ -0,0 +1,18 @@
+-- Camera coordinates for map display and "near me" search.
+-- Heading is the compass bearing the camera faces (0–360, 0 = north);
+-- elevation is metres above sea level.
+
+ALTER TABLE videos
+  ADD COLUMN IF NOT EXISTS latitude  DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS heading   DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS elevation DOUBLE PRECISION;
+
+ALTER TABLE videos
+  ADD CONSTRAINT videos_latitude_range  CHECK (latitude BETWEEN -90 AND 90),
+  ADD CONSTRAINT videos_longitude_range CHECK (longitude BETWEEN -180 AND 180),
+  ADD CONSTRAINT videos_heading_range   CHECK (heading >= 0 AND heading < 360),
+  ADD CONSTRAINT videos_coordinates_pair CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+CREATE INDEX IF NOT EXISTS idx_videos_lat_lon ON videos(latitude, longitude)
+  WHERE latitude IS NOT NULL;
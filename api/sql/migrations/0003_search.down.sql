@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_videos_search;
+DROP INDEX IF EXISTS idx_sublocations_search;
+DROP INDEX IF EXISTS idx_states_search;
+
+ALTER TABLE videos DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE sublocations DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE states DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,24 @@
+-- Full-text search vectors for GET /search.
+-- The 'simple' configuration (no stemming) is used so that prefix queries
+-- built for type-ahead ("ven:*") match the words as typed.
+
+ALTER TABLE states
+  ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+  ) STORED;
+
+ALTER TABLE sublocations
+  ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+  ) STORED;
+
+ALTER TABLE videos
+  ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A')
+  ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_states_search ON states USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_sublocations_search ON sublocations USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_videos_search ON videos USING GIN (search_vector);
@@ -0,0 +1,26 @@
+-- Free-form tags ("beach", "traffic", "ski") attached to videos.
+
+CREATE TABLE IF NOT EXISTS tags (
+  tag_id     SERIAL PRIMARY KEY,
+  name       TEXT NOT NULL UNIQUE,
+  slug       TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS video_tags (
+  video_id INTEGER NOT NULL REFERENCES videos(video_id) ON DELETE CASCADE,
+  tag_id   INTEGER NOT NULL REFERENCES tags(tag_id) ON DELETE CASCADE,
+  PRIMARY KEY (video_id, tag_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_slug ON tags(slug);
+CREATE INDEX IF NOT EXISTS idx_video_tags_tag_id ON video_tags(tag_id);
+
+CREATE OR REPLACE TRIGGER trg_tags_slug
+  BEFORE INSERT OR UPDATE ON tags
+  FOR EACH ROW EXECUTE FUNCTION set_slug_from_name();
+
+CREATE OR REPLACE TRIGGER trg_tags_updated
+  BEFORE UPDATE ON tags
+  FOR EACH ROW EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,13 @@
+-- Rows still in the trash are removed for good: without deleted_at they
+-- would otherwise reappear as live data.
+DELETE FROM videos WHERE deleted_at IS NOT NULL;
+DELETE FROM sublocations WHERE deleted_at IS NOT NULL;
+DELETE FROM states WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_videos_deleted_at;
+DROP INDEX IF EXISTS idx_sublocations_deleted_at;
+DROP INDEX IF EXISTS idx_states_deleted_at;
+
+ALTER TABLE videos       DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE sublocations DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE states       DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,12 @@
+-- Soft deletion: rows with deleted_at set are in the trash, hidden from
+-- public queries, and hard-deleted by the purge job after the retention period.
+-- Children soft-deleted along with a state share its deleted_at, which is how
+-- a restore knows to bring them back too.
+
+ALTER TABLE states       ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE sublocations ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE videos       ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_states_deleted_at ON states(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_sublocations_deleted_at ON sublocations(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_videos_deleted_at ON videos(deleted_at) WHERE deleted_at IS NOT NULL;
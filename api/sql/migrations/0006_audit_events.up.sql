@@ -0,0 +1,20 @@
+-- Append-only log of admin mutations. before/after hold JSON snapshots of
+-- the entity as returned by the API (NULL for creates and hard deletes).
+
+CREATE TABLE IF NOT EXISTS audit_events (
+  audit_id    BIGSERIAL PRIMARY KEY,
+  actor       TEXT NOT NULL,
+  actor_type  TEXT NOT NULL CHECK (actor_type IN ('user', 'api_key', 'system')),
+  action      TEXT NOT NULL,
+  entity_type TEXT NOT NULL,
+  entity_id   TEXT NOT NULL,
+  before      JSONB,
+  after       JSONB,
+  -- Set on "revert" events: the event whose change was undone.
+  reverts_audit_id BIGINT REFERENCES audit_events(audit_id) ON DELETE SET NULL,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events(created_at);
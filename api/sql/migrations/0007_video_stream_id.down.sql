@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_videos_stream_id;
+ALTER TABLE videos DROP COLUMN IF EXISTS stream_id;
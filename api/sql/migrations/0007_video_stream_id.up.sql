@@ -0,0 +1,6 @@
+-- Links a video to the Restreamer ingest process that feeds it, so stream
+-- lifecycle operations can keep the catalog in sync.
+
+ALTER TABLE videos ADD COLUMN IF NOT EXISTS stream_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_videos_stream_id ON videos(stream_id) WHERE stream_id IS NOT NULL;
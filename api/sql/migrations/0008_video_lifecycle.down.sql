@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_videos_unpublish_at;
+DROP INDEX IF EXISTS idx_videos_publish_at;
+
+ALTER TABLE videos
+  DROP COLUMN IF EXISTS unpublish_at,
+  DROP COLUMN IF EXISTS publish_at,
+  DROP CONSTRAINT IF EXISTS videos_status_check;
+
+UPDATE videos SET status = CASE
+  WHEN status IN ('published', 'maintenance', 'offline') THEN 'active'
+  ELSE 'inactive'
+END;
+
+ALTER TABLE videos
+  ALTER COLUMN status SET DEFAULT 'active',
+  ADD CONSTRAINT videos_status_check CHECK (status IN ('active', 'inactive'));
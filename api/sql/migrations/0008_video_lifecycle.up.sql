@@ -0,0 +1,20 @@
+-- Replaces active/inactive with a publishing lifecycle:
+--   draft → published ⇄ maintenance / offline → archived
+-- offline is set by the stream health check, never by hand. Listings show
+-- published, maintenance and offline videos; draft and archived are hidden.
+-- publish_at/unpublish_at schedule draft → published and → archived.
+
+ALTER TABLE videos DROP CONSTRAINT IF EXISTS videos_status_check;
+
+UPDATE videos SET status = CASE status WHEN 'active' THEN 'published' ELSE 'archived' END
+WHERE status IN ('active', 'inactive');
+
+ALTER TABLE videos
+  ALTER COLUMN status SET DEFAULT 'published',
+  ADD CONSTRAINT videos_status_check
+    CHECK (status IN ('draft', 'published', 'maintenance', 'offline', 'archived')),
+  ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_videos_publish_at ON videos(publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_videos_unpublish_at ON videos(unpublish_at) WHERE unpublish_at IS NOT NULL;
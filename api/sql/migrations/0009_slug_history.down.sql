@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_sublocations_slug_history ON sublocations;
+DROP TRIGGER IF EXISTS trg_states_slug_history ON states;
+DROP FUNCTION IF EXISTS record_slug_history();
+DROP TABLE IF EXISTS slug_history;
@@ -0,0 +1,37 @@
+-- Previous slugs of states and sublocations, so links shared before a slug
+-- change can be redirected to the canonical one. Maintained by trigger: any
+-- slug change records the old slug, and a slug coming (back) into use as a
+-- canonical slug is dropped from the history.
+
+CREATE TABLE IF NOT EXISTS slug_history (
+  entity_type TEXT NOT NULL CHECK (entity_type IN ('state', 'sublocation')),
+  slug        TEXT NOT NULL,
+  entity_id   INTEGER NOT NULL,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (entity_type, slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_slug_history_entity ON slug_history(entity_type, entity_id);
+
+-- TG_ARGV[0] is the entity type, TG_ARGV[1] the table's ID column.
+CREATE OR REPLACE FUNCTION record_slug_history() RETURNS TRIGGER AS $$
+BEGIN
+  DELETE FROM slug_history WHERE entity_type = TG_ARGV[0] AND slug = NEW.slug;
+
+  IF TG_OP = 'UPDATE' AND OLD.slug <> '' AND OLD.slug IS DISTINCT FROM NEW.slug THEN
+    INSERT INTO slug_history (entity_type, slug, entity_id)
+    VALUES (TG_ARGV[0], OLD.slug, (to_jsonb(OLD) ->> TG_ARGV[1])::int)
+    ON CONFLICT (entity_type, slug)
+    DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = now();
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_states_slug_history
+  AFTER INSERT OR UPDATE OF slug ON states
+  FOR EACH ROW EXECUTE FUNCTION record_slug_history('state', 'state_id');
+
+CREATE OR REPLACE TRIGGER trg_sublocations_slug_history
+  AFTER INSERT OR UPDATE OF slug ON sublocations
+  FOR EACH ROW EXECUTE FUNCTION record_slug_history('sublocation', 'sublocation_id');
@@ -0,0 +1,8 @@
+DROP FUNCTION IF EXISTS sublocation_subtree(INTEGER);
+DROP TRIGGER IF EXISTS trg_sublocations_parent ON sublocations;
+DROP FUNCTION IF EXISTS check_sublocation_parent();
+DROP INDEX IF EXISTS idx_sublocations_parent_id;
+
+ALTER TABLE sublocations
+  DROP CONSTRAINT IF EXISTS sublocations_parent_not_self,
+  DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,43 @@
+-- Nested sublocations: state → city → neighborhood/venue. A parent is always
+-- in the same state as its children (enforced by the API) and a sublocation
+-- can never be its own ancestor (enforced here).
+
+ALTER TABLE sublocations
+  ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES sublocations(sublocation_id) ON DELETE SET NULL,
+  ADD CONSTRAINT sublocations_parent_not_self CHECK (parent_id <> sublocation_id);
+
+CREATE INDEX IF NOT EXISTS idx_sublocations_parent_id ON sublocations(parent_id);
+
+CREATE OR REPLACE FUNCTION check_sublocation_parent() RETURNS TRIGGER AS $$
+BEGIN
+  IF NEW.parent_id IS NOT NULL AND EXISTS (
+    WITH RECURSIVE up AS (
+      SELECT NEW.parent_id AS sublocation_id
+      UNION
+      SELECT p.parent_id FROM sublocations p JOIN up ON p.sublocation_id = up.sublocation_id
+      WHERE p.parent_id IS NOT NULL
+    )
+    SELECT 1 FROM up WHERE sublocation_id = NEW.sublocation_id
+  ) THEN
+    RAISE EXCEPTION 'sublocation % cannot be its own ancestor', NEW.sublocation_id
+      USING ERRCODE = 'check_violation', CONSTRAINT = 'sublocations_parent_cycle';
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_sublocations_parent
+  BEFORE INSERT OR UPDATE OF parent_id ON sublocations
+  FOR EACH ROW EXECUTE FUNCTION check_sublocation_parent();
+
+-- root and every live sublocation below it. Trashed sublocations and their
+-- subtrees are left out.
+CREATE OR REPLACE FUNCTION sublocation_subtree(root INTEGER) RETURNS SETOF INTEGER AS $$
+  WITH RECURSIVE down AS (
+    SELECT root AS sublocation_id
+    UNION
+    SELECT c.sublocation_id FROM sublocations c JOIN down ON c.parent_id = down.sublocation_id
+    WHERE c.deleted_at IS NULL
+  )
+  SELECT sublocation_id FROM down;
+$$ LANGUAGE sql STABLE;
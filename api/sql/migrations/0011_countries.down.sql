@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_states_country_code;
+
+ALTER TABLE states
+  DROP COLUMN IF EXISTS region_type,
+  DROP COLUMN IF EXISTS country_code;
+
+DROP TABLE IF EXISTS countries;
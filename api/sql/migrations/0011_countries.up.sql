@@ -0,0 +1,29 @@
+-- Countries, with the existing states table generalized into their regions
+-- (states, provinces, territories). Existing rows become US states.
+
+CREATE TABLE IF NOT EXISTS countries (
+  code        TEXT PRIMARY KEY CHECK (code ~ '^[A-Z]{2}$'), -- ISO 3166-1 alpha-2
+  name        TEXT NOT NULL UNIQUE,
+  region_type TEXT NOT NULL DEFAULT 'state'
+              CHECK (region_type IN ('state', 'province', 'territory', 'district')),
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE OR REPLACE TRIGGER trg_countries_updated
+  BEFORE UPDATE ON countries
+  FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+INSERT INTO countries (code, name, region_type) VALUES
+  ('US', 'United States', 'state'),
+  ('CA', 'Canada', 'province'),
+  ('MX', 'Mexico', 'state')
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE states
+  ADD COLUMN IF NOT EXISTS country_code TEXT NOT NULL DEFAULT 'US'
+    REFERENCES countries(code) ON UPDATE CASCADE,
+  ADD COLUMN IF NOT EXISTS region_type TEXT NOT NULL DEFAULT 'state'
+    CHECK (region_type IN ('state', 'province', 'territory', 'district'));
+
+CREATE INDEX IF NOT EXISTS idx_states_country_code ON states(country_code);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_videos_featured;
+
+ALTER TABLE videos
+  DROP COLUMN IF EXISTS featured,
+  DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE sublocations DROP COLUMN IF EXISTS sort_order;
+ALTER TABLE states DROP COLUMN IF EXISTS sort_order;
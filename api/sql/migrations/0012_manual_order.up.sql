@@ -0,0 +1,10 @@
+-- Admin-controlled ordering and featured cameras. sort_order is NULL until
+-- an admin reorders; unordered rows sort after ordered ones, by name.
+
+ALTER TABLE states ADD COLUMN IF NOT EXISTS sort_order INTEGER;
+ALTER TABLE sublocations ADD COLUMN IF NOT EXISTS sort_order INTEGER;
+ALTER TABLE videos
+  ADD COLUMN IF NOT EXISTS sort_order INTEGER,
+  ADD COLUMN IF NOT EXISTS featured BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_videos_featured ON videos(sort_order) WHERE featured;
@@ -0,0 +1,30 @@
+-- Editorial promotions: which camera fills each homepage slot ("hero",
+-- "camera-of-the-day", …) over a date range. starts_at/ends_at are instants;
+-- timezone is the IANA zone editors scheduled them in, so the admin UI can
+-- show and edit them as local times. A slot may have one default promotion,
+-- with no window, shown whenever nothing scheduled is live.
+
+CREATE TABLE IF NOT EXISTS promotions (
+  promotion_id SERIAL PRIMARY KEY,
+  slot         TEXT NOT NULL CHECK (slot ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+  video_id     INTEGER NOT NULL REFERENCES videos(video_id) ON DELETE CASCADE,
+  title        TEXT,
+  starts_at    TIMESTAMPTZ,
+  ends_at      TIMESTAMPTZ,
+  timezone     TEXT NOT NULL DEFAULT 'UTC',
+  priority     INTEGER NOT NULL DEFAULT 0,
+  is_default   BOOLEAN NOT NULL DEFAULT false,
+  created_by   TEXT,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at),
+  CHECK (NOT is_default OR (starts_at IS NULL AND ends_at IS NULL)),
+  CHECK (is_default OR starts_at IS NOT NULL)
+);
+
+CREATE OR REPLACE TRIGGER trg_promotions_updated
+  BEFORE UPDATE ON promotions
+  FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotions_slot_default ON promotions(slot) WHERE is_default;
+CREATE INDEX IF NOT EXISTS idx_promotions_slot_window ON promotions(slot, starts_at, ends_at) WHERE NOT is_default;
@@ -0,0 +1,10 @@
+ALTER TABLE videos DROP CONSTRAINT IF EXISTS videos_field_of_view_range;
+
+ALTER TABLE videos
+  DROP COLUMN IF EXISTS notes,
+  DROP COLUMN IF EXISTS install_date,
+  DROP COLUMN IF EXISTS owner_contact,
+  DROP COLUMN IF EXISTS firmware,
+  DROP COLUMN IF EXISTS camera_model,
+  DROP COLUMN IF EXISTS field_of_view,
+  DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,15 @@
+-- Structured camera metadata. timezone (IANA name) and field_of_view
+-- (horizontal degrees; heading is in 0002) are public. The hardware and
+-- contact columns are admin-only: listing queries never select them.
+
+ALTER TABLE videos
+  ADD COLUMN IF NOT EXISTS timezone      TEXT,
+  ADD COLUMN IF NOT EXISTS field_of_view DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS camera_model  TEXT,
+  ADD COLUMN IF NOT EXISTS firmware      TEXT,
+  ADD COLUMN IF NOT EXISTS owner_contact TEXT,
+  ADD COLUMN IF NOT EXISTS install_date  DATE,
+  ADD COLUMN IF NOT EXISTS notes         TEXT;
+
+ALTER TABLE videos
+  ADD CONSTRAINT videos_field_of_view_range CHECK (field_of_view > 0 AND field_of_view <= 360);
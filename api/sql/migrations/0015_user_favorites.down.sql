@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_list_videos;
+DROP TABLE IF EXISTS user_lists;
+DROP TABLE IF EXISTS user_favorites;
@@ -0,0 +1,34 @@
+-- Signed-in viewers' saved cameras. user_id is the Logto subject: there is
+-- no users table, accounts live in Logto. Favorites are one flat set per
+-- user; lists are named, user-ordered collections ("Morning surf check")
+-- whose cameras are ordered too.
+
+CREATE TABLE IF NOT EXISTS user_favorites (
+  user_id    TEXT NOT NULL,
+  video_id   INTEGER NOT NULL REFERENCES videos(video_id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, video_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_lists (
+  list_id    SERIAL PRIMARY KEY,
+  user_id    TEXT NOT NULL,
+  name       TEXT NOT NULL CHECK (length(btrim(name)) BETWEEN 1 AND 100),
+  sort_order INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, name)
+);
+
+CREATE OR REPLACE TRIGGER trg_user_lists_updated
+  BEFORE UPDATE ON user_lists
+  FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS user_list_videos (
+  list_id  INTEGER NOT NULL REFERENCES user_lists(list_id) ON DELETE CASCADE,
+  video_id INTEGER NOT NULL REFERENCES videos(video_id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  PRIMARY KEY (list_id, video_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_list_videos_video ON user_list_videos(video_id);
@@ -0,0 +1,29 @@
+-- Cameras proposed by visitors through the public form, awaiting moderation.
+-- Approving one creates its videos row (video_id); rejecting keeps the row,
+-- with a reason, so repeat proposals can be recognised.
+
+CREATE TABLE IF NOT EXISTS submissions (
+  submission_id SERIAL PRIMARY KEY,
+  title         TEXT NOT NULL CHECK (length(btrim(title)) BETWEEN 1 AND 200),
+  src           TEXT NOT NULL,
+  state_id      INTEGER REFERENCES states(state_id) ON DELETE SET NULL,
+  contact_name  TEXT,
+  contact_email TEXT NOT NULL,
+  notes         TEXT,
+  -- Salted hash of the submitter's address: enough to spot floods, not to
+  -- identify anyone.
+  client_hash   TEXT NOT NULL,
+  status        TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  reject_reason TEXT,
+  reviewed_by   TEXT,
+  reviewed_at   TIMESTAMPTZ,
+  video_id      INTEGER REFERENCES videos(video_id) ON DELETE SET NULL,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE OR REPLACE TRIGGER trg_submissions_updated
+  BEFORE UPDATE ON submissions
+  FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE INDEX IF NOT EXISTS idx_submissions_status ON submissions(status, submission_id);
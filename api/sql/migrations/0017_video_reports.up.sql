@@ -0,0 +1,18 @@
+-- Viewers' reports of broken cameras. A report stays open until an admin
+-- resolves the camera's reports; while open, one client can report a camera
+-- once per reason.
+
+CREATE TABLE IF NOT EXISTS video_reports (
+  report_id   SERIAL PRIMARY KEY,
+  video_id    INTEGER NOT NULL REFERENCES videos(video_id) ON DELETE CASCADE,
+  reason      TEXT NOT NULL CHECK (reason IN ('offline', 'wrong_location', 'inappropriate')),
+  note        TEXT,
+  -- Salted hash of the reporter's address, as for submissions.
+  client_hash TEXT NOT NULL,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  resolved_at TIMESTAMPTZ,
+  resolved_by TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_video_reports_open
+  ON video_reports(video_id, client_hash, reason) WHERE resolved_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE video_reports
+  DROP COLUMN IF EXISTS last_reported_at,
+  DROP COLUMN IF EXISTS report_count;
@@ -0,0 +1,9 @@
+-- A client hash can stand for many viewers (a shared office or mobile
+-- network), so a repeat report is counted on the open report instead of
+-- being dropped: admins can tell one loud address from many viewers.
+
+ALTER TABLE video_reports
+  ADD COLUMN IF NOT EXISTS report_count     INTEGER NOT NULL DEFAULT 1,
+  ADD COLUMN IF NOT EXISTS last_reported_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+UPDATE video_reports SET last_reported_at = created_at;
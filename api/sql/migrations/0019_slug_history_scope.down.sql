@@ -0,0 +1,28 @@
+CREATE OR REPLACE TRIGGER trg_sublocations_slug_history
+  AFTER INSERT OR UPDATE OF slug ON sublocations
+  FOR EACH ROW EXECUTE FUNCTION record_slug_history('sublocation', 'sublocation_id');
+
+CREATE OR REPLACE FUNCTION record_slug_history() RETURNS TRIGGER AS $$
+BEGIN
+  DELETE FROM slug_history WHERE entity_type = TG_ARGV[0] AND slug = NEW.slug;
+
+  IF TG_OP = 'UPDATE' AND OLD.slug <> '' AND OLD.slug IS DISTINCT FROM NEW.slug THEN
+    INSERT INTO slug_history (entity_type, slug, entity_id)
+    VALUES (TG_ARGV[0], OLD.slug, (to_jsonb(OLD) ->> TG_ARGV[1])::int)
+    ON CONFLICT (entity_type, slug)
+    DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = now();
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Keep the most recent entry where states share a retired slug.
+DELETE FROM slug_history h
+USING slug_history newer
+WHERE newer.entity_type = h.entity_type AND newer.slug = h.slug
+  AND (newer.created_at, newer.scope_id) > (h.created_at, h.scope_id);
+
+ALTER TABLE slug_history
+  DROP CONSTRAINT IF EXISTS slug_history_pkey,
+  ADD PRIMARY KEY (entity_type, slug),
+  DROP COLUMN IF EXISTS scope_id;
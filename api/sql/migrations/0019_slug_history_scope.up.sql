@@ -0,0 +1,39 @@
+-- Sublocation slugs are only unique within a state, so their history is
+-- kept per state: scope_id is the sublocation's state_id (0 for states).
+-- Otherwise a slug used in one state would drop another state's redirect,
+-- and one state's rename could take over another's.
+
+ALTER TABLE slug_history ADD COLUMN IF NOT EXISTS scope_id INTEGER NOT NULL DEFAULT 0;
+
+UPDATE slug_history h SET scope_id = sub.state_id
+FROM sublocations sub
+WHERE h.entity_type = 'sublocation' AND sub.sublocation_id = h.entity_id;
+
+ALTER TABLE slug_history
+  DROP CONSTRAINT IF EXISTS slug_history_pkey,
+  ADD PRIMARY KEY (entity_type, scope_id, slug);
+
+-- TG_ARGV[0] is the entity type, TG_ARGV[1] the table's ID column and
+-- TG_ARGV[2], if given, the column slugs are unique within.
+CREATE OR REPLACE FUNCTION record_slug_history() RETURNS TRIGGER AS $$
+DECLARE
+  scope INTEGER := COALESCE((to_jsonb(NEW) ->> TG_ARGV[2])::int, 0);
+BEGIN
+  DELETE FROM slug_history
+  WHERE entity_type = TG_ARGV[0] AND scope_id = scope AND slug = NEW.slug;
+
+  IF TG_OP = 'UPDATE' AND OLD.slug <> '' AND OLD.slug IS DISTINCT FROM NEW.slug THEN
+    INSERT INTO slug_history (entity_type, scope_id, slug, entity_id)
+    VALUES (TG_ARGV[0], COALESCE((to_jsonb(OLD) ->> TG_ARGV[2])::int, 0),
+            OLD.slug, (to_jsonb(OLD) ->> TG_ARGV[1])::int)
+    ON CONFLICT (entity_type, scope_id, slug)
+    DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = now();
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- A sublocation moving state takes its slug into the new state too.
+CREATE OR REPLACE TRIGGER trg_sublocations_slug_history
+  AFTER INSERT OR UPDATE OF slug, state_id ON sublocations
+  FOR EACH ROW EXECUTE FUNCTION record_slug_history('sublocation', 'sublocation_id', 'state_id');
@@ -0,0 +1,2 @@
+ALTER TABLE states DROP CONSTRAINT IF EXISTS states_country_code_name_key;
+ALTER TABLE states ADD CONSTRAINT states_name_key UNIQUE (name);
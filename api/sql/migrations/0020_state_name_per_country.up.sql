@@ -0,0 +1,5 @@
+-- Region names need only be unique within their country: Córdoba is a
+-- province of both Argentina and Spain.
+
+ALTER TABLE states DROP CONSTRAINT IF EXISTS states_name_key;
+ALTER TABLE states ADD CONSTRAINT states_country_code_name_key UNIQUE (country_code, name);
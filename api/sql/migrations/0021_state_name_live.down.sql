@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_states_country_name;
+ALTER TABLE states ADD CONSTRAINT states_country_code_name_key UNIQUE (country_code, name);
@@ -0,0 +1,6 @@
+-- Trashed regions no longer hold their name: a new region may take it, and
+-- restoring the trashed one is then refused until one of them is renamed.
+
+ALTER TABLE states DROP CONSTRAINT IF EXISTS states_country_code_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_states_country_name
+  ON states(country_code, name) WHERE deleted_at IS NULL;
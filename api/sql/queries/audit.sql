@@ -0,0 +1,36 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (actor, actor_type, action, entity_type, entity_id, before, after, reverts_audit_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING audit_id, actor, actor_type, action, entity_type, entity_id, before, after, reverts_audit_id, created_at;
+
+-- name: GetAuditEvent :one
+SELECT audit_id, actor, actor_type, action, entity_type, entity_id, before, after, reverts_audit_id, created_at
+FROM audit_events
+WHERE audit_id = $1;
+
+-- name: ListAuditEvents :many
+-- Newest first. A cursor pages to older events, or to newer ones (in
+-- reverse) when backward.
+SELECT audit_id, actor, actor_type, action, entity_type, entity_id, before, after, reverts_audit_id, created_at
+FROM audit_events
+WHERE (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor)::text)
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action)::text)
+  AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type)::text)
+  AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id)::text)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR created_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR created_at < sqlc.narg(until)::timestamptz)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL OR
+       CASE WHEN sqlc.arg(backward)::bool THEN audit_id > sqlc.narg(cursor_id)::bigint
+            ELSE audit_id < sqlc.narg(cursor_id)::bigint END)
+ORDER BY CASE WHEN sqlc.arg(backward)::bool THEN audit_id ELSE -audit_id END
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountAuditEvents :one
+SELECT COUNT(*)::int
+FROM audit_events
+WHERE (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor)::text)
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action)::text)
+  AND (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type)::text)
+  AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id)::text)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR created_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR created_at < sqlc.narg(until)::timestamptz);
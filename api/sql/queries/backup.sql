@@ -0,0 +1,134 @@
+-- Catalog export/restore. Rows reference each other by slug so a backup can
+-- be loaded into a database with different serial IDs.
+
+-- name: ExportCountries :many
+SELECT code, name, region_type
+FROM countries
+ORDER BY code;
+
+-- name: ExportStates :many
+SELECT slug, name, description, country_code, region_type, sort_order
+FROM states
+WHERE deleted_at IS NULL
+ORDER BY slug;
+
+-- name: ExportSublocations :many
+SELECT s.slug AS state, sub.slug, sub.name, sub.description,
+       p.slug AS parent, sub.sort_order
+FROM sublocations sub
+JOIN states s ON s.state_id = sub.state_id
+LEFT JOIN sublocations p ON p.sublocation_id = sub.parent_id AND p.deleted_at IS NULL
+WHERE sub.deleted_at IS NULL AND s.deleted_at IS NULL
+ORDER BY s.slug, sub.slug;
+
+-- name: ExportTags :many
+SELECT slug, name
+FROM tags
+ORDER BY slug;
+
+-- name: ExportVideos :many
+SELECT v.title, v.src, v.type, v.status,
+       s.slug AS state,
+       sub.slug AS sublocation,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.camera_model, v.firmware, v.owner_contact, v.install_date, v.notes,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.stream_id,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug)
+                 FROM video_tags vt JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
+WHERE v.deleted_at IS NULL AND s.deleted_at IS NULL
+  AND (sub.sublocation_id IS NULL OR sub.deleted_at IS NULL)
+ORDER BY s.slug, v.title, v.video_id;
+
+-- name: ExportPromotions :many
+-- Promotions reference their video by src, so only those of exported videos
+-- are included.
+SELECT p.slot, v.src AS video, p.title, p.starts_at, p.ends_at, p.timezone,
+       p.priority, p.is_default, p.created_by
+FROM promotions p
+JOIN videos v ON v.video_id = p.video_id
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
+WHERE v.deleted_at IS NULL AND s.deleted_at IS NULL
+  AND (sub.sublocation_id IS NULL OR sub.deleted_at IS NULL)
+ORDER BY p.slot, p.is_default DESC, p.starts_at, p.promotion_id;
+
+-- name: UpsertCountry :exec
+INSERT INTO countries (code, name, region_type)
+VALUES ($1, $2, $3)
+ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, region_type = EXCLUDED.region_type;
+
+-- name: UpsertStateBySlug :one
+INSERT INTO states (slug, name, description, country_code, region_type, sort_order)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (slug) DO UPDATE
+SET name = EXCLUDED.name, description = EXCLUDED.description,
+    country_code = EXCLUDED.country_code, region_type = EXCLUDED.region_type,
+    sort_order = EXCLUDED.sort_order, deleted_at = NULL
+RETURNING state_id;
+
+-- name: UpsertSublocationBySlug :one
+INSERT INTO sublocations (state_id, slug, name, description, sort_order)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (state_id, slug) DO UPDATE
+SET name = EXCLUDED.name, description = EXCLUDED.description,
+    sort_order = EXCLUDED.sort_order, deleted_at = NULL
+RETURNING sublocation_id;
+
+-- name: UpsertTagBySlug :exec
+INSERT INTO tags (slug, name)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name;
+
+-- name: GetLiveVideoIDBySrc :one
+SELECT video_id
+FROM videos
+WHERE src = $1 AND deleted_at IS NULL
+ORDER BY video_id
+LIMIT 1;
+
+-- name: ClearVideoStreamIDs :exec
+-- Restores relink streams in two passes so videos can swap them.
+UPDATE videos SET stream_id = NULL
+WHERE video_id = ANY(sqlc.arg(video_ids)::int[]) AND stream_id IS NOT NULL;
+
+-- name: SetVideoStreamID :exec
+UPDATE videos SET stream_id = $2 WHERE video_id = $1;
+
+-- name: GetRestoredPromotionID :one
+-- The promotion a restored one stands for: the slot's default, or a
+-- scheduled promotion of the same video starting at the same time.
+SELECT promotion_id
+FROM promotions
+WHERE slot = sqlc.arg(slot)
+  AND CASE WHEN sqlc.arg(is_default)::bool THEN is_default
+           ELSE NOT is_default AND video_id = sqlc.arg(video_id) AND starts_at = sqlc.narg(starts_at)
+      END
+ORDER BY promotion_id
+LIMIT 1;
+
+-- name: DeleteAllPromotions :execrows
+DELETE FROM promotions;
+
+-- Replace restores prune whatever the backup didn't upsert, trash included.
+-- Rows it did upsert keep their IDs, so references to them survive.
+
+-- name: PruneVideos :execrows
+DELETE FROM videos
+WHERE NOT (video_id = ANY(sqlc.arg(keep)::int[]));
+
+-- name: PruneSublocations :execrows
+DELETE FROM sublocations
+WHERE NOT (sublocation_id = ANY(sqlc.arg(keep)::int[]));
+
+-- name: PruneStates :execrows
+DELETE FROM states
+WHERE NOT (state_id = ANY(sqlc.arg(keep)::int[]));
+
+-- name: PruneTags :execrows
+DELETE FROM tags
+WHERE NOT (slug = ANY(sqlc.arg(keep)::text[]));
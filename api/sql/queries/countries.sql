@@ -0,0 +1,27 @@
+-- name: ListCountries :many
+SELECT c.code, c.name, c.region_type, c.created_at, c.updated_at,
+       (SELECT COUNT(*) FROM states s
+        WHERE s.country_code = c.code AND s.deleted_at IS NULL)::int AS region_count,
+       (SELECT COUNT(*) FROM videos v JOIN states s ON s.state_id = v.state_id
+        WHERE s.country_code = c.code AND s.deleted_at IS NULL
+          AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL)::int AS video_count
+FROM countries c
+ORDER BY c.name;
+
+-- name: GetCountry :one
+SELECT c.code, c.name, c.region_type, c.created_at, c.updated_at,
+       (SELECT COUNT(*) FROM states s
+        WHERE s.country_code = c.code AND s.deleted_at IS NULL)::int AS region_count,
+       (SELECT COUNT(*) FROM videos v JOIN states s ON s.state_id = v.state_id
+        WHERE s.country_code = c.code AND s.deleted_at IS NULL
+          AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL)::int AS video_count
+FROM countries c
+WHERE c.code = $1;
+
+-- name: CreateCountry :one
+INSERT INTO countries (code, name, region_type)
+VALUES ($1, $2, $3)
+RETURNING code, name, region_type, created_at, updated_at;
+
+-- name: UpdateCountry :execrows
+UPDATE countries SET name = $2, region_type = $3 WHERE code = $1;
@@ -0,0 +1,98 @@
+-- name: ListFavoriteVideos :many
+-- A user's listed favorites, most recently added first.
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.featured, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name,
+       f.created_at AS favorited_at
+FROM user_favorites f
+JOIN videos v ON v.video_id = f.video_id
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE f.user_id = $1 AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY f.created_at DESC, v.video_id;
+
+-- name: ListFavoriteVideoIDs :many
+SELECT video_id FROM user_favorites WHERE user_id = $1;
+
+-- name: GetFavorite :one
+SELECT video_id, created_at FROM user_favorites
+WHERE user_id = $1 AND video_id = $2;
+
+-- name: AddFavorite :one
+-- Favorites a listed video; favoriting it again keeps the original time.
+-- No row means the video isn't listed.
+INSERT INTO user_favorites (user_id, video_id)
+SELECT sqlc.arg(user_id), v.video_id FROM videos v
+WHERE v.video_id = sqlc.arg(video_id) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ON CONFLICT (user_id, video_id) DO UPDATE SET created_at = user_favorites.created_at
+RETURNING video_id, created_at;
+
+-- name: DeleteFavorite :execrows
+DELETE FROM user_favorites WHERE user_id = $1 AND video_id = $2;
+
+-- name: ListUserLists :many
+-- A user's lists in their order, with how many listed videos each holds.
+SELECT l.list_id, l.name, l.sort_order, l.created_at, l.updated_at,
+       COUNT(v.video_id)::int AS video_count
+FROM user_lists l
+LEFT JOIN user_list_videos lv ON lv.list_id = l.list_id
+LEFT JOIN videos v ON v.video_id = lv.video_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE l.user_id = $1
+GROUP BY l.list_id
+ORDER BY l.sort_order, l.list_id;
+
+-- name: GetUserList :one
+SELECT list_id, name, sort_order, created_at, updated_at
+FROM user_lists WHERE list_id = $1 AND user_id = $2;
+
+-- name: CountUserLists :one
+SELECT COUNT(*)::int FROM user_lists WHERE user_id = $1;
+
+-- name: CreateUserList :one
+-- New lists go last.
+INSERT INTO user_lists (user_id, name, sort_order)
+VALUES (sqlc.arg(user_id), sqlc.arg(name),
+        (SELECT COALESCE(MAX(sort_order), 0) + 1 FROM user_lists WHERE user_id = sqlc.arg(user_id)))
+RETURNING list_id;
+
+-- name: RenameUserList :exec
+UPDATE user_lists SET name = $2 WHERE list_id = $1;
+
+-- name: DeleteUserList :execrows
+DELETE FROM user_lists WHERE list_id = $1 AND user_id = $2;
+
+-- name: ReorderUserLists :execrows
+-- Sets sort_order to each ID's 1-based position in ids, among the user's lists.
+UPDATE user_lists l SET sort_order = o.position
+FROM unnest(sqlc.arg(ids)::int[]) WITH ORDINALITY AS o(list_id, position)
+WHERE l.list_id = o.list_id AND l.user_id = sqlc.arg(user_id);
+
+-- name: ListUserListVideos :many
+-- A list's listed videos in the user's order.
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.featured, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name
+FROM user_list_videos lv
+JOIN videos v ON v.video_id = lv.video_id
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE lv.list_id = $1 AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY lv.position;
+
+-- name: ClearUserListVideos :exec
+DELETE FROM user_list_videos WHERE list_id = $1;
+
+-- name: AddUserListVideos :execrows
+-- Adds ids to a list at their 1-based positions, skipping any that aren't
+-- listed videos.
+INSERT INTO user_list_videos (list_id, video_id, position)
+SELECT sqlc.arg(list_id), v.video_id, o.position
+FROM unnest(sqlc.arg(ids)::int[]) WITH ORDINALITY AS o(video_id, position)
+JOIN videos v ON v.video_id = o.video_id
+WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL;
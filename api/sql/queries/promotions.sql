@@ -0,0 +1,65 @@
+-- name: ListPromotions :many
+-- Every promotion, optionally for one slot: defaults first, then newest
+-- window first.
+SELECT p.promotion_id, p.slot, p.video_id, p.title, p.starts_at, p.ends_at,
+       p.timezone, p.priority, p.is_default, p.created_by, p.created_at, p.updated_at,
+       v.title AS video_title
+FROM promotions p
+JOIN videos v ON v.video_id = p.video_id
+WHERE sqlc.narg(slot)::text IS NULL OR p.slot = sqlc.narg(slot)
+ORDER BY p.slot, p.is_default DESC, p.starts_at DESC NULLS LAST, p.promotion_id;
+
+-- name: GetPromotion :one
+SELECT p.promotion_id, p.slot, p.video_id, p.title, p.starts_at, p.ends_at,
+       p.timezone, p.priority, p.is_default, p.created_by, p.created_at, p.updated_at,
+       v.title AS video_title
+FROM promotions p
+JOIN videos v ON v.video_id = p.video_id
+WHERE p.promotion_id = $1;
+
+-- name: CreatePromotion :one
+INSERT INTO promotions (slot, video_id, title, starts_at, ends_at, timezone, priority, is_default, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING promotion_id;
+
+-- name: UpdatePromotion :execrows
+UPDATE promotions
+SET slot = $2, video_id = $3, title = $4, starts_at = $5, ends_at = $6,
+    timezone = $7, priority = $8, is_default = $9
+WHERE promotion_id = $1;
+
+-- name: DeletePromotion :execrows
+DELETE FROM promotions WHERE promotion_id = $1;
+
+-- name: GetCurrentPromotion :one
+-- The promotion live in a slot at now: the highest-priority scheduled one
+-- whose window contains now (latest start breaking ties), else the slot's
+-- default. Promotions of unlisted videos are skipped.
+SELECT p.promotion_id, p.slot, p.title, p.starts_at, p.ends_at, p.timezone,
+       p.priority, p.is_default,
+       v.video_id, v.title AS video_title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone AS video_timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name
+FROM promotions p
+JOIN videos v ON v.video_id = p.video_id
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE p.slot = sqlc.arg(slot)
+  AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+  AND (p.is_default OR (p.starts_at <= sqlc.arg(now)::timestamptz
+                        AND (p.ends_at IS NULL OR p.ends_at > sqlc.arg(now)::timestamptz)))
+ORDER BY p.is_default, p.priority DESC, p.starts_at DESC, p.promotion_id DESC
+LIMIT 1;
+
+-- name: GetNextPromotionChange :one
+-- The next instant after now at which a scheduled promotion in the slot
+-- starts or ends, i.e. when GetCurrentPromotion may next change.
+SELECT b.t::timestamptz AS changes_at
+FROM promotions p
+CROSS JOIN LATERAL (VALUES (p.starts_at), (p.ends_at)) AS b(t)
+WHERE p.slot = sqlc.arg(slot) AND NOT p.is_default
+  AND b.t > sqlc.arg(now)::timestamptz
+ORDER BY changes_at
+LIMIT 1;
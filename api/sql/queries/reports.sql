@@ -0,0 +1,57 @@
+-- name: GetListedVideoStatus :one
+SELECT status FROM videos
+WHERE video_id = $1 AND status IN ('published', 'maintenance', 'offline') AND deleted_at IS NULL;
+
+-- name: CreateVideoReport :one
+-- Opens a report, or counts a repeat on the client's open report for this
+-- reason (keeping its first note). inserted is false for a repeat.
+INSERT INTO video_reports (video_id, reason, note, client_hash)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (video_id, client_hash, reason) WHERE resolved_at IS NULL DO UPDATE
+SET report_count = video_reports.report_count + 1,
+    last_reported_at = now(),
+    note = COALESCE(video_reports.note, EXCLUDED.note)
+RETURNING (xmax = 0)::bool AS inserted;
+
+-- name: CountVideoReporters :one
+-- How many distinct clients have open reports on a video.
+SELECT COUNT(DISTINCT client_hash)::int FROM video_reports
+WHERE video_id = $1 AND resolved_at IS NULL;
+
+-- name: FlagReportedVideo :execrows
+-- Puts a published video into maintenance. No row means it wasn't published.
+UPDATE videos SET status = 'maintenance'
+WHERE video_id = $1 AND status = 'published' AND deleted_at IS NULL;
+
+-- name: ListReportedVideos :many
+-- Videos with open reports, most reported first, with counts by reason.
+-- report_count includes repeats; reporter_count is distinct client hashes,
+-- each of which may be many viewers sharing an address.
+SELECT v.video_id, v.title, v.status, s.name AS state_name,
+       SUM(r.report_count)::int AS report_count,
+       COUNT(DISTINCT r.client_hash)::int AS reporter_count,
+       (COUNT(DISTINCT r.client_hash) FILTER (WHERE r.reason = 'offline'))::int AS offline_reporters,
+       (COUNT(DISTINCT r.client_hash) FILTER (WHERE r.reason = 'wrong_location'))::int AS wrong_location_reporters,
+       (COUNT(DISTINCT r.client_hash) FILTER (WHERE r.reason = 'inappropriate'))::int AS inappropriate_reporters,
+       (COALESCE(SUM(r.report_count) FILTER (WHERE r.reason = 'offline'), 0))::int AS offline_count,
+       (COALESCE(SUM(r.report_count) FILTER (WHERE r.reason = 'wrong_location'), 0))::int AS wrong_location_count,
+       (COALESCE(SUM(r.report_count) FILTER (WHERE r.reason = 'inappropriate'), 0))::int AS inappropriate_count,
+       MIN(r.created_at)::timestamptz AS first_reported_at,
+       MAX(r.last_reported_at)::timestamptz AS last_reported_at
+FROM video_reports r
+JOIN videos v ON v.video_id = r.video_id
+JOIN states s ON s.state_id = v.state_id
+WHERE r.resolved_at IS NULL AND v.deleted_at IS NULL
+GROUP BY v.video_id, s.name
+ORDER BY COUNT(DISTINCT r.client_hash) DESC, SUM(r.report_count) DESC, MAX(r.last_reported_at) DESC, v.video_id;
+
+-- name: ListVideoReports :many
+-- A video's open reports, most recently reported first.
+SELECT report_id, video_id, reason, note, client_hash, report_count, created_at, last_reported_at
+FROM video_reports
+WHERE video_id = $1 AND resolved_at IS NULL
+ORDER BY last_reported_at DESC, report_id DESC;
+
+-- name: ResolveVideoReports :execrows
+UPDATE video_reports SET resolved_at = now(), resolved_by = $2
+WHERE video_id = $1 AND resolved_at IS NULL;
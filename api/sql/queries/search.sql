@@ -0,0 +1,32 @@
+-- name: Search :many
+-- Ranked search across states, sublocations and listed videos.
+-- query must be a valid to_tsquery('simple', ...) expression.
+WITH q AS (SELECT to_tsquery('simple', sqlc.arg(query)::text) AS tsq)
+SELECT * FROM (
+  SELECT 'state'::text AS kind, s.state_id AS id, s.name, s.slug,
+         s.description, s.name AS state_name, s.slug AS state_slug,
+         ts_rank(s.search_vector, q.tsq)::float4 AS rank
+  FROM states s, q
+  WHERE s.deleted_at IS NULL AND s.search_vector @@ q.tsq
+
+  UNION ALL
+
+  SELECT 'sublocation'::text, sub.sublocation_id, sub.name, sub.slug,
+         sub.description, st.name, st.slug,
+         ts_rank(sub.search_vector, q.tsq)::float4
+  FROM sublocations sub
+  JOIN states st ON st.state_id = sub.state_id, q
+  WHERE sub.deleted_at IS NULL AND sub.search_vector @@ q.tsq
+
+  UNION ALL
+
+  SELECT 'video'::text, v.video_id, v.title, ''::text,
+         COALESCE(vsub.name, ''), st.name, st.slug,
+         ts_rank(v.search_vector, q.tsq)::float4
+  FROM videos v
+  JOIN states st ON st.state_id = v.state_id
+  LEFT JOIN sublocations vsub ON vsub.sublocation_id = v.sublocation_id AND vsub.deleted_at IS NULL, q
+  WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL AND v.search_vector @@ q.tsq
+) results
+ORDER BY rank DESC, name
+LIMIT sqlc.arg(max_results);
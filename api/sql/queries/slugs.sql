@@ -0,0 +1,5 @@
+-- name: ListSlugHistory :many
+SELECT entity_type, slug, entity_id, created_at
+FROM slug_history
+WHERE entity_type = $1 AND entity_id = $2
+ORDER BY created_at DESC;
@@ -1,44 +1,96 @@
 -- name: ListStates :many
-SELECT s.state_id, s.name, s.description, s.slug, s.created_at, s.updated_at,
+-- The regions of one country; /states lists the US.
+SELECT s.state_id, s.name, s.description, s.slug, s.country_code, s.region_type,
+       s.sort_order, s.created_at, s.updated_at,
        COUNT(v.video_id)::int AS video_count
 FROM states s
-LEFT JOIN videos v ON v.state_id = s.state_id AND v.status = 'active'
+LEFT JOIN videos v ON v.state_id = s.state_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE s.deleted_at IS NULL AND s.country_code = $1
 GROUP BY s.state_id
-ORDER BY s.name;
+ORDER BY s.sort_order NULLS LAST, s.name;
 
 -- name: GetStateBySlug :one
-SELECT s.state_id, s.name, s.description, s.slug, s.created_at, s.updated_at,
+SELECT s.state_id, s.name, s.description, s.slug, s.country_code, s.region_type,
+       s.sort_order, s.created_at, s.updated_at,
        COUNT(v.video_id)::int AS video_count
 FROM states s
-LEFT JOIN videos v ON v.state_id = s.state_id AND v.status = 'active'
-WHERE s.slug = $1
+LEFT JOIN videos v ON v.state_id = s.state_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE s.slug = $1 AND s.deleted_at IS NULL
 GROUP BY s.state_id;
 
 -- name: GetStateByID :one
-SELECT s.state_id, s.name, s.description, s.slug, s.created_at, s.updated_at,
+SELECT s.state_id, s.name, s.description, s.slug, s.country_code, s.region_type,
+       s.sort_order, s.created_at, s.updated_at,
        COUNT(v.video_id)::int AS video_count
 FROM states s
-LEFT JOIN videos v ON v.state_id = s.state_id AND v.status = 'active'
+LEFT JOIN videos v ON v.state_id = s.state_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
 WHERE s.state_id = $1
 GROUP BY s.state_id;
 
 -- name: CreateState :one
-INSERT INTO states (name, description)
-VALUES ($1, $2)
-RETURNING state_id, name, description, slug, created_at, updated_at;
+INSERT INTO states (name, description, slug, country_code, region_type)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING state_id, name, description, slug, country_code, region_type, created_at, updated_at;
 
 -- name: UpdateState :exec
-UPDATE states SET name = $2, description = $3 WHERE state_id = $1;
+UPDATE states SET name = $2, description = $3, region_type = $4 WHERE state_id = $1;
 
--- name: DeleteState :exec
-DELETE FROM states WHERE slug = $1;
+-- name: SoftDeleteState :one
+UPDATE states SET deleted_at = now()
+WHERE slug = $1 AND deleted_at IS NULL
+RETURNING state_id, deleted_at::timestamptz AS deleted_at;
 
--- name: ListStatesPaginated :many
-SELECT s.state_id, s.name, s.description, s.slug, s.created_at, s.updated_at,
-       COUNT(v.video_id)::int AS video_count,
-       COUNT(*) OVER()::int AS total_count
-FROM states s
-LEFT JOIN videos v ON v.state_id = s.state_id AND v.status = 'active'
-GROUP BY s.state_id
-ORDER BY s.name
-LIMIT $1 OFFSET $2;
+-- name: GetStateByRef :one
+-- Resolves a live state by slug or case-insensitive name, preferring a slug match.
+SELECT state_id, name, slug
+FROM states
+WHERE deleted_at IS NULL
+  AND (slug = lower(sqlc.arg(ref)::text) OR lower(name) = lower(sqlc.arg(ref)::text))
+ORDER BY slug = lower(sqlc.arg(ref)::text) DESC
+LIMIT 1;
+
+-- name: GetStateSlugRedirect :one
+-- The current slug of the live state that used to be reachable at slug.
+SELECT s.slug
+FROM slug_history h
+JOIN states s ON s.state_id = h.entity_id
+WHERE h.entity_type = 'state' AND h.slug = $1 AND s.deleted_at IS NULL;
+
+-- name: ChangeStateSlug :one
+UPDATE states SET slug = sqlc.arg(slug)
+WHERE state_id = sqlc.arg(state_id) AND deleted_at IS NULL
+RETURNING slug;
+
+-- name: ListStateSlugs :many
+-- Slugs equal to base or base plus a "-N" suffix, trashed states included
+-- (they still hold their slug). except_id's own slug is left out.
+SELECT slug FROM states
+WHERE (slug = sqlc.arg(base)::text OR slug LIKE sqlc.arg(base)::text || '-%')
+  AND state_id <> sqlc.arg(except_id);
+
+-- name: ReorderStates :one
+-- Puts ids in the given order, in the positions they hold now: states left
+-- out keep theirs, and positions stay unique. Unpositioned states (NULL)
+-- stay so unless listed. Returns how many of ids are live.
+WITH ranked AS (
+  SELECT s.state_id, s.sort_order,
+         ROW_NUMBER() OVER (ORDER BY s.sort_order NULLS LAST, s.name, s.state_id)::int AS slot
+  FROM states s WHERE s.deleted_at IS NULL
+), listed AS (
+  SELECT r.state_id, r.slot, ROW_NUMBER() OVER (ORDER BY o.position) AS rank
+  FROM unnest(sqlc.arg(ids)::int[]) WITH ORDINALITY AS o(state_id, position)
+  JOIN ranked r ON r.state_id = o.state_id
+), slots AS (
+  SELECT slot, ROW_NUMBER() OVER (ORDER BY slot) AS rank FROM listed
+), target AS (
+  SELECT l.state_id, s.slot FROM listed l JOIN slots s ON s.rank = l.rank
+  UNION ALL
+  SELECT r.state_id, r.slot FROM ranked r
+  WHERE r.sort_order IS NOT NULL AND r.state_id NOT IN (SELECT state_id FROM listed)
+), moved AS (
+  UPDATE states s SET sort_order = t.slot
+  FROM target t
+  WHERE s.state_id = t.state_id AND s.sort_order IS DISTINCT FROM t.slot
+  RETURNING s.state_id
+)
+SELECT COUNT(*) FROM listed;
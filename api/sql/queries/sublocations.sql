@@ -1,57 +1,154 @@
 -- name: ListSublocationsByState :many
-SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.slug,
-       sub.created_at, sub.updated_at,
+-- video_count here and below rolls up every live descendant's videos.
+SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.parent_id, sub.slug,
+       sub.sort_order, sub.created_at, sub.updated_at,
        s.name AS state_name,
        COUNT(v.video_id)::int AS video_count
 FROM sublocations sub
 JOIN states s ON s.state_id = sub.state_id
-LEFT JOIN videos v ON v.sublocation_id = sub.sublocation_id AND v.status = 'active'
-WHERE sub.state_id = $1
+LEFT JOIN videos v ON v.sublocation_id IN (SELECT sublocation_subtree(sub.sublocation_id)) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE sub.state_id = $1 AND sub.deleted_at IS NULL
 GROUP BY sub.sublocation_id, s.name
-ORDER BY sub.name;
+ORDER BY sub.sort_order NULLS LAST, sub.name;
 
 -- name: GetSublocationBySlug :one
-SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.slug,
-       sub.created_at, sub.updated_at,
+-- Slugs are unique per state only; state, a state slug, picks between them.
+SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.parent_id, sub.slug,
+       sub.sort_order, sub.created_at, sub.updated_at,
        s.name AS state_name,
        COUNT(v.video_id)::int AS video_count
 FROM sublocations sub
 JOIN states s ON s.state_id = sub.state_id
-LEFT JOIN videos v ON v.sublocation_id = sub.sublocation_id AND v.status = 'active'
-WHERE sub.slug = $1
-GROUP BY sub.sublocation_id, s.name;
+LEFT JOIN videos v ON v.sublocation_id IN (SELECT sublocation_subtree(sub.sublocation_id)) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE sub.slug = sqlc.arg(slug) AND sub.deleted_at IS NULL
+  AND (sqlc.narg(state)::text IS NULL OR s.slug = sqlc.narg(state)::text)
+GROUP BY sub.sublocation_id, s.name
+ORDER BY sub.sublocation_id
+LIMIT 1;
 
 -- name: GetSublocationByID :one
-SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.slug,
-       sub.created_at, sub.updated_at,
+SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.parent_id, sub.slug,
+       sub.sort_order, sub.created_at, sub.updated_at,
        s.name AS state_name,
        COUNT(v.video_id)::int AS video_count
 FROM sublocations sub
 JOIN states s ON s.state_id = sub.state_id
-LEFT JOIN videos v ON v.sublocation_id = sub.sublocation_id AND v.status = 'active'
+LEFT JOIN videos v ON v.sublocation_id IN (SELECT sublocation_subtree(sub.sublocation_id)) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
 WHERE sub.sublocation_id = $1
 GROUP BY sub.sublocation_id, s.name;
 
 -- name: CreateSublocation :one
-INSERT INTO sublocations (name, description, state_id)
-VALUES ($1, $2, $3)
-RETURNING sublocation_id, name, description, state_id, slug, created_at, updated_at;
+INSERT INTO sublocations (name, description, state_id, parent_id, slug)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING sublocation_id, name, description, state_id, parent_id, slug, created_at, updated_at;
 
 -- name: UpdateSublocation :exec
-UPDATE sublocations SET name = $2, description = $3, state_id = $4 WHERE sublocation_id = $1;
+-- A null slug keeps the current one.
+UPDATE sublocations
+SET name = sqlc.arg(name), description = sqlc.arg(description), state_id = sqlc.arg(state_id),
+    parent_id = sqlc.narg(parent_id), slug = COALESCE(sqlc.narg(slug), slug)
+WHERE sublocation_id = sqlc.arg(sublocation_id);
 
--- name: DeleteSublocation :exec
-DELETE FROM sublocations WHERE sublocation_id = $1;
+-- name: SoftDeleteSublocation :execrows
+UPDATE sublocations SET deleted_at = now()
+WHERE sublocation_id = $1 AND deleted_at IS NULL;
 
--- name: ListSublocationsPaginated :many
-SELECT sub.sublocation_id, sub.name, sub.description, sub.state_id, sub.slug,
-       sub.created_at, sub.updated_at,
-       s.name AS state_name,
-       COUNT(v.video_id)::int AS video_count,
-       COUNT(*) OVER()::int AS total_count
-FROM sublocations sub
-JOIN states s ON s.state_id = sub.state_id
-LEFT JOIN videos v ON v.sublocation_id = sub.sublocation_id AND v.status = 'active'
-GROUP BY sub.sublocation_id, s.name
-ORDER BY sub.name
-LIMIT $1 OFFSET $2;
+-- name: SoftDeleteSublocationsByState :exec
+UPDATE sublocations SET deleted_at = sqlc.arg(deleted_at)::timestamptz
+WHERE state_id = sqlc.arg(state_id) AND deleted_at IS NULL;
+
+-- name: GetSublocationByRef :one
+-- Resolves a live sublocation within a state by slug or case-insensitive name,
+-- preferring a slug match.
+SELECT sublocation_id, name, slug
+FROM sublocations
+WHERE deleted_at IS NULL
+  AND state_id = sqlc.arg(state_id)
+  AND (slug = lower(sqlc.arg(ref)::text) OR lower(name) = lower(sqlc.arg(ref)::text))
+ORDER BY slug = lower(sqlc.arg(ref)::text) DESC
+LIMIT 1;
+
+-- name: GetSublocationSlugRedirect :one
+-- The current slug of the live sublocation that used to be reachable at slug,
+-- in state if given (history is kept per state), else the latest renamed.
+SELECT sub.slug
+FROM slug_history h
+JOIN sublocations sub ON sub.sublocation_id = h.entity_id
+JOIN states s ON s.state_id = h.scope_id
+WHERE h.entity_type = 'sublocation' AND h.slug = sqlc.arg(slug) AND sub.deleted_at IS NULL
+  AND (sqlc.narg(state)::text IS NULL OR s.slug = sqlc.narg(state)::text)
+ORDER BY h.created_at DESC
+LIMIT 1;
+
+-- name: ChangeSublocationSlug :one
+UPDATE sublocations SET slug = sqlc.arg(slug)
+WHERE sublocation_id = sqlc.arg(sublocation_id) AND deleted_at IS NULL
+RETURNING slug;
+
+-- name: ListSublocationSlugs :many
+-- ListStateSlugs for the sublocations of one state, where slugs are unique.
+SELECT slug FROM sublocations
+WHERE state_id = sqlc.arg(state_id)
+  AND (slug = sqlc.arg(base)::text OR slug LIKE sqlc.arg(base)::text || '-%')
+  AND sublocation_id <> sqlc.arg(except_id);
+
+-- name: ListSublocationAncestors :many
+-- Breadcrumbs: the live ancestors of a sublocation, root first.
+WITH RECURSIVE up AS (
+  SELECT p.sublocation_id, p.parent_id, p.name, p.slug, 1 AS depth
+  FROM sublocations sub
+  JOIN sublocations p ON p.sublocation_id = sub.parent_id
+  WHERE sub.sublocation_id = $1
+  UNION
+  SELECT p.sublocation_id, p.parent_id, p.name, p.slug, up.depth + 1
+  FROM sublocations p
+  JOIN up ON p.sublocation_id = up.parent_id
+)
+SELECT up.sublocation_id, up.name, up.slug
+FROM up
+JOIN sublocations sub ON sub.sublocation_id = up.sublocation_id
+WHERE sub.deleted_at IS NULL
+ORDER BY up.depth DESC;
+
+-- name: IsSublocationInSubtree :one
+-- Whether candidate is root or one of its descendants, trashed ones included.
+WITH RECURSIVE down AS (
+  SELECT sqlc.arg(root)::int AS sublocation_id
+  UNION
+  SELECT c.sublocation_id FROM sublocations c JOIN down ON c.parent_id = down.sublocation_id
+)
+SELECT EXISTS (SELECT 1 FROM down WHERE sublocation_id = sqlc.arg(candidate)::int);
+
+-- name: CountSublocationChildren :one
+SELECT COUNT(*)::int FROM sublocations
+WHERE parent_id = sqlc.arg(sublocation_id)::int AND deleted_at IS NULL;
+
+-- name: SetSublocationParent :exec
+UPDATE sublocations SET parent_id = sqlc.narg(parent_id) WHERE sublocation_id = sqlc.arg(sublocation_id);
+
+-- name: ReorderSublocations :one
+-- Puts ids in the given order, in the positions they hold now: sublocations left
+-- out keep theirs, and positions stay unique. Unpositioned sublocations (NULL)
+-- stay so unless listed. Returns how many of ids are live.
+WITH ranked AS (
+  SELECT sub.sublocation_id, sub.sort_order,
+         ROW_NUMBER() OVER (ORDER BY sub.sort_order NULLS LAST, sub.name, sub.sublocation_id)::int AS slot
+  FROM sublocations sub WHERE sub.deleted_at IS NULL
+), listed AS (
+  SELECT r.sublocation_id, r.slot, ROW_NUMBER() OVER (ORDER BY o.position) AS rank
+  FROM unnest(sqlc.arg(ids)::int[]) WITH ORDINALITY AS o(sublocation_id, position)
+  JOIN ranked r ON r.sublocation_id = o.sublocation_id
+), slots AS (
+  SELECT slot, ROW_NUMBER() OVER (ORDER BY slot) AS rank FROM listed
+), target AS (
+  SELECT l.sublocation_id, s.slot FROM listed l JOIN slots s ON s.rank = l.rank
+  UNION ALL
+  SELECT r.sublocation_id, r.slot FROM ranked r
+  WHERE r.sort_order IS NOT NULL AND r.sublocation_id NOT IN (SELECT sublocation_id FROM listed)
+), moved AS (
+  UPDATE sublocations sub SET sort_order = t.slot
+  FROM target t
+  WHERE sub.sublocation_id = t.sublocation_id AND sub.sort_order IS DISTINCT FROM t.slot
+  RETURNING sub.sublocation_id
+)
+SELECT COUNT(*) FROM listed;
@@ -0,0 +1,45 @@
+-- name: CreateSubmission :one
+INSERT INTO submissions (title, src, state_id, contact_name, contact_email, notes, client_hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING submission_id;
+
+-- name: CountRecentSubmissions :one
+-- Submissions from one client since a time, for the per-client daily cap.
+SELECT COUNT(*)::int FROM submissions
+WHERE client_hash = $1 AND created_at >= $2;
+
+-- name: ListSubmissions :many
+-- The moderation queue, oldest first, optionally by status. A cursor pages
+-- to newer submissions, or to older ones (in reverse) when backward.
+SELECT sm.submission_id, sm.title, sm.src, sm.state_id, sm.contact_name, sm.contact_email,
+       sm.notes, sm.client_hash, sm.status, sm.reject_reason, sm.reviewed_by, sm.reviewed_at,
+       sm.video_id, sm.created_at, sm.updated_at,
+       s.name AS state_name
+FROM submissions sm
+LEFT JOIN states s ON s.state_id = sm.state_id
+WHERE (sqlc.narg(status)::text IS NULL OR sm.status = sqlc.narg(status)::text)
+  AND (sqlc.narg(cursor_id)::int IS NULL OR
+       CASE WHEN sqlc.arg(backward)::bool THEN sm.submission_id < sqlc.narg(cursor_id)::int
+            ELSE sm.submission_id > sqlc.narg(cursor_id)::int END)
+ORDER BY CASE WHEN sqlc.arg(backward)::bool THEN -sm.submission_id ELSE sm.submission_id END
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountSubmissions :one
+SELECT COUNT(*)::int FROM submissions
+WHERE sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text;
+
+-- name: GetSubmission :one
+SELECT sm.submission_id, sm.title, sm.src, sm.state_id, sm.contact_name, sm.contact_email,
+       sm.notes, sm.client_hash, sm.status, sm.reject_reason, sm.reviewed_by, sm.reviewed_at,
+       sm.video_id, sm.created_at, sm.updated_at,
+       s.name AS state_name
+FROM submissions sm
+LEFT JOIN states s ON s.state_id = sm.state_id
+WHERE sm.submission_id = $1;
+
+-- name: ReviewSubmission :execrows
+-- Approves or rejects a pending submission. No rows means it isn't pending.
+UPDATE submissions
+SET status = sqlc.arg(status), reject_reason = sqlc.narg(reject_reason), video_id = sqlc.narg(video_id),
+    reviewed_by = sqlc.arg(reviewed_by), reviewed_at = now()
+WHERE submission_id = sqlc.arg(submission_id) AND status = 'pending';
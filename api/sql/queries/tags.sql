@@ -0,0 +1,45 @@
+-- name: ListTags :many
+SELECT t.tag_id, t.name, t.slug, t.created_at, t.updated_at,
+       COUNT(v.video_id)::int AS video_count
+FROM tags t
+LEFT JOIN video_tags vt ON vt.tag_id = t.tag_id
+LEFT JOIN videos v ON v.video_id = vt.video_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+GROUP BY t.tag_id
+ORDER BY t.name;
+
+-- name: GetTagByID :one
+SELECT t.tag_id, t.name, t.slug, t.created_at, t.updated_at,
+       COUNT(v.video_id)::int AS video_count
+FROM tags t
+LEFT JOIN video_tags vt ON vt.tag_id = t.tag_id
+LEFT JOIN videos v ON v.video_id = vt.video_id AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+WHERE t.tag_id = $1
+GROUP BY t.tag_id;
+
+-- name: CreateTag :one
+INSERT INTO tags (name, slug)
+VALUES ($1, $2)
+RETURNING tag_id, name, slug, created_at, updated_at;
+
+-- name: UpdateTag :exec
+UPDATE tags SET name = $2 WHERE tag_id = $1;
+
+-- name: DeleteTag :exec
+DELETE FROM tags WHERE tag_id = $1;
+
+-- name: CountTagsBySlug :one
+SELECT COUNT(*)::int FROM tags WHERE slug = ANY(sqlc.arg(slugs)::text[]);
+
+-- name: ClearVideoTags :exec
+DELETE FROM video_tags WHERE video_id = $1;
+
+-- name: AddVideoTags :exec
+INSERT INTO video_tags (video_id, tag_id)
+SELECT sqlc.arg(video_id), t.tag_id FROM tags t WHERE t.slug = ANY(sqlc.arg(slugs)::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: ListTagSlugs :many
+-- ListStateSlugs for tags.
+SELECT slug FROM tags
+WHERE (slug = sqlc.arg(base)::text OR slug LIKE sqlc.arg(base)::text || '-%')
+  AND tag_id <> sqlc.arg(except_id);
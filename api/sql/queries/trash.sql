@@ -0,0 +1,72 @@
+-- name: ListTrash :many
+-- Everything currently soft-deleted, most recent first.
+SELECT * FROM (
+  SELECT 'state'::text AS kind, s.state_id AS id, s.name, ''::text AS state_name,
+         s.deleted_at::timestamptz AS deleted_at
+  FROM states s
+  WHERE s.deleted_at IS NOT NULL
+
+  UNION ALL
+
+  SELECT 'sublocation'::text, sub.sublocation_id, sub.name, st.name,
+         sub.deleted_at::timestamptz
+  FROM sublocations sub
+  JOIN states st ON st.state_id = sub.state_id
+  WHERE sub.deleted_at IS NOT NULL
+
+  UNION ALL
+
+  SELECT 'video'::text, v.video_id, v.title, st.name,
+         v.deleted_at::timestamptz
+  FROM videos v
+  JOIN states st ON st.state_id = v.state_id
+  WHERE v.deleted_at IS NOT NULL
+) trash
+ORDER BY deleted_at DESC, kind, id;
+
+-- name: GetStateDeletedAt :one
+SELECT deleted_at FROM states WHERE state_id = $1;
+
+-- name: GetSublocationDeletedAt :one
+SELECT sub.deleted_at, s.deleted_at AS state_deleted_at,
+       p.deleted_at AS parent_deleted_at
+FROM sublocations sub
+JOIN states s ON s.state_id = sub.state_id
+LEFT JOIN sublocations p ON p.sublocation_id = sub.parent_id
+WHERE sub.sublocation_id = $1;
+
+-- name: GetVideoDeletedAt :one
+SELECT v.deleted_at, s.deleted_at AS state_deleted_at,
+       sub.deleted_at AS sublocation_deleted_at
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
+WHERE v.video_id = $1;
+
+-- name: RestoreState :exec
+UPDATE states SET deleted_at = NULL WHERE state_id = $1;
+
+-- name: RestoreSublocationsByState :exec
+-- Restores only the sublocations trashed together with the state.
+UPDATE sublocations SET deleted_at = NULL
+WHERE state_id = sqlc.arg(state_id) AND deleted_at = sqlc.arg(deleted_at)::timestamptz;
+
+-- name: RestoreVideosByState :exec
+-- Restores only the videos trashed together with the state.
+UPDATE videos SET deleted_at = NULL
+WHERE state_id = sqlc.arg(state_id) AND deleted_at = sqlc.arg(deleted_at)::timestamptz;
+
+-- name: RestoreSublocation :exec
+UPDATE sublocations SET deleted_at = NULL WHERE sublocation_id = $1;
+
+-- name: RestoreVideo :exec
+UPDATE videos SET deleted_at = NULL WHERE video_id = $1;
+
+-- name: PurgeVideos :execrows
+DELETE FROM videos WHERE deleted_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: PurgeSublocations :execrows
+DELETE FROM sublocations WHERE deleted_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: PurgeStates :execrows
+DELETE FROM states WHERE deleted_at < sqlc.arg(cutoff)::timestamptz;
@@ -1,66 +1,289 @@
 -- name: ListVideos :many
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
-       COALESCE(sub.name, '') AS sublocation_name
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
-WHERE v.status = 'active'
-ORDER BY v.title;
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY v.sort_order NULLS LAST, v.title;
+
+-- name: ListFeaturedVideos :many
+-- Listed videos flagged for the homepage hero, in admin order.
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.featured AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY v.sort_order NULLS LAST, v.title;
 
 -- name: ListVideosByState :many
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
-       COALESCE(sub.name, '') AS sublocation_name
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
-WHERE v.state_id = $1 AND v.status = 'active'
-ORDER BY v.title;
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.state_id = $1 AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY v.sort_order NULLS LAST, v.title;
 
 -- name: ListVideosBySublocation :many
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
-       COALESCE(sub.name, '') AS sublocation_name
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
-WHERE v.sublocation_id = $1 AND v.status = 'active'
-ORDER BY v.title;
+JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.sublocation_id = $1 AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY v.sort_order NULLS LAST, v.title;
+
+-- name: ListVideosInSublocationTree :many
+-- Videos in a sublocation or any live sublocation below it.
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.sublocation_id IN (SELECT sublocation_subtree(sqlc.arg(sublocation_id)::int)) AND v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+ORDER BY sub.sort_order NULLS LAST, sub.name, v.sort_order NULLS LAST, v.title;
 
 -- name: GetVideoByID :one
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone, v.stream_id,
+       v.camera_model, v.firmware, v.owner_contact, v.install_date, v.notes,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
-       COALESCE(sub.name, '') AS sublocation_name
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
 WHERE v.video_id = $1;
 
 -- name: CreateVideo :one
-INSERT INTO videos (title, src, type, state_id, sublocation_id, status, created_by)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING video_id, title, src, type, state_id, sublocation_id, status, created_by, created_at, updated_at;
+INSERT INTO videos (title, src, type, state_id, sublocation_id, status, created_by,
+                    latitude, longitude, heading, elevation, stream_id, publish_at, unpublish_at, featured,
+                    timezone, field_of_view, camera_model, firmware, owner_contact, install_date, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+        $16, $17, $18, $19, $20, $21, $22)
+RETURNING video_id, title, src, type, state_id, sublocation_id, status, created_by, created_at, updated_at,
+          latitude, longitude, heading, elevation, stream_id, publish_at, unpublish_at, featured, sort_order;
 
 -- name: UpdateVideo :exec
-UPDATE videos SET title = $2, src = $3, type = $4, state_id = $5, sublocation_id = $6, status = $7 WHERE video_id = $1;
+UPDATE videos SET title = $2, src = $3, type = $4, state_id = $5, sublocation_id = $6, status = $7,
+                  latitude = $8, longitude = $9, heading = $10, elevation = $11,
+                  publish_at = $12, unpublish_at = $13, featured = $14,
+                  timezone = $15, field_of_view = $16, camera_model = $17, firmware = $18,
+                  owner_contact = $19, install_date = $20, notes = $21
+WHERE video_id = $1;
+
+-- name: SoftDeleteVideo :execrows
+UPDATE videos SET deleted_at = now()
+WHERE video_id = $1 AND deleted_at IS NULL;
 
--- name: DeleteVideo :exec
-DELETE FROM videos WHERE video_id = $1;
+-- name: SoftDeleteVideosByState :exec
+UPDATE videos SET deleted_at = sqlc.arg(deleted_at)::timestamptz
+WHERE state_id = sqlc.arg(state_id) AND deleted_at IS NULL;
 
--- name: ListVideosPaginated :many
+-- name: ListVideosNearby :many
+-- Great-circle (haversine) distance from a point, limited to radius_km.
+-- The lat/lon pre-filter lets idx_videos_lat_lon narrow the scan first.
+SELECT * FROM (
+  SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+         v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+         v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+         v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+         v.created_by, v.created_at, v.updated_at,
+         s.name AS state_name,
+         COALESCE(sub.name, '') AS sublocation_name,
+         COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                   JOIN tags t ON t.tag_id = vt.tag_id
+                   WHERE vt.video_id = v.video_id), '{}')::text[] AS tags,
+         (6371 * 2 * asin(LEAST(1, sqrt(
+           power(sin(radians(v.latitude - sqlc.arg(lat)::float8) / 2), 2) +
+           cos(radians(sqlc.arg(lat)::float8)) * cos(radians(v.latitude)) *
+           power(sin(radians(v.longitude - sqlc.arg(lon)::float8) / 2), 2)
+         ))))::float8 AS distance_km
+  FROM videos v
+  JOIN states s ON s.state_id = v.state_id
+  LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+  WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+    AND v.latitude BETWEEN sqlc.arg(min_lat)::float8 AND sqlc.arg(max_lat)::float8
+) nearby
+WHERE distance_km <= sqlc.arg(radius_km)::float8
+ORDER BY distance_km, title;
+
+-- name: ListVideosInBBox :many
+-- Videos inside a bounding box, ordered by distance from the box centre.
 SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
-       v.status, v.created_by, v.created_at, v.updated_at,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
        s.name AS state_name,
        COALESCE(sub.name, '') AS sublocation_name,
-       COUNT(*) OVER()::int AS total_count
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags,
+       (6371 * 2 * asin(LEAST(1, sqrt(
+         power(sin(radians(v.latitude - sqlc.arg(center_lat)::float8) / 2), 2) +
+         cos(radians(sqlc.arg(center_lat)::float8)) * cos(radians(v.latitude)) *
+         power(sin(radians(v.longitude - sqlc.arg(center_lon)::float8) / 2), 2)
+       ))))::float8 AS distance_km
 FROM videos v
 JOIN states s ON s.state_id = v.state_id
-LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id
-WHERE v.status = 'active'
-ORDER BY v.title
-LIMIT $1 OFFSET $2;
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+  AND v.latitude BETWEEN sqlc.arg(min_lat)::float8 AND sqlc.arg(max_lat)::float8
+  AND v.longitude BETWEEN sqlc.arg(min_lon)::float8 AND sqlc.arg(max_lon)::float8
+ORDER BY distance_km, v.title;
+
+-- name: ListVideosByTags :many
+-- Listed videos carrying at least min_matches of the given tag slugs:
+-- len(tags) for AND semantics, 1 for OR. state_id/sublocation_id narrow further.
+SELECT v.video_id, v.title, v.src, v.type, v.state_id, v.sublocation_id,
+       v.latitude, v.longitude, v.heading, v.field_of_view, v.elevation, v.timezone,
+       v.status, CASE WHEN v.status IN ('maintenance', 'offline') THEN v.status ELSE '' END AS badge,
+       v.publish_at, v.unpublish_at, v.featured, v.sort_order,
+       v.created_by, v.created_at, v.updated_at,
+       s.name AS state_name,
+       COALESCE(sub.name, '') AS sublocation_name,
+       COALESCE((SELECT array_agg(t.slug ORDER BY t.slug) FROM video_tags vt
+                 JOIN tags t ON t.tag_id = vt.tag_id
+                 WHERE vt.video_id = v.video_id), '{}')::text[] AS tags
+FROM videos v
+JOIN states s ON s.state_id = v.state_id
+LEFT JOIN sublocations sub ON sub.sublocation_id = v.sublocation_id AND sub.deleted_at IS NULL
+WHERE v.status IN ('published', 'maintenance', 'offline') AND v.deleted_at IS NULL
+  AND (sqlc.narg(state_id)::int IS NULL OR v.state_id = sqlc.narg(state_id)::int)
+  AND (sqlc.narg(sublocation_id)::int IS NULL OR v.sublocation_id = sqlc.narg(sublocation_id)::int)
+  AND v.video_id IN (
+    SELECT vt.video_id FROM video_tags vt
+    JOIN tags t ON t.tag_id = vt.tag_id
+    WHERE t.slug = ANY(sqlc.arg(tags)::text[])
+    GROUP BY vt.video_id
+    HAVING COUNT(*) >= sqlc.arg(min_matches)::int
+  )
+ORDER BY v.sort_order NULLS LAST, v.title;
+
+-- name: DeactivateVideosByStream :many
+UPDATE videos v SET status = 'archived'
+FROM videos old
+WHERE old.video_id = v.video_id AND v.stream_id = $1 AND v.deleted_at IS NULL
+RETURNING v.video_id, old.status AS previous_status;
+
+-- name: SoftDeleteVideosByStream :many
+UPDATE videos SET deleted_at = now()
+WHERE stream_id = $1 AND deleted_at IS NULL
+RETURNING video_id;
+
+-- name: PublishScheduledVideos :many
+UPDATE videos SET status = 'published', publish_at = NULL
+WHERE status = 'draft' AND publish_at <= sqlc.arg(now)::timestamptz AND deleted_at IS NULL
+RETURNING video_id;
+
+-- name: UnpublishScheduledVideos :many
+UPDATE videos SET status = 'archived', unpublish_at = NULL
+WHERE status IN ('published', 'maintenance', 'offline')
+  AND unpublish_at <= sqlc.arg(now)::timestamptz AND deleted_at IS NULL
+RETURNING video_id;
+
+-- name: ListVideosForHealthCheck :many
+-- Videos whose stream the health check probes: published ones that may have
+-- gone offline, and offline ones that may have come back.
+SELECT video_id, src, status
+FROM videos
+WHERE status IN ('published', 'offline') AND deleted_at IS NULL
+ORDER BY video_id;
+
+-- name: SetVideoOnline :execrows
+UPDATE videos SET status = CASE WHEN sqlc.arg(online)::boolean THEN 'published' ELSE 'offline' END
+WHERE video_id = sqlc.arg(video_id)
+  AND status = CASE WHEN sqlc.arg(online)::boolean THEN 'offline' ELSE 'published' END
+  AND deleted_at IS NULL;
+
+-- name: ReorderVideos :one
+-- Puts ids in the given order, in the positions they hold now: videos left
+-- out keep theirs, and positions stay unique. Unpositioned videos (NULL)
+-- stay so unless listed. Returns how many of ids are live.
+WITH ranked AS (
+  SELECT v.video_id, v.sort_order,
+         ROW_NUMBER() OVER (ORDER BY v.sort_order NULLS LAST, v.title, v.video_id)::int AS slot
+  FROM videos v WHERE v.deleted_at IS NULL
+), listed AS (
+  SELECT r.video_id, r.slot, ROW_NUMBER() OVER (ORDER BY o.position) AS rank
+  FROM unnest(sqlc.arg(ids)::int[]) WITH ORDINALITY AS o(video_id, position)
+  JOIN ranked r ON r.video_id = o.video_id
+), slots AS (
+  SELECT slot, ROW_NUMBER() OVER (ORDER BY slot) AS rank FROM listed
+), target AS (
+  SELECT l.video_id, s.slot FROM listed l JOIN slots s ON s.rank = l.rank
+  UNION ALL
+  SELECT r.video_id, r.slot FROM ranked r
+  WHERE r.sort_order IS NOT NULL AND r.video_id NOT IN (SELECT video_id FROM listed)
+), moved AS (
+  UPDATE videos v SET sort_order = t.slot
+  FROM target t
+  WHERE v.video_id = t.video_id AND v.sort_order IS DISTINCT FROM t.slot
+  RETURNING v.video_id
+)
+SELECT COUNT(*) FROM listed;
+
+-- name: SetVideoSortOrder :exec
+UPDATE videos SET sort_order = $2 WHERE video_id = $1;
+
+-- name: GetListedVideoTimezone :one
+SELECT timezone FROM videos
+WHERE video_id = $1 AND status IN ('published', 'maintenance', 'offline') AND deleted_at IS NULL;
+
+-- name: CheckVideoPlace :one
+-- Whether a state, and a sublocation within it when given, exist and aren't
+-- trashed: where a new video may go.
+SELECT EXISTS (SELECT 1 FROM states s
+               WHERE s.state_id = sqlc.arg(state_id)::int AND s.deleted_at IS NULL) AS state_ok,
+       (sqlc.narg(sublocation_id)::int IS NULL OR
+        EXISTS (SELECT 1 FROM sublocations sub
+                WHERE sub.sublocation_id = sqlc.narg(sublocation_id)::int
+                  AND sub.state_id = sqlc.arg(state_id)::int AND sub.deleted_at IS NULL))::bool AS sublocation_ok;
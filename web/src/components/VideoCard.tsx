@@ -22,7 +22,7 @@ export default function VideoCard({
   video,
   showLocation = false,
 }: VideoCardProps) {
-  const isActive = video.status === 'active'
+  const isActive = video.status === 'published'
 
   return (
     <article className="reveal-scale group relative flex flex-col overflow-hidden rounded-2xl border border-overlay0/60 bg-surface0 shadow-md ring-1 ring-black/[0.03] transition-all duration-350 ease-[var(--spring-snappy)] hover:-translate-y-1 hover:border-accent/40 hover:shadow-xl hover:ring-accent/10 dark:ring-white/[0.02]">
@@ -41,6 +41,11 @@ export default function VideoCard({
         {isActive && (
           <LiveBadge className="absolute top-3 left-3 z-10 shadow-sm" />
         )}
+        {video.badge && (
+          <span className="absolute top-3 left-3 z-10 rounded-md bg-surface1/90 px-2.5 py-1 font-mono text-xs font-semibold tracking-wider text-subtext1 uppercase shadow-sm">
+            {video.badge}
+          </span>
+        )}
       </div>
 
       {/* ── Card body ── */}
@@ -179,8 +179,12 @@ export async function fetchSublocationsByState(
 
 export async function fetchSublocationBySlug(
   slug: string,
+  stateSlug: string,
 ): Promise<Sublocation> {
-  return get<Sublocation>(`/sublocations/${slug}`)
+  // Sublocation slugs are only unique within a state.
+  return get<Sublocation>(
+    `/sublocations/${slug}?state=${encodeURIComponent(stateSlug)}`,
+  )
 }
 
 export async function createSublocation(
@@ -269,7 +273,7 @@ export async function createVideo(
       type: input.type,
       state_id: input.state_id,
       sublocation_id: input.sublocation_id ?? null,
-      status: input.status ?? 'active',
+      status: input.status ?? 'published',
     },
     token,
   )
@@ -288,7 +292,8 @@ export async function updateVideo(
       type: input.type,
       state_id: input.state_id,
       sublocation_id: input.sublocation_id ?? null,
-      status: input.status ?? 'active',
+      // Left out, the API keeps the current status (drafts stay drafts).
+      status: input.status,
     },
     token,
   )
@@ -20,6 +20,13 @@ export interface Sublocation {
   video_count: number
 }
 
+export type VideoStatus =
+  | 'draft'
+  | 'published'
+  | 'maintenance'
+  | 'offline'
+  | 'archived'
+
 export interface Video {
   video_id: number
   title: string
@@ -27,7 +34,31 @@ export interface Video {
   type: string
   state_id: number
   sublocation_id: number | null
-  status: 'active' | 'inactive'
+  status: VideoStatus
+  /** 'maintenance' or 'offline' for listed videos that aren't playing normally. */
+  badge: '' | 'maintenance' | 'offline'
+  publish_at: string | null
+  unpublish_at: string | null
+  featured: boolean
+  /** Admin display order; null sorts after ordered videos, by title. */
+  sort_order: number | null
+  /** IANA timezone of the camera, e.g. "America/Chicago". */
+  timezone: string | null
+  /** Horizontal field of view in degrees. */
+  field_of_view: number | null
+  /** Admin responses only. */
+  camera_model?: string | null
+  firmware?: string | null
+  owner_contact?: string | null
+  /** YYYY-MM-DD. */
+  install_date?: string | null
+  notes?: string | null
+  /** Public listings: whether it is between civil dawn and dusk at the camera. Null without coordinates. */
+  is_daylight?: boolean | null
+  /** Public listings: the next sunrise, in the camera's timezone. */
+  next_sunrise?: string | null
+  /** Public listings, signed in: whether the user has favorited it. */
+  is_favorite?: boolean
   created_by: string
   created_at: string
   updated_at: string
@@ -77,9 +108,14 @@ export interface UpdateVideoInput {
 
 export interface PaginatedResponse<T> {
   data: Array<T>
-  total: number
-  page: number
+  /** Present for numbered pages, or with ?total=true. */
+  total?: number
+  /** Absent when paging by cursor. */
+  page?: number
   per_page: number
+  /** Opaque ?cursor= tokens; absent at either end of the list. */
+  next_cursor?: string
+  prev_cursor?: string
 }
 
 /* ──── Streams (Restreamer) ──── */
@@ -80,10 +80,16 @@ const VIDEO_TYPE_LABELS: Record<string, string> = {
 }
 
 const STATUS_OPTIONS = [
-  { value: 'active', label: 'Active' },
-  { value: 'inactive', label: 'Inactive' },
+  { value: 'draft', label: 'Draft' },
+  { value: 'published', label: 'Published' },
+  { value: 'maintenance', label: 'Maintenance' },
+  { value: 'archived', label: 'Archived' },
 ]
 
+// Offline is set by the API's stream health check, so it's only offered
+// when editing a video that is already offline.
+const OFFLINE_OPTION = { value: 'offline', label: 'Offline' }
+
 const PER_PAGE = 20
 
 const ENTITY_SORT_OPTIONS: Array<{
@@ -477,7 +483,7 @@ function CamerasPanel({
   const [type, setType] = useState('')
   const [stateId, setStateId] = useState<number | ''>('')
   const [sublocationId, setSublocationId] = useState<number | ''>('')
-  const [status, setStatus] = useState('active')
+  const [status, setStatus] = useState('published')
   const [submitting, setSubmitting] = useState(false)
   const [msg, setMsg] = useState<FormMsg>(null)
 
@@ -568,7 +574,7 @@ function CamerasPanel({
       setType('')
       setStateId('')
       setSublocationId('')
-      setStatus('active')
+      setStatus('published')
       onSuccess()
     } catch {
       setMsg({ text: 'Failed to add camera.', ok: false })
@@ -1638,7 +1644,10 @@ function VideoRow({
   onDelete: () => void
 }) {
   const typeLabel = VIDEO_TYPE_LABELS[video.type] ?? video.type
-  const isActive = video.status === 'active'
+  const isActive = video.status === 'published'
+  const statusLabel =
+    STATUS_OPTIONS.find((o) => o.value === video.status)?.label ??
+    OFFLINE_OPTION.label
 
   return (
     <div
@@ -1667,7 +1676,7 @@ function VideoRow({
       </div>
 
       {/* Status */}
-      <StatusDot active={isActive} label={isActive ? 'Live' : 'Off'} />
+      <StatusDot active={isActive} label={isActive ? 'Live' : statusLabel} />
 
       {/* Actions */}
       <div className="flex shrink-0 items-center gap-1 opacity-0 transition-opacity duration-150 group-hover:opacity-100">
@@ -2115,7 +2124,7 @@ function EditVideoModal({
   const [type, setType] = useState(video.type)
   const [stateId, setStateId] = useState<number>(video.state_id)
   const [sublocationId, setSublocationId] = useState<number | ''>(video.sublocation_id ?? '')
-  const [status, setStatus] = useState(video.status)
+  const [status, setStatus] = useState<string>(video.status)
   const [submitting, setSubmitting] = useState(false)
   const [msg, setMsg] = useState<FormMsg>(null)
   useAutoHide(msg, setMsg)
@@ -2168,7 +2177,7 @@ function EditVideoModal({
             onSelect={(v) => setSublocationId(Number(v))}
           />
         )}
-        <Dropdown label="Status" options={STATUS_OPTIONS} selectedValue={status} onSelect={(v) => setStatus(String(v))} />
+        <Dropdown label="Status" options={video.status === 'offline' ? [...STATUS_OPTIONS, OFFLINE_OPTION] : STATUS_OPTIONS} selectedValue={status} onSelect={(v) => setStatus(String(v))} />
         <FormFooter msg={msg} submitting={submitting} label="Save Changes" />
       </form>
     </ModalShell>
@@ -26,7 +26,7 @@ function SublocationPage() {
   useEffect(() => {
     async function fetchData() {
       try {
-        const matched = await fetchSublocationBySlug(sublocationSlug)
+        const matched = await fetchSublocationBySlug(sublocationSlug, slug)
         setSublocation(matched)
 
         const subVideos = await fetchVideosBySublocation(matched.sublocation_id)
@@ -38,7 +38,7 @@ function SublocationPage() {
       }
     }
     fetchData()
-  }, [sublocationSlug])
+  }, [slug, sublocationSlug])
 
   const { search, setSearch, sort, setSort, filtered } =
     useCameraFilter(videos)